@@ -8,7 +8,7 @@ param (
     [string]$Out = "out"
 )
 
-$ComputeTypes = @("managed-ec2", "anywhere", "managed-containers")
+$ComputeTypes = @("managed-ec2", "anywhere", "managed-containers", "local")
 $AppName = "amazon-gamelift-servers-game-server-wrapper"
 $AppPackage = "github.com/amazon-gamelift/$AppName"
 $GoOS = "windows"
@@ -74,6 +74,10 @@ function Build {
 
         Copy-Item "$OutFolder\$AppName\$AppName$FileExt" "$ComputeTypeOutFolder\$AppName$FileExt" -Force
         Copy-Item "src\template\template-$Type-config.yaml" "$ComputeTypeOutFolder\config.yaml" -Force
+
+        if ($Type -eq "local") {
+            Copy-Item "src\template\template-local-scenario.yaml" "$ComputeTypeOutFolder\scenario.yaml" -Force
+        }
     }
 
     if ($Type -eq "managed-containers") {
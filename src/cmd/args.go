@@ -52,6 +52,18 @@ func bindFlags() {
 		logger.Error("Failed to bind flag to viper", "flag", "location", "error", err)
 	}
 
+	rootCmd.PersistentFlags().StringVar(&cfgWrapper.Anywhere.Fleet, "fleet", "", "anywhere fleet id, name or alias, instead of the fleet arn")
+	err = viperInstance.BindPFlag("anywhere.fleet", rootCmd.PersistentFlags().Lookup("fleet"))
+	if err != nil {
+		logger.Error("Failed to bind flag to viper", "flag", "fleet", "error", err)
+	}
+
+	rootCmd.PersistentFlags().StringVar(&cfgWrapper.Anywhere.Location, "location", "", "anywhere fleet custom location name, instead of the location arn")
+	err = viperInstance.BindPFlag("anywhere.location", rootCmd.PersistentFlags().Lookup("location"))
+	if err != nil {
+		logger.Error("Failed to bind flag to viper", "flag", "location", "error", err)
+	}
+
 	rootCmd.PersistentFlags().StringVarP(&cfgWrapper.Anywhere.AuthToken, "auth-token", "t", "", "anywhere fleet auth token")
 	err = viperInstance.BindPFlag("anywhere.auth-token", rootCmd.PersistentFlags().Lookup("auth-token"))
 	if err != nil {
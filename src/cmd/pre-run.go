@@ -6,14 +6,19 @@
 package cmd
 
 import (
+	"fmt"
 	"io"
 	"log/slog"
 	"os"
+	"strconv"
 
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/internal"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/internal/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/constants"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/logging"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/network"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/observability"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
@@ -32,6 +37,11 @@ func preRun(cmd *cobra.Command, args []string) error {
 		return errors.Wrapf(err, "failed to process configuration")
 	}
 
+	keptCompute, err := adaptConfigToInstance()
+	if err != nil {
+		return errors.Wrapf(err, "failed to process configuration")
+	}
+
 	logLevel, err := parseLogLevel(cfg.LogLevel)
 	if err != nil {
 		logger.Warn("Invalid log level was specified. Defaulting to Debug level.",
@@ -58,6 +68,9 @@ func preRun(cmd *cobra.Command, args []string) error {
 
 	observability.SetLogger(slogHandler)
 	cfg.Observability.Name = internal.AppName()
+	if cfg.Observability.Network, err = network.NewSettings(cfg.Network); err != nil {
+		return errors.Wrap(err, "failed to load network settings")
+	}
 	observabilityProviderInstance, err := observability.NewProvider(ctx, &cfg.Observability)
 	if err != nil {
 		return err
@@ -72,5 +85,45 @@ func preRun(cmd *cobra.Command, args []string) error {
 
 	logger = slog.New(contextHandler)
 
+	if cfg.Network.ConnectTimeout > 0 && cfg.Observability.Enabled && len(cfg.Observability.Endpoint) != 0 {
+		logger.WarnContext(ctx, "network.connect-timeout does not apply to the OTLP exporters, only network.request-timeout bounds their requests",
+			"connectTimeout", cfg.Network.ConnectTimeout, "endpoint", cfg.Observability.Endpoint)
+	}
+
+	if keptCompute {
+		logger.InfoContext(ctx, "the instances share the compute name, keeping the compute registered when the instance stops",
+			"deregisterCompute", cfg.Hosting.GameLift.Anywhere.Host.DeregisterCompute)
+	}
+
+	if !network.IsDefault(cfg.Network) || len(cfg.Network.GameLiftEndpoint) != 0 {
+		logger.InfoContext(ctx, "using network settings", "network", network.LogValue(cfg.Network))
+	}
+
 	return nil
 }
+
+// adaptConfigToInstance narrows the configuration when the wrapper is one of the instances run by the supervisor.
+func adaptConfigToInstance() (bool, error) {
+	indexValue, ok := os.LookupEnv(constants.EnvironmentKeyInstanceIndex)
+	if !ok {
+		return false, nil
+	}
+
+	index, err := strconv.Atoi(indexValue)
+	if err != nil {
+		return false, errors.Wrapf(err, "%s is not a number", constants.EnvironmentKeyInstanceIndex)
+	}
+	count, err := strconv.Atoi(os.Getenv(constants.EnvironmentKeyInstanceCount))
+	if err != nil {
+		return false, errors.Wrapf(err, "%s is not a number", constants.EnvironmentKeyInstanceCount)
+	}
+
+	// a process ID given to the supervisor is shared by its instances, each needs its own
+	if processId, ok := os.LookupEnv(common.EnvironmentKeyProcessID); ok && len(processId) != 0 {
+		if err := os.Setenv(common.EnvironmentKeyProcessID, fmt.Sprintf("%s-%d", processId, index)); err != nil {
+			return false, errors.Wrapf(err, "failed to set %s", common.EnvironmentKeyProcessID)
+		}
+	}
+
+	return config.AdaptConfigToInstance(&cfg, index, count)
+}
@@ -48,6 +48,9 @@ var (
 	obs                   *observability.Observability
 	observabilityProvider *observability.Provider
 
+	// drained is set when the wrapper exits after being drained, so the supervisor does not restart it
+	drained bool
+
 	rootCmd = &cobra.Command{
 		PersistentPreRunE: preRun,
 		RunE:              run,
@@ -119,6 +122,18 @@ func run(cmd *cobra.Command, args []string) error {
 	ctx, cancel := addSyscallInterrupt(ctx)
 	defer cancel()
 
+	if svcs.Drainer != nil {
+		if err := svcs.Drainer.Start(ctx, cancel); err != nil {
+			return errors.Wrapf(err, "failed to start drain triggers")
+		}
+		defer func() {
+			if err := svcs.Drainer.Close(context.WithoutCancel(ctx)); err != nil {
+				logger.WarnContext(ctx, "failed to close drain triggers", "err", err)
+			}
+			drained = svcs.Drainer.Draining()
+		}()
+	}
+
 	return wrp.Run(ctx)
 }
 
@@ -152,6 +167,10 @@ func Execute() {
 	if exit1 {
 		os.Exit(1)
 	}
+
+	if drained {
+		os.Exit(constants.ExitCodeDrained)
+	}
 }
 
 func setupLogging(ctx context.Context) (context.Context, error) {
@@ -0,0 +1,91 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/constants"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/drain"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/process"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/supervisor"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var (
+	supervisorInstances int
+
+	supervisorCmd = &cobra.Command{
+		Use:   "supervisor",
+		Short: "run several wrapper instances",
+		Long:  "Runs several wrapper instances on this host, each a child process with its own Amazon GameLift connection, process ID, ports and run log directory, and restarts them when they exit",
+		RunE:  supervisorE,
+	}
+)
+
+func supervisorE(cmd *cobra.Command, args []string) error {
+	if _, ok := os.LookupEnv(constants.EnvironmentKeyInstanceIndex); ok {
+		return errors.New("the supervisor cannot run inside a wrapper instance")
+	}
+
+	supervisorConfig := cfg.Supervisor
+	if cmd.Flags().Changed("instances") {
+		supervisorConfig.Instances = supervisorInstances
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return errors.Wrap(err, "failed to find the wrapper executable")
+	}
+
+	s, err := supervisor.New(supervisorConfig, supervisor.Command{
+		Path: executable,
+		Args: instanceArgs(),
+		Env:  os.Environ(),
+	}, logger, obs.Meter)
+	if err != nil {
+		return errors.Wrap(err, "failed to create supervisor")
+	}
+
+	ctx, cancel := addSyscallInterrupt(cmd.Context())
+	defer cancel()
+
+	if sig, err := process.ParseSignal(drain.Signal); err == nil {
+		drains := make(chan os.Signal, 1)
+		signal.Notify(drains, sig)
+		defer signal.Stop(drains)
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-drains:
+					s.Drain()
+				}
+			}
+		}()
+	}
+
+	return s.Run(ctx)
+}
+
+// instanceArgs passes the flags given to the supervisor on to the instances.
+func instanceArgs() []string {
+	args := make([]string, 0)
+	rootCmd.PersistentFlags().Visit(func(flag *pflag.Flag) {
+		args = append(args, fmt.Sprintf("--%s=%s", flag.Name, flag.Value.String()))
+	})
+	return args
+}
+
+func init() {
+	supervisorCmd.Flags().IntVar(&supervisorInstances, "instances", 0, "number of wrapper instances, overrides supervisor.instances")
+	rootCmd.AddCommand(supervisorCmd)
+}
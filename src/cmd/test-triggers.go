@@ -0,0 +1,104 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	pkgconfig "github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/triggers"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+var (
+	testTriggersLogFile   string
+	testTriggersRulesFile string
+	testTriggersStream    string
+
+	testTriggersCmd = &cobra.Command{
+		Use:   "test-triggers",
+		Short: "test log trigger rules",
+		Long:  "Runs the log trigger rules against a sample game server log file and prints the actions they would take, without taking them",
+		RunE:  testTriggersE,
+	}
+)
+
+func testTriggersE(cmd *cobra.Command, args []string) error {
+	logTriggers := cfg.LogTriggers
+	if len(testTriggersRulesFile) != 0 {
+		logTriggers = pkgconfig.LogTriggers{RulesFile: testTriggersRulesFile}
+	}
+
+	stream := pkgconfig.TriggerStream(testTriggersStream)
+	if stream != pkgconfig.TriggerStreamStdout && stream != pkgconfig.TriggerStreamStderr {
+		return errors.Errorf("stream must be '%s' or '%s'", pkgconfig.TriggerStreamStdout, pkgconfig.TriggerStreamStderr)
+	}
+
+	// without actions the engine is only used to match, so nothing is done on behalf of the rules
+	engine, err := triggers.New(logTriggers, nil, logger, noop.NewMeterProvider().Meter("test-triggers"))
+	if err != nil {
+		return errors.Wrap(err, "failed to load log trigger rules")
+	}
+	if len(engine.Rules()) == 0 {
+		return errors.New("no log trigger rules configured")
+	}
+
+	f, err := os.Open(testTriggersLogFile)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open log file '%s'", testTriggersLogFile)
+	}
+	defer f.Close()
+
+	out := cmd.OutOrStdout()
+	counts := make(map[string]int)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		for _, match := range engine.Match(stream, scanner.Text()) {
+			counts[match.Rule.Name]++
+			fmt.Fprintf(out, "line %d: rule '%s' -> %s%s\n", n, match.Rule.Name, match.Rule.Action, formatCaptures(match.Captures))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.Wrapf(err, "failed to read log file '%s'", testTriggersLogFile)
+	}
+
+	fmt.Fprintln(out, "matches per rule:")
+	for _, rule := range engine.Rules() {
+		fmt.Fprintf(out, "  %s: %d\n", rule.Name, counts[rule.Name])
+	}
+
+	return nil
+}
+
+func formatCaptures(captures map[string]string) string {
+	if len(captures) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, 0, len(captures))
+	for name, value := range captures {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", name, value))
+	}
+	sort.Strings(pairs)
+
+	return " (" + strings.Join(pairs, ", ") + ")"
+}
+
+func init() {
+	testTriggersCmd.Flags().StringVar(&testTriggersLogFile, "log", "", "sample game server log file")
+	testTriggersCmd.Flags().StringVar(&testTriggersRulesFile, "rules", "", "rules file to test instead of the configured rules")
+	testTriggersCmd.Flags().StringVar(&testTriggersStream, "stream", string(pkgconfig.TriggerStreamStdout), "stream the log file was written to, stdout or stderr")
+	_ = testTriggersCmd.MarkFlagRequired("log")
+
+	rootCmd.AddCommand(testTriggersCmd)
+}
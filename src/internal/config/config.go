@@ -7,52 +7,229 @@ package config
 
 import (
 	"fmt"
+	"math"
+	"net"
+	"net/netip"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	"github.com/go-playground/validator/v10"
 
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/logcollector"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/observability"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/ports"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/process"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/triggers"
 )
 
 // Config represents the main configuration structure for the game server wrapper.
 type Config struct {
-	Observability observability.Config `mapstructure:"observability" yaml:"observability, omitempty"`
-	LogLevel      string               `mapstructure:"logLevel" yaml:"logLevel"`
-	BuildDetail   BuildDetail          `mapstructure:"buildDetail" yaml:"buildDetail" json:"buildDetail"`
-	Ports         Ports                `mapstructure:"ports" yaml:"ports, omitempty"`
-	Hosting       Hosting              `mapstructure:"hosting" yaml:"hosting"`
+	Observability     observability.Config     `mapstructure:"observability" yaml:"observability, omitempty"`
+	LogLevel          string                   `mapstructure:"logLevel" yaml:"logLevel"`
+	BuildDetail       BuildDetail              `mapstructure:"buildDetail" yaml:"buildDetail" json:"buildDetail"`
+	Ports             Ports                    `mapstructure:"ports" yaml:"ports, omitempty"`
+	Hosting           Hosting                  `mapstructure:"hosting" yaml:"hosting"`
+	LogTriggers       config.LogTriggers       `mapstructure:"logTriggers" yaml:"logTriggers"`
+	TerminationNotice config.TerminationNotice `mapstructure:"terminationNotice" yaml:"terminationNotice"`
+	PortAllocation    config.PortAllocation    `mapstructure:"portAllocation" yaml:"portAllocation"`
+	Supervisor        config.Supervisor        `mapstructure:"supervisor" yaml:"supervisor"`
+	Drain             config.Drain             `mapstructure:"drain" yaml:"drain"`
+	Network           config.Network           `mapstructure:"network" yaml:"network"`
 }
 
 // ConfigWrapper provides a wrapper configuration structure for additional
 // server configuration options, particularly for Amazon GameLift Anywhere setup.
 type ConfigWrapper struct {
-	LogConfig         LogConfig         `mapstructure:"log-config" yaml:"log-config"`
-	Anywhere          AnywhereConfig    `mapstructure:"anywhere" yaml:"anywhere"`
-	Ports             Ports             `mapstructure:"ports" yaml:"ports, omitempty"`
-	GameServerDetails GameServerDetails `mapstructure:"game-server-details" yaml:"game-server-details"`
+	LogConfig            LogConfig                       `mapstructure:"log-config" yaml:"log-config"`
+	Provider             config.Provider                 `mapstructure:"provider" yaml:"provider"`
+	Anywhere             AnywhereConfig                  `mapstructure:"anywhere" yaml:"anywhere"`
+	Local                LocalConfig                     `mapstructure:"local" yaml:"local"`
+	Agones               AgonesConfig                    `mapstructure:"agones" yaml:"agones"`
+	Ports                Ports                           `mapstructure:"ports" yaml:"ports, omitempty"`
+	GameServerDetails    GameServerDetails               `mapstructure:"game-server-details" yaml:"game-server-details"`
+	LogTriggers          LogTriggersConfig               `mapstructure:"log-triggers" yaml:"log-triggers"`
+	FleetRoleCredentials FleetRoleCredentialsConfig      `mapstructure:"fleet-role-credentials" yaml:"fleet-role-credentials"`
+	ComputeCertificate   config.ComputeCertificatePolicy `mapstructure:"compute-certificate" yaml:"compute-certificate"`
+	SdkInit              config.SdkInitMode              `mapstructure:"sdk-init" yaml:"sdk-init"`
+	TerminationNotice    TerminationNoticeConfig         `mapstructure:"termination-notice" yaml:"termination-notice"`
+	SdkRetry             SdkRetryConfig                  `mapstructure:"sdk-retry" yaml:"sdk-retry"`
+	Supervisor           SupervisorConfig                `mapstructure:"supervisor" yaml:"supervisor"`
+	PlayerTracking       PlayerTrackingConfig            `mapstructure:"player-tracking" yaml:"player-tracking"`
+	Drain                DrainConfig                     `mapstructure:"drain" yaml:"drain"`
+	Network              NetworkConfig                   `mapstructure:"network" yaml:"network"`
+}
+
+// NetworkConfig defines how the wrapper reaches the Amazon GameLift API and the telemetry collector.
+type NetworkConfig struct {
+	GameLiftEndpoint string             `mapstructure:"gamelift-endpoint" yaml:"gamelift-endpoint"`
+	Proxy            NetworkProxyConfig `mapstructure:"proxy" yaml:"proxy"`
+	CaBundle         string             `mapstructure:"ca-bundle" yaml:"ca-bundle"`
+	ConnectTimeout   time.Duration      `mapstructure:"connect-timeout" yaml:"connect-timeout"`
+	RequestTimeout   time.Duration      `mapstructure:"request-timeout" yaml:"request-timeout"`
+}
+
+// NetworkProxyConfig defines the HTTP proxies used instead of the ones of the environment.
+type NetworkProxyConfig struct {
+	Http    string `mapstructure:"http" yaml:"http"`
+	Https   string `mapstructure:"https" yaml:"https"`
+	NoProxy string `mapstructure:"no-proxy" yaml:"no-proxy"`
 }
 
 // LogConfig defines logging-specific configuration options.
 type LogConfig struct {
-	WrapperLogLevel   string `mapstructure:"wrapper-log-level" yaml:"wrapper-log-level"`
-	GameServerLogsDir string `mapstructure:"game-server-logs-dir" yaml:"game-server-logs-dir"`
+	WrapperLogLevel   string                   `mapstructure:"wrapper-log-level" yaml:"wrapper-log-level"`
+	GameServerLogsDir string                   `mapstructure:"game-server-logs-dir" yaml:"game-server-logs-dir"`
+	GameServerLogs    GameServerLogsCollection `mapstructure:"game-server-logs" yaml:"game-server-logs"`
+	LogPaths          []string                 `mapstructure:"log-paths" yaml:"log-paths"`
+}
+
+// GameServerLogsCollection defines which game server logs are collected into the run log directory at shutdown.
+// Sizes are a number of bytes, optionally followed by KB, MB or GB.
+type GameServerLogsCollection struct {
+	Include      []string `mapstructure:"include" yaml:"include"`
+	Exclude      []string `mapstructure:"exclude" yaml:"exclude"`
+	MaxFileSize  string   `mapstructure:"max-file-size" yaml:"max-file-size"`
+	MaxTotalSize string   `mapstructure:"max-total-size" yaml:"max-total-size"`
+	Compress     bool     `mapstructure:"compress" yaml:"compress"`
 }
 
 // AnywhereConfig defines Amazon GameLift Anywhere specific configuration settings.
 type AnywhereConfig struct {
-	Profile            string                   `mapstructure:"profile" yaml:"profile"`
-	Provider           config.AwsConfigProvider `mapstructure:"provider" yaml:"provider"`
-	ComputeName        string                   `mapstructure:"compute-name" yaml:"compute-name"`
-	ServiceSdkEndpoint string                   `mapstructure:"service-sdk-endpoint" yaml:"service-sdk-endpoint"`
-	AuthToken          string                   `mapstructure:"auth-token" yaml:"auth-token"`
-	LocationArn        string                   `mapstructure:"location-arn" yaml:"location-arn"`
-	FleetArn           string                   `mapstructure:"fleet-arn" yaml:"fleet-arn"`
-	IPv4               string                   `mapstructure:"ipv4" yaml:"ipv4"`
+	Profile              string                            `mapstructure:"profile" yaml:"profile"`
+	Provider             config.AwsConfigProvider          `mapstructure:"provider" yaml:"provider"`
+	ComputeName          string                            `mapstructure:"compute-name" yaml:"compute-name"`
+	ServiceSdkEndpoint   string                            `mapstructure:"service-sdk-endpoint" yaml:"service-sdk-endpoint"`
+	AuthToken            string                            `mapstructure:"auth-token" yaml:"auth-token"`
+	LocationArn          string                            `mapstructure:"location-arn" yaml:"location-arn"`
+	FleetArn             string                            `mapstructure:"fleet-arn" yaml:"fleet-arn"`
+	Fleet                string                            `mapstructure:"fleet" yaml:"fleet"`
+	Location             string                            `mapstructure:"location" yaml:"location"`
+	IPv4                 string                            `mapstructure:"ipv4" yaml:"ipv4"`
+	IPv6                 string                            `mapstructure:"ipv6" yaml:"ipv6"`
+	IpDiscovery          IpDiscoveryConfig                 `mapstructure:"ip-discovery" yaml:"ip-discovery"`
+	DeregisterCompute    config.DeregisterComputeMode      `mapstructure:"deregister-compute" yaml:"deregister-compute"`
+	ComputeNameTemplate  string                            `mapstructure:"compute-name-template" yaml:"compute-name-template"`
+	ComputeNameCollision config.ComputeNameCollisionPolicy `mapstructure:"compute-name-collision" yaml:"compute-name-collision"`
+	Literal              LiteralCredentialsConfig          `mapstructure:"literal" yaml:"literal"`
+	CredentialProcess    string                            `mapstructure:"credential-process" yaml:"credential-process"`
+	WebIdentity          WebIdentityConfig                 `mapstructure:"web-identity" yaml:"web-identity"`
+	SSOFileRefresh       SSOFileRefreshConfig              `mapstructure:"sso-file-refresh" yaml:"sso-file-refresh"`
+}
+
+// SSOFileRefreshConfig defines how the credentials of the sso-file provider are watched for expiry and refreshed.
+type SSOFileRefreshConfig struct {
+	Command       string        `mapstructure:"command" yaml:"command"`
+	RefreshBefore time.Duration `mapstructure:"refresh-before" yaml:"refresh-before"`
+	WarnBefore    time.Duration `mapstructure:"warn-before" yaml:"warn-before"`
+	ProbeInterval time.Duration `mapstructure:"probe-interval" yaml:"probe-interval"`
+}
+
+// LiteralCredentialsConfig defines where the static AWS credentials of the literal provider are read from.
+type LiteralCredentialsConfig struct {
+	File               string `mapstructure:"file" yaml:"file"`
+	AccessKeyIdEnv     string `mapstructure:"access-key-id-env" yaml:"access-key-id-env"`
+	SecretAccessKeyEnv string `mapstructure:"secret-access-key-env" yaml:"secret-access-key-env"`
+	SessionTokenEnv    string `mapstructure:"session-token-env" yaml:"session-token-env"`
+}
+
+// WebIdentityConfig defines the role assumed by the web-identity provider.
+type WebIdentityConfig struct {
+	RoleArn         string `mapstructure:"role-arn" yaml:"role-arn"`
+	TokenFile       string `mapstructure:"token-file" yaml:"token-file"`
+	RoleSessionName string `mapstructure:"role-session-name" yaml:"role-session-name"`
+}
+
+// IpDiscoveryConfig defines how the IP address registered for an Amazon GameLift Anywhere compute is found.
+type IpDiscoveryConfig struct {
+	Source        config.AddressSource `mapstructure:"source" yaml:"source"`
+	Interface     string               `mapstructure:"interface" yaml:"interface"`
+	Cidr          string               `mapstructure:"cidr" yaml:"cidr"`
+	Family        config.AddressFamily `mapstructure:"family" yaml:"family"`
+	CheckInterval time.Duration        `mapstructure:"check-interval" yaml:"check-interval"`
+}
+
+// LocalConfig defines the settings of the local offline hosting provider.
+type LocalConfig struct {
+	ScenarioFile        string        `mapstructure:"scenario-file" yaml:"scenario-file"`
+	ApiAddress          string        `mapstructure:"api-address" yaml:"api-address"`
+	HealthCheckInterval time.Duration `mapstructure:"health-check-interval" yaml:"health-check-interval"`
+	ResultsFile         string        `mapstructure:"results-file" yaml:"results-file"`
+}
+
+// AgonesConfig defines the settings of the Agones hosting provider.
+type AgonesConfig struct {
+	SdkAddress          string        `mapstructure:"sdk-address" yaml:"sdk-address"`
+	HealthCheckInterval time.Duration `mapstructure:"health-check-interval" yaml:"health-check-interval"`
+	PortName            string        `mapstructure:"port-name" yaml:"port-name"`
+	MetadataPrefix      string        `mapstructure:"metadata-prefix" yaml:"metadata-prefix"`
+}
+
+// LogTriggersConfig defines the rules applied to the output of the game server.
+type LogTriggersConfig struct {
+	WaitForReady bool                 `mapstructure:"wait-for-ready" yaml:"wait-for-ready"`
+	RulesFile    string               `mapstructure:"rules-file" yaml:"rules-file"`
+	Rules        []config.TriggerRule `mapstructure:"rules" yaml:"rules"`
+}
+
+// FleetRoleCredentialsConfig defines the loopback endpoint serving the fleet role credentials to the game server.
+type FleetRoleCredentialsConfig struct {
+	RoleArn         string        `mapstructure:"role-arn" yaml:"role-arn"`
+	RoleSessionName string        `mapstructure:"role-session-name" yaml:"role-session-name"`
+	Address         string        `mapstructure:"address" yaml:"address"`
+	RefreshBefore   time.Duration `mapstructure:"refresh-before" yaml:"refresh-before"`
+}
+
+// SdkRetryConfig defines how failed InitSDK and ProcessReady calls are retried.
+type SdkRetryConfig struct {
+	Attempts       int           `mapstructure:"attempts" yaml:"attempts"`
+	InitialBackoff time.Duration `mapstructure:"initial-backoff" yaml:"initial-backoff"`
+	MaxBackoff     time.Duration `mapstructure:"max-backoff" yaml:"max-backoff"`
+	Jitter         float64       `mapstructure:"jitter" yaml:"jitter"`
+	CallTimeout    time.Duration `mapstructure:"call-timeout" yaml:"call-timeout"`
+}
+
+// PlayerTrackingConfig defines where the players of the game session are reported from, and whether new player
+// sessions are denied while the game session is full.
+type PlayerTrackingConfig struct {
+	ApiAddress             string        `mapstructure:"api-address" yaml:"api-address"`
+	ConnectionPollInterval time.Duration `mapstructure:"connection-poll-interval" yaml:"connection-poll-interval"`
+	EnforceCapacity        bool          `mapstructure:"enforce-capacity" yaml:"enforce-capacity"`
+}
+
+// SupervisorConfig defines how the supervisor command runs several wrapper instances on the host.
+type SupervisorConfig struct {
+	Instances       int           `mapstructure:"instances" yaml:"instances"`
+	StartInterval   time.Duration `mapstructure:"start-interval" yaml:"start-interval"`
+	StopTimeout     time.Duration `mapstructure:"stop-timeout" yaml:"stop-timeout"`
+	RestartDelay    time.Duration `mapstructure:"restart-delay" yaml:"restart-delay"`
+	MaxRestartDelay time.Duration `mapstructure:"max-restart-delay" yaml:"max-restart-delay"`
+	StatusInterval  time.Duration `mapstructure:"status-interval" yaml:"status-interval"`
+}
+
+// DrainConfig defines the file and admin endpoint that drain the wrapper for maintenance.
+type DrainConfig struct {
+	File         string        `mapstructure:"file" yaml:"file"`
+	PollInterval time.Duration `mapstructure:"poll-interval" yaml:"poll-interval"`
+	AdminAddress string        `mapstructure:"admin-address" yaml:"admin-address"`
+}
+
+// TerminationNoticeConfig defines how the game server is told about a scheduled termination before it is stopped.
+type TerminationNoticeConfig struct {
+	Enabled      bool          `mapstructure:"enabled" yaml:"enabled"`
+	Margin       time.Duration `mapstructure:"margin" yaml:"margin"`
+	File         string        `mapstructure:"file" yaml:"file"`
+	StdinCommand string        `mapstructure:"stdin-command" yaml:"stdin-command"`
+	Signal       string        `mapstructure:"signal" yaml:"signal"`
 }
 
 // GameServerDetails contains configuration details for the game server executable.
@@ -179,12 +356,109 @@ func AdaptConfigWrapperToConfig(configWrapper *ConfigWrapper, cfg *Config) error
 		return fmt.Errorf("error making game server logs path absolute: %v", err)
 	}
 
+	portAllocation, err := getPortAllocationAndValidate(&configWrapper.Ports)
+	if err != nil {
+		return fmt.Errorf("error validating ports config: %v", err)
+	}
+
+	logCollection, err := getLogCollectionAndValidate(&configWrapper.LogConfig.GameServerLogs)
+	if err != nil {
+		return fmt.Errorf("error validating game server logs config: %v", err)
+	}
+
+	logPaths, err := getLogPathsAndValidate(absWorkingDir, configWrapper.LogConfig.LogPaths)
+	if err != nil {
+		return fmt.Errorf("error validating log paths config: %v", err)
+	}
+
 	anywhereAwsRegion, err := getAnywhereRegionAndValidate(&configWrapper.Anywhere)
 	if err != nil {
 		return fmt.Errorf("error validating anywhere config: %v", err)
 	}
 
+	// the sso-file provider takes the path of the credentials file in anywhere.profile
+	var ssoFile string
+	if configWrapper.Anywhere.Provider == config.AwsConfigProviderSSOFile {
+		ssoFile = configWrapper.Anywhere.Profile
+	}
+
+	provider, err := getProviderAndValidate(configWrapper.Provider, &configWrapper.Local, &configWrapper.Agones)
+	if err != nil {
+		return fmt.Errorf("error validating provider config: %v", err)
+	}
+
+	scenarioFile, err := makeAbsolutePath(absWorkingDir, configWrapper.Local.ScenarioFile)
+	if err != nil {
+		return fmt.Errorf("error making local scenario path absolute: %v", err)
+	}
+
+	resultsFile, err := makeAbsolutePath(absWorkingDir, configWrapper.Local.ResultsFile)
+	if err != nil {
+		return fmt.Errorf("error making local results path absolute: %v", err)
+	}
+
+	if err := validateFleetRoleCredentials(&configWrapper.FleetRoleCredentials, provider, &configWrapper.Anywhere); err != nil {
+		return fmt.Errorf("error validating fleet role credentials config: %v", err)
+	}
+
+	if err := validateComputeCertificate(configWrapper.ComputeCertificate, provider, &configWrapper.Anywhere); err != nil {
+		return fmt.Errorf("error validating compute certificate config: %v", err)
+	}
+
+	logTriggers, err := getLogTriggersAndValidate(absWorkingDir, &configWrapper.LogTriggers)
+	if err != nil {
+		return fmt.Errorf("error validating log triggers config: %v", err)
+	}
+
+	network, err := getNetworkAndValidate(absWorkingDir, &configWrapper.Network)
+	if err != nil {
+		return fmt.Errorf("error validating network config: %v", err)
+	}
+
+	sdkInit, err := getSdkInitAndValidate(configWrapper.SdkInit)
+	if err != nil {
+		return fmt.Errorf("error validating sdk init config: %v", err)
+	}
+
+	if err := validateSdkRetry(&configWrapper.SdkRetry); err != nil {
+		return fmt.Errorf("error validating sdk retry config: %v", err)
+	}
+
+	terminationNotice, err := getTerminationNoticeAndValidate(absWorkingDir, &configWrapper.TerminationNotice)
+	if err != nil {
+		return fmt.Errorf("error validating termination notice config: %v", err)
+	}
+
+	if err := validatePlayerTracking(&configWrapper.PlayerTracking, provider); err != nil {
+		return fmt.Errorf("error validating player tracking config: %v", err)
+	}
+
+	if err := validateSupervisor(&configWrapper.Supervisor); err != nil {
+		return fmt.Errorf("error validating supervisor config: %v", err)
+	}
+
+	if err := validateDrain(&configWrapper.Drain); err != nil {
+		return fmt.Errorf("error validating drain config: %v", err)
+	}
+
 	cfg.LogLevel = configWrapper.LogConfig.WrapperLogLevel
+	cfg.LogTriggers = logTriggers
+	cfg.TerminationNotice = terminationNotice
+	cfg.PortAllocation = portAllocation
+	cfg.Supervisor = config.Supervisor{
+		Instances:       configWrapper.Supervisor.Instances,
+		StartInterval:   configWrapper.Supervisor.StartInterval,
+		StopTimeout:     configWrapper.Supervisor.StopTimeout,
+		RestartDelay:    configWrapper.Supervisor.RestartDelay,
+		MaxRestartDelay: configWrapper.Supervisor.MaxRestartDelay,
+		StatusInterval:  configWrapper.Supervisor.StatusInterval,
+	}
+	cfg.Drain = config.Drain{
+		File:         configWrapper.Drain.File,
+		PollInterval: configWrapper.Drain.PollInterval,
+		AdminAddress: configWrapper.Drain.AdminAddress,
+	}
+	cfg.Network = network
 	cfg.BuildDetail = BuildDetail{
 		WorkingDir:      absWorkingDir,
 		RelativeExePath: relExePath,
@@ -197,13 +471,66 @@ func AdaptConfigWrapperToConfig(configWrapper *ConfigWrapper, cfg *Config) error
 		Hosting: config.Hosting{
 			LogDirectory:                   absWorkingDir,
 			AbsoluteGameServerLogDirectory: gameServerLogsDir,
+			LogCollection:                  logCollection,
+			LogPaths:                       logPaths,
+		},
+		Provider: provider,
+		Local: config.Local{
+			ScenarioFile:        scenarioFile,
+			ApiAddress:          configWrapper.Local.ApiAddress,
+			HealthCheckInterval: configWrapper.Local.HealthCheckInterval,
+			ResultsFile:         resultsFile,
+		},
+		Agones: config.Agones{
+			SdkAddress:          configWrapper.Agones.SdkAddress,
+			HealthCheckInterval: configWrapper.Agones.HealthCheckInterval,
+			PortName:            configWrapper.Agones.PortName,
+			MetadataPrefix:      configWrapper.Agones.MetadataPrefix,
 		},
 		GameLift: config.GameLift{
+			ComputeCertificate: configWrapper.ComputeCertificate,
+			SdkRetry: config.SdkRetry{
+				Attempts:       configWrapper.SdkRetry.Attempts,
+				InitialBackoff: configWrapper.SdkRetry.InitialBackoff,
+				MaxBackoff:     configWrapper.SdkRetry.MaxBackoff,
+				Jitter:         configWrapper.SdkRetry.Jitter,
+				CallTimeout:    configWrapper.SdkRetry.CallTimeout,
+			},
+			PlayerTracking: config.PlayerTracking{
+				ApiAddress:             configWrapper.PlayerTracking.ApiAddress,
+				ConnectionPollInterval: configWrapper.PlayerTracking.ConnectionPollInterval,
+				EnforceCapacity:        configWrapper.PlayerTracking.EnforceCapacity,
+			},
+			FleetRoleCredentials: config.FleetRoleCredentials{
+				RoleArn:         configWrapper.FleetRoleCredentials.RoleArn,
+				RoleSessionName: configWrapper.FleetRoleCredentials.RoleSessionName,
+				Address:         configWrapper.FleetRoleCredentials.Address,
+				RefreshBefore:   configWrapper.FleetRoleCredentials.RefreshBefore,
+			},
 			Anywhere: config.Anywhere{
 				Config: config.AwsConfig{
 					Region:   anywhereAwsRegion,
 					Provider: configWrapper.Anywhere.Provider,
 					Profile:  configWrapper.Anywhere.Profile,
+					SSOFile:  ssoFile,
+					Literal: config.AwsConfigLiteral{
+						File:               configWrapper.Anywhere.Literal.File,
+						AccessKeyIdEnv:     configWrapper.Anywhere.Literal.AccessKeyIdEnv,
+						SecretAccessKeyEnv: configWrapper.Anywhere.Literal.SecretAccessKeyEnv,
+						SessionTokenEnv:    configWrapper.Anywhere.Literal.SessionTokenEnv,
+					},
+					CredentialProcess: configWrapper.Anywhere.CredentialProcess,
+					WebIdentity: config.AwsConfigWebIdentity{
+						RoleArn:         configWrapper.Anywhere.WebIdentity.RoleArn,
+						TokenFile:       configWrapper.Anywhere.WebIdentity.TokenFile,
+						RoleSessionName: configWrapper.Anywhere.WebIdentity.RoleSessionName,
+					},
+					SSOFileRefresh: config.AwsConfigSSOFileRefresh{
+						Command:       configWrapper.Anywhere.SSOFileRefresh.Command,
+						RefreshBefore: configWrapper.Anywhere.SSOFileRefresh.RefreshBefore,
+						WarnBefore:    configWrapper.Anywhere.SSOFileRefresh.WarnBefore,
+						ProbeInterval: configWrapper.Anywhere.SSOFileRefresh.ProbeInterval,
+					},
 				},
 				Host: config.AnywhereHostConfig{
 					HostName:           configWrapper.Anywhere.ComputeName,
@@ -211,8 +538,24 @@ func AdaptConfigWrapperToConfig(configWrapper *ConfigWrapper, cfg *Config) error
 					AuthToken:          configWrapper.Anywhere.AuthToken,
 					LocationArn:        configWrapper.Anywhere.LocationArn,
 					FleetArn:           configWrapper.Anywhere.FleetArn,
+					Fleet:              configWrapper.Anywhere.Fleet,
+					Location:           configWrapper.Anywhere.Location,
 					IPv4Address:        configWrapper.Anywhere.IPv4,
+					IPv6Address:        configWrapper.Anywhere.IPv6,
+					AddressDiscovery: config.AddressDiscovery{
+						Source:        configWrapper.Anywhere.IpDiscovery.Source,
+						Interface:     configWrapper.Anywhere.IpDiscovery.Interface,
+						Cidr:          configWrapper.Anywhere.IpDiscovery.Cidr,
+						Family:        configWrapper.Anywhere.IpDiscovery.Family,
+						CheckInterval: configWrapper.Anywhere.IpDiscovery.CheckInterval,
+					},
+					DeregisterCompute:    configWrapper.Anywhere.DeregisterCompute,
+					ComputeNameTemplate:  configWrapper.Anywhere.ComputeNameTemplate,
+					ComputeNameCollision: configWrapper.Anywhere.ComputeNameCollision,
+					GamePort:             configWrapper.Ports.GamePort,
 				},
+				SdkInit: sdkInit,
+				Network: network,
 			},
 		},
 	}
@@ -220,16 +563,460 @@ func AdaptConfigWrapperToConfig(configWrapper *ConfigWrapper, cfg *Config) error
 	return nil
 }
 
+func validateFleetRoleCredentials(credentialsConfig *FleetRoleCredentialsConfig, provider config.Provider, anywhereConfig *AnywhereConfig) error {
+	if credentialsConfig.RoleArn == "" {
+		if credentialsConfig.RoleSessionName != "" || credentialsConfig.Address != "" || credentialsConfig.RefreshBefore != 0 {
+			return fmt.Errorf("fleet-role-credentials.role-arn must be provided to serve fleet role credentials")
+		}
+		return nil
+	}
+
+	if provider != config.ProviderGameLift || anywhereConfig.FleetArn != "" || anywhereConfig.Fleet != "" || anywhereConfig.ComputeName != "" {
+		return fmt.Errorf("fleet role credentials are only available on managed Amazon GameLift fleets")
+	}
+
+	if _, err := arn.Parse(credentialsConfig.RoleArn); err != nil {
+		return fmt.Errorf("fleet-role-credentials.role-arn is not a valid arn: %v", err)
+	}
+
+	if n := len(credentialsConfig.RoleSessionName); n != 0 && (n < 2 || n > 64) {
+		return fmt.Errorf("fleet-role-credentials.role-session-name must be between 2 and 64 characters")
+	}
+
+	if credentialsConfig.RefreshBefore < 0 {
+		return fmt.Errorf("fleet-role-credentials.refresh-before must not be negative")
+	}
+
+	return nil
+}
+
+func validateComputeCertificate(policy config.ComputeCertificatePolicy, provider config.Provider, anywhereConfig *AnywhereConfig) error {
+	switch policy {
+	case "", config.ComputeCertificateDisabled:
+		return nil
+	case config.ComputeCertificateOptional, config.ComputeCertificateRequired:
+	default:
+		return fmt.Errorf("compute-certificate must be one of '%s', '%s' or '%s'", config.ComputeCertificateDisabled, config.ComputeCertificateOptional, config.ComputeCertificateRequired)
+	}
+
+	if provider != config.ProviderGameLift || anywhereConfig.FleetArn != "" || anywhereConfig.Fleet != "" || anywhereConfig.ComputeName != "" {
+		return fmt.Errorf("compute certificates are only available on managed Amazon GameLift fleets")
+	}
+
+	return nil
+}
+
+// customLocationPattern matches the names Amazon GameLift accepts for custom locations.
+var customLocationPattern = regexp.MustCompile(`^custom-[A-Za-z0-9\-]+$`)
+
+var portNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func getPortAllocationAndValidate(portsConfig *Ports) (config.PortAllocation, error) {
+	allocation := config.PortAllocation{}
+
+	if len(portsConfig.GamePortRange) != 0 {
+		if portsConfig.GamePort != 0 {
+			return config.PortAllocation{}, fmt.Errorf("ports.gamePort and ports.gamePortRange cannot both be set")
+		}
+
+		gamePortRange, err := parsePortRange(portsConfig.GamePortRange)
+		if err != nil {
+			return config.PortAllocation{}, fmt.Errorf("ports.gamePortRange is not valid: %v", err)
+		}
+		allocation.Game = gamePortRange
+	}
+
+	if len(portsConfig.Named) != 0 {
+		allocation.Named = make(map[string]config.PortRange, len(portsConfig.Named))
+	}
+	for name, value := range portsConfig.Named {
+		if !portNamePattern.MatchString(name) {
+			return config.PortAllocation{}, fmt.Errorf("ports.named '%s' must only contain letters, digits and underscores", name)
+		}
+		if strings.EqualFold(name, "game") {
+			return config.PortAllocation{}, fmt.Errorf("ports.named cannot contain 'game', use ports.gamePort or ports.gamePortRange")
+		}
+
+		portRange, err := parsePortRange(value)
+		if err != nil {
+			return config.PortAllocation{}, fmt.Errorf("ports.named.%s is not valid: %v", name, err)
+		}
+		allocation.Named[name] = portRange
+	}
+
+	return allocation, nil
+}
+
+// parsePortRange parses a single port, or an inclusive range of ports such as 27015-27030.
+func parsePortRange(value string) (config.PortRange, error) {
+	from, to, isRange := strings.Cut(strings.TrimSpace(value), "-")
+
+	fromPort, err := strconv.Atoi(strings.TrimSpace(from))
+	if err != nil {
+		return config.PortRange{}, fmt.Errorf("'%s' is not a port or a port range", value)
+	}
+
+	toPort := fromPort
+	if isRange {
+		toPort, err = strconv.Atoi(strings.TrimSpace(to))
+		if err != nil {
+			return config.PortRange{}, fmt.Errorf("'%s' is not a port or a port range", value)
+		}
+	}
+
+	if fromPort <= 0 || toPort >= 65535 {
+		return config.PortRange{}, fmt.Errorf("'%s' is not between 1 and 65534", value)
+	}
+	if fromPort > toPort {
+		return config.PortRange{}, fmt.Errorf("'%s' ends before it starts", value)
+	}
+
+	return config.PortRange{From: fromPort, To: toPort}, nil
+}
+
+func getLogPathsAndValidate(absWorkingDir string, paths []string) ([]string, error) {
+	logPaths := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if strings.TrimSpace(p) == "" {
+			return nil, fmt.Errorf("log-config.log-paths must not contain empty paths")
+		}
+
+		absPath, err := makeAbsolutePath(absWorkingDir, p)
+		if err != nil {
+			return nil, fmt.Errorf("error making log path '%s' absolute: %v", p, err)
+		}
+
+		if _, err := filepath.Match(absPath, ""); err != nil {
+			return nil, fmt.Errorf("log-config.log-paths '%s' is not a valid glob: %v", p, err)
+		}
+
+		logPaths = append(logPaths, absPath)
+	}
+
+	return logPaths, nil
+}
+
+func getLogCollectionAndValidate(logsConfig *GameServerLogsCollection) (config.LogCollection, error) {
+	if err := logcollector.ValidatePatterns(logsConfig.Include); err != nil {
+		return config.LogCollection{}, fmt.Errorf("log-config.game-server-logs.include is not valid: %v", err)
+	}
+
+	if err := logcollector.ValidatePatterns(logsConfig.Exclude); err != nil {
+		return config.LogCollection{}, fmt.Errorf("log-config.game-server-logs.exclude is not valid: %v", err)
+	}
+
+	maxFileSize, err := parseSize(logsConfig.MaxFileSize)
+	if err != nil {
+		return config.LogCollection{}, fmt.Errorf("log-config.game-server-logs.max-file-size is not valid: %v", err)
+	}
+
+	maxTotalSize, err := parseSize(logsConfig.MaxTotalSize)
+	if err != nil {
+		return config.LogCollection{}, fmt.Errorf("log-config.game-server-logs.max-total-size is not valid: %v", err)
+	}
+
+	return config.LogCollection{
+		Include:      logsConfig.Include,
+		Exclude:      logsConfig.Exclude,
+		MaxFileSize:  maxFileSize,
+		MaxTotalSize: maxTotalSize,
+		Compress:     logsConfig.Compress,
+	}, nil
+}
+
+// parseSize parses a number of bytes with an optional KB, MB or GB suffix, an empty size is 0.
+func parseSize(size string) (int64, error) {
+	size = strings.ToUpper(strings.TrimSpace(size))
+	if len(size) == 0 {
+		return 0, nil
+	}
+
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix     string
+		multiplier int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}} {
+		if trimmed, ok := strings.CutSuffix(size, unit.suffix); ok {
+			size = strings.TrimSpace(trimmed)
+			multiplier = unit.multiplier
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(size, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("'%s' is not a size", size)
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("size must not be negative")
+	}
+	if n > math.MaxInt64/multiplier {
+		return 0, fmt.Errorf("size is too large")
+	}
+	return n * multiplier, nil
+}
+
+func validatePlayerTracking(playerTrackingConfig *PlayerTrackingConfig, provider config.Provider) error {
+	if playerTrackingConfig.ConnectionPollInterval < 0 {
+		return fmt.Errorf("player-tracking.connection-poll-interval cannot be negative")
+	}
+	if playerTrackingConfig.ConnectionPollInterval > 0 && runtime.GOOS != "linux" {
+		return fmt.Errorf("player-tracking.connection-poll-interval is only supported on Linux")
+	}
+	if len(playerTrackingConfig.ApiAddress) != 0 && provider != config.ProviderGameLift {
+		return fmt.Errorf("player-tracking.api-address is not supported by the %s provider", provider)
+	}
+	return nil
+}
+
+func validateSupervisor(supervisorConfig *SupervisorConfig) error {
+	if supervisorConfig.Instances < 0 {
+		return fmt.Errorf("supervisor.instances cannot be negative")
+	}
+	if supervisorConfig.StartInterval < 0 || supervisorConfig.StopTimeout < 0 || supervisorConfig.RestartDelay < 0 ||
+		supervisorConfig.MaxRestartDelay < 0 || supervisorConfig.StatusInterval < 0 {
+		return fmt.Errorf("supervisor durations cannot be negative")
+	}
+	return nil
+}
+
+func validateDrain(drainConfig *DrainConfig) error {
+	if drainConfig.PollInterval < 0 {
+		return fmt.Errorf("drain.poll-interval cannot be negative")
+	}
+	return nil
+}
+
+// AdaptConfigToInstance narrows the configuration to one of the wrapper instances run by the supervisor.
+// The instance gets its share of the port ranges, or the static game port offset by its index, and its own
+// compute identity file. When the compute name is the same for every instance, the instances share the
+// Anywhere compute and an instance only deregisters it when anywhere.deregister-compute is 'always'.
+//
+// Parameters:
+//   - cfg: The configuration of the host, updated in place
+//   - index: The index of the instance, from 0
+//   - count: The number of instances run by the supervisor
+//
+// Returns:
+//   - bool: Whether anywhere.deregister-compute was changed to 'keep' as the instances share the compute
+//   - error: An error if a port range has fewer ports than instances
+func AdaptConfigToInstance(cfg *Config, index int, count int) (bool, error) {
+	portAllocation, gamePort, err := ports.ForInstance(cfg.PortAllocation, cfg.Ports.GamePort, index, count)
+	if err != nil {
+		return false, fmt.Errorf("error splitting ports between %d instances: %v", count, err)
+	}
+
+	drainAdminAddress, err := offsetPort(cfg.Drain.AdminAddress, index)
+	if err != nil {
+		return false, fmt.Errorf("error offsetting drain.admin-address for instance %d: %v", index, err)
+	}
+
+	cfg.PortAllocation = portAllocation
+	cfg.Ports.GamePort = gamePort
+	cfg.Drain.AdminAddress = drainAdminAddress
+	cfg.Hosting.GameLift.Anywhere.Host.GamePort = gamePort
+	cfg.Hosting.GameLift.Anywhere.Host.InstanceIndex = index
+
+	host := &cfg.Hosting.GameLift.Anywhere.Host
+	if !sharesComputeName(host) || host.DeregisterCompute == config.DeregisterComputeAlways ||
+		host.DeregisterCompute == config.DeregisterComputeKeep {
+		return false, nil
+	}
+	host.DeregisterCompute = config.DeregisterComputeKeep
+	return true, nil
+}
+
+// instanceTemplateFields matches the compute name template fields that differ between the instances of a supervisor.
+var instanceTemplateFields = regexp.MustCompile(`\.(Index|InstanceId)\b`)
+
+// sharesComputeName reports whether every instance of a supervisor renders the same compute name, so
+// they register a single compute. A fixed compute name or a template without a per-instance field is shared.
+func sharesComputeName(host *config.AnywhereHostConfig) bool {
+	return len(host.HostName) != 0 || !instanceTemplateFields.MatchString(host.ComputeNameTemplate)
+}
+
+// offsetPort adds the offset to the port of the address. An empty address, or port 0 which picks a free port,
+// is kept as it is.
+func offsetPort(address string, offset int) (string, error) {
+	if len(address) == 0 {
+		return address, nil
+	}
+
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", err
+	}
+	portNumber, err := strconv.Atoi(port)
+	if err != nil {
+		return "", fmt.Errorf("invalid port '%s'", port)
+	}
+	if portNumber == 0 {
+		return address, nil
+	}
+	return net.JoinHostPort(host, strconv.Itoa(portNumber+offset)), nil
+}
+
+func getNetworkAndValidate(absWorkingDir string, networkConfig *NetworkConfig) (config.Network, error) {
+	urls := []struct {
+		key   string
+		value string
+	}{
+		{key: "network.gamelift-endpoint", value: networkConfig.GameLiftEndpoint},
+		{key: "network.proxy.http", value: networkConfig.Proxy.Http},
+		{key: "network.proxy.https", value: networkConfig.Proxy.Https},
+	}
+	for _, u := range urls {
+		if u.value == "" {
+			continue
+		}
+		parsed, err := url.Parse(u.value)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return config.Network{}, fmt.Errorf("%s must be an http or https url", u.key)
+		}
+	}
+
+	if networkConfig.ConnectTimeout < 0 || networkConfig.RequestTimeout < 0 {
+		return config.Network{}, fmt.Errorf("network timeouts must not be negative")
+	}
+
+	caBundle, err := makeAbsolutePath(absWorkingDir, networkConfig.CaBundle)
+	if err != nil {
+		return config.Network{}, fmt.Errorf("error making ca bundle path absolute: %v", err)
+	}
+
+	return config.Network{
+		GameLiftEndpoint: networkConfig.GameLiftEndpoint,
+		Proxy: config.NetworkProxy{
+			Http:    networkConfig.Proxy.Http,
+			Https:   networkConfig.Proxy.Https,
+			NoProxy: networkConfig.Proxy.NoProxy,
+		},
+		CaBundle:       caBundle,
+		ConnectTimeout: networkConfig.ConnectTimeout,
+		RequestTimeout: networkConfig.RequestTimeout,
+	}, nil
+}
+
+func getSdkInitAndValidate(mode config.SdkInitMode) (config.SdkInitMode, error) {
+	switch mode {
+	case "":
+		return config.SdkInitAuto, nil
+	case config.SdkInitAuto, config.SdkInitConfig, config.SdkInitEnvironment:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("sdk-init must be one of '%s', '%s' or '%s'", config.SdkInitAuto, config.SdkInitConfig, config.SdkInitEnvironment)
+	}
+}
+
+func validateSdkRetry(retryConfig *SdkRetryConfig) error {
+	if retryConfig.Attempts < 0 {
+		return fmt.Errorf("sdk-retry.attempts must not be negative")
+	}
+
+	if retryConfig.InitialBackoff < 0 || retryConfig.MaxBackoff < 0 || retryConfig.CallTimeout < 0 {
+		return fmt.Errorf("sdk-retry durations must not be negative")
+	}
+
+	if retryConfig.MaxBackoff != 0 && retryConfig.InitialBackoff > retryConfig.MaxBackoff {
+		return fmt.Errorf("sdk-retry.initial-backoff must not be greater than sdk-retry.max-backoff")
+	}
+
+	if retryConfig.Jitter < 0 || retryConfig.Jitter > 1 {
+		return fmt.Errorf("sdk-retry.jitter must be between 0 and 1")
+	}
+
+	return nil
+}
+
+func getTerminationNoticeAndValidate(absWorkingDir string, noticeConfig *TerminationNoticeConfig) (config.TerminationNotice, error) {
+	if !noticeConfig.Enabled {
+		if noticeConfig.Margin != 0 || noticeConfig.File != "" || noticeConfig.StdinCommand != "" || noticeConfig.Signal != "" {
+			return config.TerminationNotice{}, fmt.Errorf("termination-notice.enabled must be set to use the termination notice")
+		}
+		return config.TerminationNotice{}, nil
+	}
+
+	if noticeConfig.Margin < 0 {
+		return config.TerminationNotice{}, fmt.Errorf("termination-notice.margin must not be negative")
+	}
+
+	file, err := makeAbsolutePath(absWorkingDir, noticeConfig.File)
+	if err != nil {
+		return config.TerminationNotice{}, fmt.Errorf("error making termination notice file path absolute: %v", err)
+	}
+
+	if _, err := template.New("stdin-command").Parse(noticeConfig.StdinCommand); err != nil {
+		return config.TerminationNotice{}, fmt.Errorf("termination-notice.stdin-command is not a valid template: %v", err)
+	}
+
+	if noticeConfig.Signal != "" {
+		if _, err := process.ParseSignal(noticeConfig.Signal); err != nil {
+			return config.TerminationNotice{}, fmt.Errorf("termination-notice.signal is not valid: %v", err)
+		}
+	}
+
+	return config.TerminationNotice{
+		Enabled:      true,
+		Margin:       noticeConfig.Margin,
+		File:         file,
+		StdinCommand: noticeConfig.StdinCommand,
+		Signal:       noticeConfig.Signal,
+	}, nil
+}
+
+func getLogTriggersAndValidate(absWorkingDir string, logTriggersConfig *LogTriggersConfig) (config.LogTriggers, error) {
+	rulesFile, err := makeAbsolutePath(absWorkingDir, logTriggersConfig.RulesFile)
+	if err != nil {
+		return config.LogTriggers{}, fmt.Errorf("error making rules file path absolute: %v", err)
+	}
+
+	logTriggers := config.LogTriggers{
+		WaitForReady: logTriggersConfig.WaitForReady,
+		RulesFile:    rulesFile,
+		Rules:        logTriggersConfig.Rules,
+	}
+
+	rules, err := triggers.Load(logTriggers)
+	if err != nil {
+		return config.LogTriggers{}, err
+	}
+
+	if logTriggers.WaitForReady && !slices.ContainsFunc(rules, func(rule *triggers.Rule) bool {
+		return rule.Action == config.TriggerActionMarkReady
+	}) {
+		return config.LogTriggers{}, fmt.Errorf("log-triggers.wait-for-ready needs a rule with the '%s' action", config.TriggerActionMarkReady)
+	}
+
+	return logTriggers, nil
+}
+
 func getAnywhereRegionAndValidate(anywhereConfig *AnywhereConfig) (string, error) {
 	if anywhereConfig == nil {
 		return "", nil
 	}
 
-	locationArnDefined := anywhereConfig.LocationArn != ""
-	fleetArnDefined := anywhereConfig.FleetArn != ""
-	ipv4Defined := anywhereConfig.IPv4 != ""
-	if (locationArnDefined != fleetArnDefined) || (locationArnDefined != ipv4Defined) {
-		return "", fmt.Errorf("anywhere.location-arn, anywhere.fleet-arn, and anywhere.ipv4 must be either all empty or all non-empty")
+	if anywhereConfig.FleetArn != "" && anywhereConfig.Fleet != "" {
+		return "", fmt.Errorf("anywhere.fleet cannot be provided with anywhere.fleet-arn")
+	}
+	if anywhereConfig.LocationArn != "" && anywhereConfig.Location != "" {
+		return "", fmt.Errorf("anywhere.location cannot be provided with anywhere.location-arn")
+	}
+
+	fleetDefined := anywhereConfig.FleetArn != "" || anywhereConfig.Fleet != ""
+	locationDefined := anywhereConfig.LocationArn != "" || anywhereConfig.Location != ""
+	if locationDefined != fleetDefined {
+		return "", fmt.Errorf("the anywhere fleet and location must be either both empty or both provided, through anywhere.fleet or anywhere.fleet-arn and anywhere.location or anywhere.location-arn")
+	}
+
+	if strings.HasPrefix(anywhereConfig.Fleet, "arn:") {
+		return "", fmt.Errorf("anywhere.fleet must be a fleet id, name or alias, use anywhere.fleet-arn for an arn")
+	}
+	if anywhereConfig.Location != "" && !customLocationPattern.MatchString(anywhereConfig.Location) {
+		return "", fmt.Errorf("anywhere.location '%s' must be the name of a custom location, starting with 'custom-'", anywhereConfig.Location)
+	}
+
+	if err := validateIpDiscovery(anywhereConfig, fleetDefined); err != nil {
+		return "", err
 	}
 
 	if (anywhereConfig.ComputeName == "") != (anywhereConfig.ServiceSdkEndpoint == "") {
@@ -240,22 +1027,168 @@ func getAnywhereRegionAndValidate(anywhereConfig *AnywhereConfig) (string, error
 		return "", fmt.Errorf("auth-token can only be provided when anywhere.compute-name is provided")
 	}
 
-	if locationArnDefined {
-		locationRegion, err := getRegionFromArn(anywhereConfig.LocationArn)
-		if err != nil {
+	switch anywhereConfig.DeregisterCompute {
+	case "", config.DeregisterComputeAuto, config.DeregisterComputeKeep, config.DeregisterComputeAlways:
+	default:
+		return "", fmt.Errorf("anywhere.deregister-compute must be one of '%s', '%s' or '%s'", config.DeregisterComputeAuto, config.DeregisterComputeKeep, config.DeregisterComputeAlways)
+	}
+
+	if anywhereConfig.ComputeNameTemplate != "" {
+		if anywhereConfig.ComputeName != "" {
+			return "", fmt.Errorf("anywhere.compute-name-template cannot be provided with anywhere.compute-name")
+		}
+		if _, err := template.New("compute-name").Parse(anywhereConfig.ComputeNameTemplate); err != nil {
+			return "", fmt.Errorf("anywhere.compute-name-template is not a valid template: %v", err)
+		}
+	}
+
+	switch anywhereConfig.ComputeNameCollision {
+	case "", config.ComputeNameCollisionReuse, config.ComputeNameCollisionFail, config.ComputeNameCollisionSuffix:
+	default:
+		return "", fmt.Errorf("anywhere.compute-name-collision must be one of '%s', '%s' or '%s'", config.ComputeNameCollisionReuse, config.ComputeNameCollisionFail, config.ComputeNameCollisionSuffix)
+	}
+
+	if err := validateAwsCredentials(anywhereConfig); err != nil {
+		return "", err
+	}
+
+	// without an arn, the region is the one of the aws profile
+	var locationRegion, fleetRegion string
+	var err error
+	if anywhereConfig.LocationArn != "" {
+		if locationRegion, err = getRegionFromArn(anywhereConfig.LocationArn); err != nil {
 			return "", fmt.Errorf("error getting region from location-arn: %v", err)
 		}
-		fleetRegion, err := getRegionFromArn(anywhereConfig.FleetArn)
-		if err != nil {
+	}
+	if anywhereConfig.FleetArn != "" {
+		if fleetRegion, err = getRegionFromArn(anywhereConfig.FleetArn); err != nil {
 			return "", fmt.Errorf("error getting region from fleet-arn: %v", err)
 		}
-		if locationRegion != fleetRegion {
-			return "", fmt.Errorf("location-arn and fleet-arn must be in the same region")
+	}
+	if locationRegion != "" && fleetRegion != "" && locationRegion != fleetRegion {
+		return "", fmt.Errorf("location-arn and fleet-arn must be in the same region")
+	}
+	if fleetRegion != "" {
+		return fleetRegion, nil
+	}
+
+	return locationRegion, nil
+}
+
+func validateAwsCredentials(anywhereConfig *AnywhereConfig) error {
+	literal := anywhereConfig.Literal
+	literalEnvDefined := literal.AccessKeyIdEnv != "" || literal.SecretAccessKeyEnv != "" || literal.SessionTokenEnv != ""
+
+	switch anywhereConfig.Provider {
+	case "", config.AwsConfigProviderProfile, config.AwsConfigProviderSSOFile, config.AwsConfigProviderDefault:
+	case config.AwsConfigProviderLiteral:
+		if literal.File != "" && literalEnvDefined {
+			return fmt.Errorf("anywhere.literal.file cannot be provided with environment variables")
+		}
+		if literal.File == "" && (literal.AccessKeyIdEnv == "" || literal.SecretAccessKeyEnv == "") {
+			return fmt.Errorf("anywhere.literal needs a file, or both access-key-id-env and secret-access-key-env")
 		}
-		return locationRegion, nil
+	case config.AwsConfigProviderCredentialProcess:
+		if strings.TrimSpace(anywhereConfig.CredentialProcess) == "" {
+			return fmt.Errorf("anywhere.credential-process must be provided with the %s provider", config.AwsConfigProviderCredentialProcess)
+		}
+	case config.AwsConfigProviderWebIdentity:
+		if anywhereConfig.WebIdentity.RoleArn == "" || anywhereConfig.WebIdentity.TokenFile == "" {
+			return fmt.Errorf("anywhere.web-identity.role-arn and anywhere.web-identity.token-file must be provided with the %s provider", config.AwsConfigProviderWebIdentity)
+		}
+	default:
+		return fmt.Errorf("anywhere.provider must be one of '%s', '%s', '%s', '%s', '%s' or '%s'",
+			config.AwsConfigProviderProfile, config.AwsConfigProviderSSOFile, config.AwsConfigProviderLiteral,
+			config.AwsConfigProviderDefault, config.AwsConfigProviderCredentialProcess, config.AwsConfigProviderWebIdentity)
 	}
 
-	return "", nil
+	refresh := anywhereConfig.SSOFileRefresh
+	if refresh != (SSOFileRefreshConfig{}) && anywhereConfig.Provider != config.AwsConfigProviderSSOFile {
+		return fmt.Errorf("anywhere.sso-file-refresh can only be provided with the %s provider", config.AwsConfigProviderSSOFile)
+	}
+	if refresh.Command != "" && strings.TrimSpace(refresh.Command) == "" {
+		return fmt.Errorf("anywhere.sso-file-refresh.command cannot be blank")
+	}
+	if refresh.RefreshBefore < 0 || refresh.WarnBefore < 0 || refresh.ProbeInterval < 0 {
+		return fmt.Errorf("anywhere.sso-file-refresh durations cannot be negative")
+	}
+
+	return nil
+}
+
+func validateIpDiscovery(anywhereConfig *AnywhereConfig, fleetDefined bool) error {
+	discovery := anywhereConfig.IpDiscovery
+
+	if anywhereConfig.IPv4 != "" {
+		if addr, err := netip.ParseAddr(anywhereConfig.IPv4); err != nil || !addr.Is4() {
+			return fmt.Errorf("anywhere.ipv4 '%s' is not a valid IPv4 address", anywhereConfig.IPv4)
+		}
+	}
+
+	if anywhereConfig.IPv6 != "" {
+		if addr, err := netip.ParseAddr(anywhereConfig.IPv6); err != nil || !addr.Is6() || addr.Is4In6() {
+			return fmt.Errorf("anywhere.ipv6 '%s' is not a valid IPv6 address", anywhereConfig.IPv6)
+		}
+	}
+
+	staticDefined := anywhereConfig.IPv4 != "" || anywhereConfig.IPv6 != ""
+	switch discovery.Source {
+	case "", config.AddressSourceStatic:
+		if fleetDefined != staticDefined {
+			return fmt.Errorf("anywhere.ipv4 or anywhere.ipv6 must be provided with the anywhere fleet, unless anywhere.ip-discovery.source is '%s' or '%s'", config.AddressSourceInterface, config.AddressSourceDefaultRoute)
+		}
+	case config.AddressSourceInterface, config.AddressSourceDefaultRoute:
+		if !fleetDefined {
+			return fmt.Errorf("anywhere.ip-discovery.source can only be provided with the anywhere fleet")
+		}
+	default:
+		return fmt.Errorf("anywhere.ip-discovery.source must be one of '%s', '%s' or '%s'", config.AddressSourceStatic, config.AddressSourceInterface, config.AddressSourceDefaultRoute)
+	}
+
+	if discovery.Cidr != "" {
+		if _, err := netip.ParsePrefix(discovery.Cidr); err != nil {
+			return fmt.Errorf("anywhere.ip-discovery.cidr '%s' is not a valid CIDR: %v", discovery.Cidr, err)
+		}
+	}
+
+	switch discovery.Family {
+	case "", config.AddressFamilyIPv4, config.AddressFamilyIPv6:
+	default:
+		return fmt.Errorf("anywhere.ip-discovery.family must be either '%s' or '%s'", config.AddressFamilyIPv4, config.AddressFamilyIPv6)
+	}
+
+	if discovery.CheckInterval < 0 {
+		return fmt.Errorf("anywhere.ip-discovery.check-interval must not be negative")
+	}
+
+	return nil
+}
+
+func getProviderAndValidate(provider config.Provider, localConfig *LocalConfig, agonesConfig *AgonesConfig) (config.Provider, error) {
+	switch provider {
+	case "", config.ProviderGameLift:
+		return config.ProviderGameLift, nil
+	case config.ProviderLocal:
+		if localConfig.ScenarioFile == "" && localConfig.ApiAddress == "" {
+			return "", fmt.Errorf("local.scenario-file or local.api-address must be provided when provider is '%s'", config.ProviderLocal)
+		}
+		if localConfig.HealthCheckInterval < 0 {
+			return "", fmt.Errorf("local.health-check-interval must not be negative")
+		}
+		return provider, nil
+	case config.ProviderAgones:
+		if agonesConfig.HealthCheckInterval < 0 {
+			return "", fmt.Errorf("agones.health-check-interval must not be negative")
+		}
+		if len(agonesConfig.SdkAddress) != 0 {
+			if _, _, err := net.SplitHostPort(agonesConfig.SdkAddress); err != nil {
+				return "", fmt.Errorf("agones.sdk-address is not valid: %v", err)
+			}
+		}
+		return provider, nil
+	default:
+		return "", fmt.Errorf("unsupported provider '%s'", provider)
+	}
 }
 
 func getRegionFromArn(arnStr string) (string, error) {
@@ -268,13 +1201,20 @@ func getRegionFromArn(arnStr string) (string, error) {
 
 // Ports defines the network port configuration for the game server.
 type Ports struct {
-	GamePort int `mapstructure:"gamePort" json:"gamePort" yaml:"gamePort, omitempty" validate:"required,gt=0"`
+	GamePort      int               `mapstructure:"gamePort" json:"gamePort" yaml:"gamePort, omitempty" validate:"required,gt=0"`
+	GamePortRange string            `mapstructure:"gamePortRange" json:"gamePortRange" yaml:"gamePortRange, omitempty"`
+	Named         map[string]string `mapstructure:"named" json:"named" yaml:"named, omitempty"`
+	/// To be filled in when the ports are allocated, not config
+	Allocated map[string]int `mapstructure:"-" json:"-" yaml:"-"`
 }
 
 // Hosting defines all hosting-related configuration settings.
 type Hosting struct {
 	config.Hosting `mapstructure:",squash"`
+	Provider       config.Provider `mapstructure:"provider" yaml:"provider"`
 	GameLift       config.GameLift `mapstructure:"gamelift" yaml:"gameLift"`
+	Local          config.Local    `mapstructure:"local" yaml:"local"`
+	Agones         config.Agones   `mapstructure:"agones" yaml:"agones"`
 }
 
 // Game defines the game process configuration and its launch parameters.
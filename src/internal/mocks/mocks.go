@@ -6,7 +6,12 @@
 package mocks
 
 import (
+	"os"
+
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/process"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/request"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/result"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server"
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel/trace"
@@ -29,6 +34,8 @@ type ProcessMock struct {
 	RunResultResponse *process.Result
 	RunErrorResponse  error
 	StateResponse     *process.State
+	SignalError       error
+	Signals           []os.Signal
 }
 
 func (processMock *ProcessMock) Init(ctx context.Context) error {
@@ -43,12 +50,29 @@ func (processMock *ProcessMock) State() *process.State {
 	return processMock.StateResponse
 }
 
+func (processMock *ProcessMock) Signal(sig os.Signal) error {
+	processMock.Signals = append(processMock.Signals, sig)
+	return processMock.SignalError
+}
+
 type GameLiftSdkMock struct {
 	InitSdkError                 error
 	InitSDKFromEnvironmentError  error
 	ProcessReadyError            error
 	ProcessEndingError           error
 	ActivateGameSessionError     error
+	AcceptPlayerSessionError     error
+	RemovePlayerSessionError     error
+	PlayerSessionPolicyError     error
+	OnError                      func(call string, err error)
+	TerminationTime              int64
+	TerminationTimeError         error
+	ComputeCertificate           result.GetComputeCertificateResult
+	ComputeCertificateError      error
+	ComputeCertificateCalls      int
+	FleetRoleCredentials         result.GetFleetRoleCredentialsResult
+	FleetRoleCredentialsError    error
+	FleetRoleCredentialsRequests []request.GetFleetRoleCredentialsRequest
 	DestroyError                 error
 	ProcessParameters            *server.ProcessParameters
 	ServerParameters             *server.ServerParameters
@@ -57,7 +81,13 @@ type GameLiftSdkMock struct {
 	ProcessReadyCalled           bool
 	ProcessEndingCalled          bool
 	ActivateGameSessionCalled    bool
+	AcceptedPlayerSessions       []string
+	RemovedPlayerSessions        []string
+	PlayerSessionPolicies        []model.PlayerSessionCreationPolicy
 	DestroyCalled                bool
+	ReconnectError               error
+	ReconnectParameters          []server.ServerParameters
+	OnConnectionLost             func()
 }
 
 func (gameLiftSdkMock *GameLiftSdkMock) InitSDK(ctx context.Context, params server.ServerParameters) error {
@@ -87,7 +117,49 @@ func (gameLiftSdkMock *GameLiftSdkMock) ActivateGameSession(ctx context.Context)
 	return gameLiftSdkMock.InitSdkError
 }
 
+func (gameLiftSdkMock *GameLiftSdkMock) AcceptPlayerSession(ctx context.Context, playerSessionId string) error {
+	gameLiftSdkMock.AcceptedPlayerSessions = append(gameLiftSdkMock.AcceptedPlayerSessions, playerSessionId)
+	return gameLiftSdkMock.AcceptPlayerSessionError
+}
+
+func (gameLiftSdkMock *GameLiftSdkMock) RemovePlayerSession(ctx context.Context, playerSessionId string) error {
+	gameLiftSdkMock.RemovedPlayerSessions = append(gameLiftSdkMock.RemovedPlayerSessions, playerSessionId)
+	return gameLiftSdkMock.RemovePlayerSessionError
+}
+
+func (gameLiftSdkMock *GameLiftSdkMock) UpdatePlayerSessionCreationPolicy(ctx context.Context, policy model.PlayerSessionCreationPolicy) error {
+	gameLiftSdkMock.PlayerSessionPolicies = append(gameLiftSdkMock.PlayerSessionPolicies, policy)
+	return gameLiftSdkMock.PlayerSessionPolicyError
+}
+
+func (gameLiftSdkMock *GameLiftSdkMock) SetOnError(f func(call string, err error)) {
+	gameLiftSdkMock.OnError = f
+}
+
+func (gameLiftSdkMock *GameLiftSdkMock) GetTerminationTime(ctx context.Context) (int64, error) {
+	return gameLiftSdkMock.TerminationTime, gameLiftSdkMock.TerminationTimeError
+}
+
+func (gameLiftSdkMock *GameLiftSdkMock) GetComputeCertificate(ctx context.Context) (result.GetComputeCertificateResult, error) {
+	gameLiftSdkMock.ComputeCertificateCalls++
+	return gameLiftSdkMock.ComputeCertificate, gameLiftSdkMock.ComputeCertificateError
+}
+
+func (gameLiftSdkMock *GameLiftSdkMock) GetFleetRoleCredentials(ctx context.Context, req request.GetFleetRoleCredentialsRequest) (result.GetFleetRoleCredentialsResult, error) {
+	gameLiftSdkMock.FleetRoleCredentialsRequests = append(gameLiftSdkMock.FleetRoleCredentialsRequests, req)
+	return gameLiftSdkMock.FleetRoleCredentials, gameLiftSdkMock.FleetRoleCredentialsError
+}
+
 func (gameLiftSdkMock *GameLiftSdkMock) Destroy(ctx context.Context) error {
 	gameLiftSdkMock.DestroyCalled = true
 	return gameLiftSdkMock.InitSdkError
 }
+
+func (gameLiftSdkMock *GameLiftSdkMock) Reconnect(ctx context.Context, params server.ServerParameters) error {
+	gameLiftSdkMock.ReconnectParameters = append(gameLiftSdkMock.ReconnectParameters, params)
+	return gameLiftSdkMock.ReconnectError
+}
+
+func (gameLiftSdkMock *GameLiftSdkMock) SetOnConnectionLost(f func()) {
+	gameLiftSdkMock.OnConnectionLost = f
+}
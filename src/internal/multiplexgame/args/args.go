@@ -106,10 +106,12 @@ func (normaliser *normaliser) Normalise(session *game.StartArgs) ([]pkgConfig.Cl
 
 type generator struct {
 	normaliser Normaliser
+	ports      map[string]int
 }
 
 type StartArgs struct {
 	*game.StartArgs
+	Ports map[string]int // the allocated ports by name, including the game port
 }
 
 // Get generates the final command-line arguments for a game session.
@@ -123,6 +125,7 @@ type StartArgs struct {
 func (generator *generator) Get(gsa *game.StartArgs) ([]string, error) {
 	session := &StartArgs{
 		StartArgs: gsa,
+		Ports:     generator.ports,
 	}
 
 	args, err := generator.normaliser.Normalise(gsa)
@@ -157,6 +160,7 @@ func (generator *generator) Get(gsa *game.StartArgs) ([]string, error) {
 
 type Config struct {
 	config.BuildDetail
+	Ports map[string]int
 }
 
 // New creates a new argument generator instance.
@@ -173,6 +177,7 @@ func New(cfg *Config) (Generator, error) {
 		normaliser: &normaliser{
 			cfg: cfg,
 		},
+		ports: cfg.Ports,
 	}
 
 	if err := generator.normaliser.Init(); err != nil {
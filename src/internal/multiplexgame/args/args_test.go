@@ -174,3 +174,27 @@ func Test_Works(t *testing.T) {
 	}
 
 }
+
+func Test_AllocatedPorts(t *testing.T) {
+	//arrange
+	generator, err := New(&Config{
+		BuildDetail: config.BuildDetail{
+			DefaultArgs: []pkgConf.CliArg{
+				{Name: "-port", Value: "{{.GamePort}}", Position: 0},
+				{Name: "-queryPort", Value: "{{.Ports.query}}", Position: 1},
+				{Name: "-rconPort", Value: "{{index .Ports \"rcon\"}}", Position: 2},
+			},
+		},
+		Ports: map[string]int{"game": 7777, "query": 27015, "rcon": 27100},
+	})
+	assert.NoError(t, err)
+
+	//act
+	args, err := generator.Get(&game.StartArgs{
+		HostingStart: &events.HostingStart{GamePort: 7777},
+	})
+
+	//assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"-port", "7777", "-queryPort", "27015", "-rconPort", "27100"}, args)
+}
@@ -0,0 +1,65 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package multiplexgame
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/game"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// lifecycleObserver publishes game state transitions to the logs, the active span and the metrics.
+type lifecycleObserver struct {
+	logger      *slog.Logger
+	transitions metric.Int64Counter
+	durations   metric.Float64Histogram
+}
+
+func (lifecycleObserver *lifecycleObserver) onTransition(ctx context.Context, t game.Transition) {
+	lifecycleObserver.logger.InfoContext(ctx, "Game state changed",
+		"from", t.From,
+		"to", t.To,
+		"reason", t.Reason,
+		"elapsed", t.Elapsed)
+
+	attributes := []attribute.KeyValue{
+		attribute.String("from", string(t.From)),
+		attribute.String("to", string(t.To)),
+	}
+
+	trace.SpanFromContext(ctx).AddEvent("game state transition", trace.WithTimestamp(t.At), trace.WithAttributes(
+		append(attributes, attribute.String("reason", t.Reason))...,
+	))
+
+	lifecycleObserver.transitions.Add(ctx, 1, metric.WithAttributes(attributes...))
+	lifecycleObserver.durations.Record(ctx, t.Elapsed.Seconds(), metric.WithAttributes(attribute.String("state", string(t.From))))
+}
+
+func newLifecycleObserver(logger *slog.Logger, meter metric.Meter) (*lifecycleObserver, error) {
+	transitions, err := meter.Int64Counter("game.state.transitions",
+		metric.WithDescription("Number of game state transitions"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create game state transition counter")
+	}
+
+	durations, err := meter.Float64Histogram("game.state.duration",
+		metric.WithDescription("Time spent in a game state before leaving it"),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create game state duration histogram")
+	}
+
+	return &lifecycleObserver{
+		logger:      logger,
+		transitions: transitions,
+		durations:   durations,
+	}, nil
+}
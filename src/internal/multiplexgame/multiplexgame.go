@@ -13,16 +13,26 @@ import (
 	"log/slog"
 	"os"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/internal/config"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/internal/multiplexgame/args"
+	pkgconfig "github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/constants"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/game"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/logging"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/observability"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/process"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/types"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/types/events"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// stopWaitTimeout bounds how long Stop waits for Run to record the session outcome.
+const stopWaitTimeout = 10 * time.Second
+
 // New creates a new MultiplexGame instance with the provided configuration and dependencies.
 //
 // Parameters:
@@ -30,11 +40,12 @@ import (
 //   - logger: Main logger for the game server
 //   - sessionLoggerFactory: Factory for creating session-specific loggers
 //   - spanner: Tracing and monitoring provider
+//   - meter: Metrics provider for game state telemetry
 //
 // Returns:
 //   - *MultiplexGame: New game server instance
 //   - error: Any error during initialization
-func New(cfg config.Config, logger *slog.Logger, sessionLoggerFactory SessionLoggerFactory, spanner observability.Spanner) (*MultiplexGame, error) {
+func New(cfg config.Config, logger *slog.Logger, sessionLoggerFactory SessionLoggerFactory, spanner observability.Spanner, meter metric.Meter) (*MultiplexGame, error) {
 	if logger == nil {
 
 		return nil, errors.New("multiplex game initialization failed: logger not provided")
@@ -45,11 +56,36 @@ func New(cfg config.Config, logger *slog.Logger, sessionLoggerFactory SessionLog
 	if spanner == nil {
 		return nil, errors.New("multiplex game initialization failed: spanner not provided")
 	}
+	if meter == nil {
+		return nil, errors.New("multiplex game initialization failed: meter not provided")
+	}
+
+	observer, err := newLifecycleObserver(logger, meter)
+	if err != nil {
+		return nil, fmt.Errorf("multiplex game initialization failed: %w", err)
+	}
+
+	outcomes, err := newOutcomeRecorder(logger, meter)
+	if err != nil {
+		return nil, fmt.Errorf("multiplex game initialization failed: %w", err)
+	}
+
+	notice, err := newTerminationNotice(cfg.TerminationNotice)
+	if err != nil {
+		return nil, fmt.Errorf("multiplex game initialization failed: %w", err)
+	}
+
+	state := game.NewStateMachine()
+	state.Subscribe(observer.onTransition)
+
 	multiplexGame := MultiplexGame{
 		cfg:                  cfg,
 		logger:               logger,
 		sessionLoggerFactory: sessionLoggerFactory,
 		spanner:              spanner,
+		state:                state,
+		outcomes:             outcomes,
+		notice:               notice,
 	}
 	return &multiplexGame, nil
 }
@@ -63,8 +99,19 @@ type MultiplexGame struct {
 	spanner              observability.Spanner
 	stdout, stderr       *logging.BufferedLogger
 	proc                 process.Process
-	status               events.GameStatus
+	state                *game.StateMachine
 	cancel               func()
+	outcomes             *outcomeRecorder
+	notice               *terminationNotice // nil when the termination notice is disabled
+
+	mutex       sync.Mutex
+	stopTrigger game.TerminationTrigger
+	unhealthy   string
+	onOutput    func(ctx context.Context, stream pkgconfig.TriggerStream, line string)
+	onLaunch    func()
+	runDone     chan struct{}
+	stdin       *os.File // write end of the game process stdin, open while it runs with a stdin notice
+	outcome     *game.Outcome
 }
 
 // SessionLoggerFactory defines the interface for creating session-specific loggers.
@@ -72,6 +119,79 @@ type SessionLoggerFactory interface {
 	New(ctx context.Context, name string, logDirectory string) (*logging.BufferedLogger, error)
 }
 
+// StateMachine returns the lifecycle state machine of the game server so callers can observe transitions.
+func (multiplexGame *MultiplexGame) StateMachine() *game.StateMachine {
+	return multiplexGame.state
+}
+
+// Outcome returns the outcome of the last game session, or nil if no session has ended yet.
+func (multiplexGame *MultiplexGame) Outcome() *game.Outcome {
+	multiplexGame.mutex.Lock()
+	defer multiplexGame.mutex.Unlock()
+	return multiplexGame.outcome
+}
+
+// SetOnOutput registers a function called with every line the game process writes to stdout or stderr.
+// It must be set before the game is run.
+//
+// Parameters:
+//   - f: Function receiving the stream and the line, without its line ending
+func (multiplexGame *MultiplexGame) SetOnOutput(f func(ctx context.Context, stream pkgconfig.TriggerStream, line string)) {
+	multiplexGame.mutex.Lock()
+	defer multiplexGame.mutex.Unlock()
+	multiplexGame.onOutput = f
+}
+
+// SetOnLaunch registers a function called right before the game process is launched, such as releasing
+// the ports reserved for it. It must be set before the game is run.
+//
+// Parameters:
+//   - f: Function called before every launch
+func (multiplexGame *MultiplexGame) SetOnLaunch(f func()) {
+	multiplexGame.mutex.Lock()
+	defer multiplexGame.mutex.Unlock()
+	multiplexGame.onLaunch = f
+}
+
+// MarkUnhealthy makes the health checks report the game server as errored for the rest of the session.
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - reason: Why the game server is unhealthy
+func (multiplexGame *MultiplexGame) MarkUnhealthy(ctx context.Context, reason string) {
+	multiplexGame.mutex.Lock()
+	defer multiplexGame.mutex.Unlock()
+	if len(multiplexGame.unhealthy) == 0 {
+		multiplexGame.logger.WarnContext(ctx, "Game server marked unhealthy", "reason", reason)
+		multiplexGame.unhealthy = reason
+	}
+}
+
+// Terminate ends the running game session by stopping the game process.
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - reason: Why the session is terminated
+//
+// Returns:
+//   - error: An error if no game session is running
+func (multiplexGame *MultiplexGame) Terminate(ctx context.Context, reason string) error {
+	multiplexGame.mutex.Lock()
+	cancel := multiplexGame.cancel
+	if cancel != nil && len(multiplexGame.stopTrigger) == 0 {
+		multiplexGame.stopTrigger = game.TerminationTriggerLogRule
+	}
+	multiplexGame.mutex.Unlock()
+
+	if cancel == nil {
+		return errors.New("no game session is running")
+	}
+
+	multiplexGame.logger.InfoContext(ctx, "Terminating game session", "reason", reason)
+	cancel()
+	return nil
+}
+
 // HealthCheck performs a health check on the game server and returns its current status.
 //
 // Parameters:
@@ -80,22 +200,52 @@ type SessionLoggerFactory interface {
 // Returns:
 //   - events.GameStatus: Current status of the game server
 func (multiplexGame *MultiplexGame) HealthCheck(ctx context.Context) events.GameStatus {
-	var pid *int
 	if multiplexGame.proc != nil {
 		procState := multiplexGame.proc.State()
 
-		if procState != nil {
-			if procState.Pid != 0 {
-				pid = &procState.Pid
-			}
-
-			if procState.Exited {
-				multiplexGame.status = events.GameStatusFinished
-			}
+		if procState != nil && procState.Exited && multiplexGame.state.State() == types.GameStateRunning {
+			multiplexGame.settle(ctx, types.GameStateFinishingGame, "game process exited")
 		}
 	}
-	_ = pid
-	return multiplexGame.status
+
+	multiplexGame.mutex.Lock()
+	unhealthy := len(multiplexGame.unhealthy) != 0
+	multiplexGame.mutex.Unlock()
+	if unhealthy {
+		return events.GameStatusErrored
+	}
+
+	return multiplexGame.state.GameStatus()
+}
+
+// transition moves the game to a new lifecycle state, logging any rejected transition.
+func (multiplexGame *MultiplexGame) transition(ctx context.Context, to types.GameState, reason string) error {
+	if err := multiplexGame.state.Transition(ctx, to, reason); err != nil {
+		multiplexGame.logger.ErrorContext(ctx, "Game state transition rejected", "to", to, "reason", reason, "error", err)
+		return err
+	}
+	return nil
+}
+
+// settle moves the game towards the end of its lifecycle. Losing a race against another
+// closing transition, such as a concurrent stop, is expected and is not treated as an error.
+func (multiplexGame *MultiplexGame) settle(ctx context.Context, to types.GameState, reason string) {
+	err := multiplexGame.state.Transition(ctx, to, reason)
+	if err == nil {
+		return
+	}
+
+	var invalid *game.InvalidTransitionError
+	if errors.As(err, &invalid) && isClosingState(invalid.From) {
+		multiplexGame.logger.DebugContext(ctx, "Game already closing, state unchanged", "state", invalid.From, "to", to, "reason", reason)
+		return
+	}
+
+	multiplexGame.logger.ErrorContext(ctx, "Game state transition rejected", "to", to, "reason", reason, "error", err)
+}
+
+func isClosingState(state types.GameState) bool {
+	return state == types.GameStateFinishingGame || state == types.GameStateShuttingDown || state == types.GameStateFailed
 }
 
 // Run starts the game server process with the provided arguments.
@@ -106,11 +256,37 @@ func (multiplexGame *MultiplexGame) HealthCheck(ctx context.Context) events.Game
 //
 // Returns:
 //   - error: Any error during server execution
-func (multiplexGame *MultiplexGame) Run(ctx context.Context, startArgs *game.StartArgs) error {
+func (multiplexGame *MultiplexGame) Run(ctx context.Context, startArgs *game.StartArgs) (err error) {
+	parentCtx := ctx
+	gameRunSpan := trace.SpanFromContext(ctx)
+
 	ctx, span, _ := multiplexGame.spanner.NewSpan(ctx, "run", nil)
 	defer span.End()
 
+	if err := multiplexGame.transition(ctx, types.GameStateStartingGame, "hosting start received"); err != nil {
+		return fmt.Errorf("failed to start game: %w", err)
+	}
+
+	startedAt := time.Now()
+	done := make(chan struct{})
+	multiplexGame.mutex.Lock()
+	multiplexGame.runDone = done
+	multiplexGame.mutex.Unlock()
+
+	var result *process.Result
+	launched := false
+	defer func() {
+		multiplexGame.recordOutcome(parentCtx, gameRunSpan, startArgs, outcomeDetail{
+			launched:  launched,
+			result:    result,
+			err:       err,
+			startedAt: startedAt,
+		})
+		close(done)
+	}()
+
 	if multiplexGame.cfg.Ports.GamePort == 0 {
+		multiplexGame.settle(ctx, types.GameStateFailed, "invalid game port")
 		return errors.New("game server initialization failed: invalid game port: 0")
 	}
 
@@ -118,19 +294,27 @@ func (multiplexGame *MultiplexGame) Run(ctx context.Context, startArgs *game.Sta
 
 	build := multiplexGame.cfg.BuildDetail
 
-	err := multiplexGame.initProcess(ctx, build)
+	var envVars map[string]string
+	if startArgs != nil && startArgs.HostingStart != nil {
+		envVars = startArgs.EnvVars
+	}
+
+	err = multiplexGame.initProcess(ctx, build, envVars)
 	if err != nil {
 		multiplexGame.logger.ErrorContext(ctx, "Game process initialization failed",
 			"error", err,
 			"buildPath", build.RelativeExePath,
 			"workingDir", build.WorkingDir)
+		multiplexGame.settle(ctx, types.GameStateFailed, "game process initialization failed")
 		return fmt.Errorf("failed to initialize game process: %w", err)
 	}
 
 	multiplexGame.logger.InfoContext(ctx, "Starting multiplex game", "arguments", startArgs)
 
 	ctx, cancel := context.WithCancel(ctx)
+	multiplexGame.mutex.Lock()
 	multiplexGame.cancel = cancel
+	multiplexGame.mutex.Unlock()
 
 	gsPidChan := make(chan int)
 
@@ -139,14 +323,17 @@ func (multiplexGame *MultiplexGame) Run(ctx context.Context, startArgs *game.Sta
 	multiplexGame.logger.DebugContext(ctx, "Generating command line arguments")
 	argGenerator, err := args.New(&args.Config{
 		BuildDetail: build,
+		Ports:       multiplexGame.cfg.Ports.Allocated,
 	})
 	if err != nil {
 		multiplexGame.logger.Error("Failed to create argument generator: ", "build", build, "error", err)
+		multiplexGame.settle(ctx, types.GameStateFailed, "argument generator creation failed")
 		return fmt.Errorf("failed to create argument generator: %w", err)
 	}
 	processArgs, err := argGenerator.Get(startArgs)
 	if err != nil {
 		multiplexGame.logger.Error("Failed to generate process arguments: ", "error", err)
+		multiplexGame.settle(ctx, types.GameStateFailed, "argument generation failed")
 		return fmt.Errorf("failed to generate process arguments: %w", err)
 	}
 	multiplexGame.logger.DebugContext(ctx, "cli args: ", "args", processArgs)
@@ -154,30 +341,73 @@ func (multiplexGame *MultiplexGame) Run(ctx context.Context, startArgs *game.Sta
 	multiplexGame.logger.DebugContext(ctx, "Creating log files")
 	if err := multiplexGame.createLogStreams(ctx, startArgs.LogDirectory); err != nil {
 		multiplexGame.logger.Error("failed to create log streams for stdout and stderr", "error", err)
+		multiplexGame.settle(ctx, types.GameStateFailed, "log stream creation failed")
 		return fmt.Errorf("failed to create log streams: %w", err)
 	}
 
+	if err := multiplexGame.transition(ctx, types.GameStateRunning, "launching game process"); err != nil {
+		return fmt.Errorf("failed to run game: %w", err)
+	}
+	startedAt = time.Now()
+
+	var stdin *os.File
+	if multiplexGame.notice != nil && multiplexGame.notice.stdinCommand != nil {
+		var stdinWriter *os.File
+		stdin, stdinWriter, err = os.Pipe()
+		if err != nil {
+			multiplexGame.settle(ctx, types.GameStateFailed, "stdin pipe creation failed")
+			return fmt.Errorf("failed to create game process stdin: %w", err)
+		}
+		multiplexGame.mutex.Lock()
+		multiplexGame.stdin = stdinWriter
+		multiplexGame.mutex.Unlock()
+		defer multiplexGame.closeStdin(stdin)
+	}
+
+	if multiplexGame.notice != nil && len(multiplexGame.notice.cfg.File) != 0 {
+		// a notice left over from a previous session must not be mistaken for a new one
+		if err := os.Remove(multiplexGame.notice.cfg.File); err != nil && !os.IsNotExist(err) {
+			multiplexGame.logger.WarnContext(ctx, "failed to remove termination notice file", "file", multiplexGame.notice.cfg.File, "err", err)
+		}
+	}
+
+	multiplexGame.mutex.Lock()
+	onLaunch := multiplexGame.onLaunch
+	multiplexGame.mutex.Unlock()
+	if onLaunch != nil {
+		onLaunch()
+	}
+
 	e := make(chan error)
 	go func() {
-		multiplexGame.status = events.GameStatusRunning
 		multiplexGame.logger.DebugContext(ctx, "Calling process run")
 
-		res, err := multiplexGame.proc.Run(ctx, &process.Args{
+		procArgs := &process.Args{
 			CliArgs: processArgs,
 			Stdout:  io.MultiWriter(os.Stdout, multiplexGame.stdout),
 			Stderr:  io.MultiWriter(os.Stderr, multiplexGame.stderr),
-		}, gsPidChan)
+		}
+		if stdin != nil {
+			// a nil *os.File would not be a nil io.Reader
+			procArgs.Stdin = stdin
+		}
+		res, err := multiplexGame.proc.Run(ctx, procArgs, gsPidChan)
 
 		multiplexGame.logger.DebugContext(ctx, "Process run finished", "result", res)
 
+		result = res
+		if procState := multiplexGame.proc.State(); procState != nil && procState.Pid != 0 {
+			launched = true
+		}
+
 		if err != nil {
-			multiplexGame.status = events.GameStatusErrored
+			multiplexGame.settle(ctx, types.GameStateFailed, "game process failed")
 			multiplexGame.logger.Error("Game process execution failed: ", "error", err)
 			e <- fmt.Errorf("game process failure: %w", err)
 			return
 		}
 
-		multiplexGame.status = events.GameStatusFinished
+		multiplexGame.settle(ctx, types.GameStateFinishingGame, "game process exited")
 
 		e <- nil
 	}()
@@ -189,6 +419,18 @@ func (multiplexGame *MultiplexGame) Run(ctx context.Context, startArgs *game.Sta
 	return err
 }
 
+// closeStdin closes both ends of the game process stdin pipe.
+func (multiplexGame *MultiplexGame) closeStdin(stdinReader *os.File) {
+	multiplexGame.mutex.Lock()
+	defer multiplexGame.mutex.Unlock()
+
+	_ = stdinReader.Close()
+	if multiplexGame.stdin != nil {
+		_ = multiplexGame.stdin.Close()
+		multiplexGame.stdin = nil
+	}
+}
+
 // Init initializes the game server instance and prepares it for operation.
 // This method sets up the initial state and returns metadata about the initialization.
 //
@@ -201,14 +443,16 @@ func (multiplexGame *MultiplexGame) Run(ctx context.Context, startArgs *game.Sta
 //   - error: Any error during initialization
 func (multiplexGame *MultiplexGame) Init(ctx context.Context, args *game.InitArgs) (*game.InitMeta, error) {
 	multiplexGame.logger.DebugContext(ctx, "Starting multiplex game initialization", "args", args)
-	multiplexGame.status = events.GameStatusWaiting
+	if state := multiplexGame.state.State(); state != types.GameStateInitialising {
+		return nil, fmt.Errorf("game cannot be initialized in state '%s'", state)
+	}
 	meta := &game.InitMeta{}
 
 	multiplexGame.logger.InfoContext(ctx, "Multiplex game initialized")
 	return meta, nil
 }
 
-func (multiplexGame *MultiplexGame) initProcess(ctx context.Context, build config.BuildDetail) error {
+func (multiplexGame *MultiplexGame) initProcess(ctx context.Context, build config.BuildDetail, envVars map[string]string) error {
 	wd, err := os.Stat(build.WorkingDir)
 	if err != nil {
 		return fmt.Errorf("failed to access working directory %s: %w", build.WorkingDir, err)
@@ -229,6 +473,17 @@ func (multiplexGame *MultiplexGame) initProcess(ctx context.Context, build confi
 	}
 	multiplexGame.logger.DebugContext(ctx, "Passing wrapper's environment variables to game process", "envVarsCount", len(envMap))
 
+	for key, value := range envVars {
+		envMap[key] = value
+	}
+	if len(envVars) != 0 {
+		multiplexGame.logger.DebugContext(ctx, "Passing hosting environment variables to game process", "envVarsCount", len(envVars))
+	}
+
+	if multiplexGame.notice != nil && len(multiplexGame.notice.cfg.File) != 0 {
+		envMap[constants.EnvironmentKeyTerminationNoticeFile] = multiplexGame.notice.cfg.File
+	}
+
 	procCfg := &process.Config{
 		EnvVars:          envMap,
 		WorkingDirectory: build.WorkingDir,
@@ -236,7 +491,16 @@ func (multiplexGame *MultiplexGame) initProcess(ctx context.Context, build confi
 	}
 	multiplexGame.proc = process.New(procCfg, multiplexGame.logger)
 
-	multiplexGame.logger.DebugContext(ctx, "Initializing game process with configuration", "procCfg", procCfg)
+	// the hosting environment variables can contain credentials
+	loggedCfg := *procCfg
+	loggedCfg.EnvVars = make(map[string]string, len(envMap))
+	for key, value := range envMap {
+		if _, ok := envVars[key]; ok {
+			value = "<REDACTED>"
+		}
+		loggedCfg.EnvVars[key] = value
+	}
+	multiplexGame.logger.DebugContext(ctx, "Initializing game process with configuration", "procCfg", &loggedCfg)
 	if err := multiplexGame.proc.Init(ctx); err != nil {
 		return fmt.Errorf("failed to initialize game process: %w", err)
 	}
@@ -253,9 +517,45 @@ func (multiplexGame *MultiplexGame) initProcess(ctx context.Context, build confi
 //   - error: Any error during shutdown
 func (multiplexGame *MultiplexGame) Stop(ctx context.Context) error {
 	multiplexGame.logger.InfoContext(ctx, "Initiating game server shutdown")
-	if multiplexGame.cancel != nil {
+
+	trigger, ok := game.TerminationTriggerFromContext(ctx)
+	if !ok {
+		trigger = game.TerminationTriggerWrapperShutdown
+	}
+
+	multiplexGame.mutex.Lock()
+	if len(multiplexGame.stopTrigger) == 0 {
+		multiplexGame.stopTrigger = trigger
+	}
+	done := multiplexGame.runDone
+	cancel := multiplexGame.cancel
+	multiplexGame.mutex.Unlock()
+
+	err := multiplexGame.state.Transition(ctx, types.GameStateShuttingDown, "stop requested")
+	var invalid *game.InvalidTransitionError
+	if errors.As(err, &invalid) && invalid.From == types.GameStateShuttingDown {
+		// Stop is called both on hosting termination and on close
+		err = nil
+	} else if err != nil {
+		multiplexGame.logger.ErrorContext(ctx, "Game state transition rejected", "to", types.GameStateShuttingDown, "error", err)
+	}
+
+	if terminationTime, ok := game.TerminationTimeFromContext(ctx); ok && multiplexGame.notice != nil && cancel != nil {
+		multiplexGame.awaitTermination(ctx, terminationTime, done)
+	}
+
+	if cancel != nil {
 		multiplexGame.logger.DebugContext(ctx, "Canceling game server context")
-		multiplexGame.cancel()
+		cancel()
+	}
+
+	if done != nil {
+		// wait for the session outcome to be recorded so it is included in the uploaded logs
+		select {
+		case <-done:
+		case <-time.After(stopWaitTimeout):
+			multiplexGame.logger.WarnContext(ctx, "Timed out waiting for game run to finish", "timeout", stopWaitTimeout)
+		}
 	}
 
 	if multiplexGame.stdout != nil {
@@ -274,10 +574,75 @@ func (multiplexGame *MultiplexGame) Stop(ctx context.Context) error {
 
 	multiplexGame.logger.InfoContext(ctx, "Game server shutdown completed")
 
-	return nil
+	return err
+}
+
+// outcomeDetail holds what Run knows about the end of a game session.
+type outcomeDetail struct {
+	launched  bool
+	result    *process.Result
+	err       error
+	startedAt time.Time
+}
+
+// recordOutcome classifies how the game session ended and publishes the outcome.
+func (multiplexGame *MultiplexGame) recordOutcome(ctx context.Context, span trace.Span, startArgs *game.StartArgs, detail outcomeDetail) {
+	multiplexGame.mutex.Lock()
+	trigger := multiplexGame.stopTrigger
+	multiplexGame.mutex.Unlock()
+
+	if len(trigger) == 0 {
+		switch {
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			trigger = game.TerminationTriggerTimeout
+		case ctx.Err() != nil:
+			trigger = game.TerminationTriggerWrapperShutdown
+		default:
+			trigger = game.TerminationTriggerGameExit
+		}
+	}
+
+	endedAt := time.Now()
+	outcome := &game.Outcome{
+		Trigger:   trigger,
+		ExitCode:  -1,
+		StartedAt: detail.startedAt,
+		EndedAt:   endedAt,
+	}
+	if detail.launched {
+		outcome.Duration = endedAt.Sub(detail.startedAt)
+	}
+	if detail.result != nil {
+		outcome.ExitCode = detail.result.ReturnCode
+		if detail.result.Signal != nil {
+			outcome.Signal = detail.result.Signal.String()
+		}
+	}
+	if detail.err != nil {
+		outcome.Error = detail.err.Error()
+	}
+
+	logDirectory := ""
+	if startArgs != nil && startArgs.HostingStart != nil {
+		outcome.GameSessionId = startArgs.GameSessionId
+		logDirectory = startArgs.LogDirectory
+	}
+	outcome.Classification = game.Classify(detail.launched, trigger, outcome.ExitCode, outcome.Signal)
+
+	multiplexGame.mutex.Lock()
+	multiplexGame.outcome = outcome
+	multiplexGame.mutex.Unlock()
+
+	multiplexGame.outcomes.record(ctx, span, outcome, logDirectory)
 }
 
 func (multiplexGame *MultiplexGame) createLogStreams(ctx context.Context, logDirectory string) error {
+	if len(logDirectory) != 0 {
+		if err := os.MkdirAll(logDirectory, 0755); err != nil {
+			return fmt.Errorf("failed to create session log directory '%s': %w", logDirectory, err)
+		}
+	}
+
 	stdout, err := multiplexGame.sessionLoggerFactory.New(ctx, "game-stdout.log", logDirectory)
 	if err != nil {
 		return err
@@ -287,6 +652,18 @@ func (multiplexGame *MultiplexGame) createLogStreams(ctx context.Context, logDir
 		return err
 	}
 
+	multiplexGame.mutex.Lock()
+	onOutput := multiplexGame.onOutput
+	multiplexGame.mutex.Unlock()
+	if onOutput != nil {
+		stdout.SetOnLine(func(ctx context.Context, line string) {
+			onOutput(ctx, pkgconfig.TriggerStreamStdout, line)
+		})
+		stderr.SetOnLine(func(ctx context.Context, line string) {
+			onOutput(ctx, pkgconfig.TriggerStreamStderr, line)
+		})
+	}
+
 	multiplexGame.stdout, multiplexGame.stderr = stdout, stderr
 
 	return nil
@@ -7,7 +7,9 @@ package multiplexgame
 
 import (
 	"bytes"
+	"encoding/json"
 	"log/slog"
+	"os"
 	"path/filepath"
 	"runtime"
 	"testing"
@@ -15,13 +17,16 @@ import (
 
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/internal/config"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/internal/mocks"
+	pkgconfig "github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/game"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/logging"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/observability"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/process"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/types"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/types/events"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/metric/noop"
 	"golang.org/x/net/context"
 )
 
@@ -56,7 +61,7 @@ func createMultiPlexGameWithMocks(cfg config.Config) MultiPlexGameMock {
 	mockSessionLoggerFactory := MockSessionLoggerFactory{
 		logger: logger,
 	}
-	multiplexGame, _ := New(cfg, logger, &mockSessionLoggerFactory, &spannerMock)
+	multiplexGame, _ := New(cfg, logger, &mockSessionLoggerFactory, &spannerMock, noop.NewMeterProvider().Meter("test"))
 	return MultiPlexGameMock{
 		logger:        logger,
 		spanner:       &spannerMock,
@@ -71,7 +76,6 @@ func TestHealthCheckHappyPathNoProcess(t *testing.T) {
 	// Arrange
 	cfg := config.Config{}
 	multiPlexGameMock := createMultiPlexGameWithMocks(cfg)
-	multiPlexGameMock.multiplexGame.status = events.GameStatusWaiting
 
 	// Act
 	gameStatus := multiPlexGameMock.multiplexGame.HealthCheck(multiPlexGameMock.ctx)
@@ -84,6 +88,8 @@ func TestHealthCheckHappyPathWithProcessExited(t *testing.T) {
 	// Arrange
 	cfg := config.Config{}
 	multiPlexGameMock := createMultiPlexGameWithMocks(cfg)
+	assert.Nil(t, multiPlexGameMock.multiplexGame.state.Transition(multiPlexGameMock.ctx, types.GameStateStartingGame, "test"))
+	assert.Nil(t, multiPlexGameMock.multiplexGame.state.Transition(multiPlexGameMock.ctx, types.GameStateRunning, "test"))
 
 	multiPlexGameMock.multiplexGame.proc = &mocks.ProcessMock{
 		StateResponse: &process.State{
@@ -125,19 +131,24 @@ func TestRunHappyPath(t *testing.T) {
 	}
 
 	multiPlexGameMock := createMultiPlexGameWithMocks(cfg)
-	multiPlexGameMock.multiplexGame.status = events.GameStatusWaiting
 
+	logDirectory := t.TempDir()
 	startArgs := game.StartArgs{
-		&events.HostingStart{
-			LogDirectory: workingDir,
+		HostingStart: &events.HostingStart{
+			GameSessionId: "gs-1",
+			LogDirectory:  logDirectory,
+			EnvVars:       map[string]string{"hostingEnvKey": "hostingEnvSecret"},
 		},
 	}
+	launches := 0
+	multiPlexGameMock.multiplexGame.SetOnLaunch(func() { launches++ })
 
 	// Act
 	err = multiPlexGameMock.multiplexGame.Run(multiPlexGameMock.ctx, &startArgs)
 
 	//Assert
 	assert.Nil(t, err)
+	assert.Equal(t, 1, launches)
 	logString := multiPlexGameMock.logBuffer.String()
 	assert.Contains(t, logString, "Starting multiplex game")
 	assert.Contains(t, logString, "Generating command line arguments")
@@ -148,6 +159,54 @@ func TestRunHappyPath(t *testing.T) {
 	assert.Contains(t, logString, "Process result received")
 	assert.Contains(t, logString, testEnvKey)
 	assert.Contains(t, logString, testEnvValue)
+	assert.Contains(t, logString, "hostingEnvKey")
+	assert.NotContains(t, logString, "hostingEnvSecret")
+	assert.Contains(t, logString, "Game session ended")
+
+	outcome := multiPlexGameMock.multiplexGame.Outcome()
+	assert.NotNil(t, outcome)
+	assert.Equal(t, "gs-1", outcome.GameSessionId)
+	assert.Equal(t, game.OutcomeCompleted, outcome.Classification)
+	assert.Equal(t, game.TerminationTriggerGameExit, outcome.Trigger)
+	assert.Equal(t, 0, outcome.ExitCode)
+
+	b, err := os.ReadFile(filepath.Join(logDirectory, game.OutcomeFileName))
+	assert.Nil(t, err)
+	written := map[string]any{}
+	assert.Nil(t, json.Unmarshal(b, &written))
+	assert.Equal(t, "completed", written["classification"])
+	assert.Contains(t, written, "durationSeconds")
+}
+
+func TestRunFailedToStart(t *testing.T) {
+	// Arrange
+	cfg := config.Config{
+		Ports: config.Ports{
+			GamePort: 12345,
+		},
+		BuildDetail: config.BuildDetail{
+			WorkingDir:      filepath.Join(t.TempDir(), "missing"),
+			RelativeExePath: "./missing",
+		},
+	}
+	multiPlexGameMock := createMultiPlexGameWithMocks(cfg)
+	logDirectory := t.TempDir()
+	startArgs := game.StartArgs{
+		HostingStart: &events.HostingStart{
+			LogDirectory: logDirectory,
+		},
+	}
+
+	// Act
+	err := multiPlexGameMock.multiplexGame.Run(multiPlexGameMock.ctx, &startArgs)
+
+	//Assert
+	assert.NotNil(t, err)
+	outcome := multiPlexGameMock.multiplexGame.Outcome()
+	assert.NotNil(t, outcome)
+	assert.Equal(t, game.OutcomeFailedToStart, outcome.Classification)
+	assert.NotEmpty(t, outcome.Error)
+	assert.FileExists(t, filepath.Join(logDirectory, game.OutcomeFileName))
 }
 
 func TestInitHappyPath(t *testing.T) {
@@ -168,7 +227,7 @@ func TestInitHappyPath(t *testing.T) {
 	logString := multiPlexGameMock.logBuffer.String()
 	assert.Contains(t, logString, "Starting multiplex game initialization")
 	assert.Contains(t, logString, "Multiplex game initialized")
-	assert.Equal(t, events.GameStatusWaiting, multiPlexGameMock.multiplexGame.status)
+	assert.Equal(t, events.GameStatusWaiting, multiPlexGameMock.multiplexGame.HealthCheck(multiPlexGameMock.ctx))
 }
 
 func TestStopHappyPath(t *testing.T) {
@@ -184,3 +243,131 @@ func TestStopHappyPath(t *testing.T) {
 	logString := multiPlexGameMock.logBuffer.String()
 	assert.Contains(t, logString, "Initiating game server shutdown")
 }
+
+func TestHealthCheckMarkedUnhealthy(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	multiPlexGameMock := createMultiPlexGameWithMocks(cfg)
+
+	// Act
+	multiPlexGameMock.multiplexGame.MarkUnhealthy(multiPlexGameMock.ctx, "test")
+	gameStatus := multiPlexGameMock.multiplexGame.HealthCheck(multiPlexGameMock.ctx)
+
+	//Assert
+	assert.Equal(t, events.GameStatusErrored, gameStatus)
+	assert.Contains(t, multiPlexGameMock.logBuffer.String(), "Game server marked unhealthy")
+}
+
+func TestTerminateNoSession(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	multiPlexGameMock := createMultiPlexGameWithMocks(cfg)
+
+	// Act
+	err := multiPlexGameMock.multiplexGame.Terminate(multiPlexGameMock.ctx, "test")
+
+	//Assert
+	assert.Error(t, err)
+}
+
+func TestTerminateSetsLogRuleTrigger(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	multiPlexGameMock := createMultiPlexGameWithMocks(cfg)
+	ctx, cancel := context.WithCancel(multiPlexGameMock.ctx)
+	multiPlexGameMock.multiplexGame.cancel = cancel
+
+	// Act
+	err := multiPlexGameMock.multiplexGame.Terminate(multiPlexGameMock.ctx, "test")
+
+	//Assert
+	assert.NoError(t, err)
+	assert.Error(t, ctx.Err())
+	assert.Equal(t, game.TerminationTriggerLogRule, multiPlexGameMock.multiplexGame.stopTrigger)
+}
+
+func TestCreateLogStreamsForwardsOutput(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	multiPlexGameMock := createMultiPlexGameWithMocks(cfg)
+	var lines []string
+	multiPlexGameMock.multiplexGame.SetOnOutput(func(ctx context.Context, stream pkgconfig.TriggerStream, line string) {
+		lines = append(lines, string(stream)+":"+line)
+	})
+
+	// Act
+	err := multiPlexGameMock.multiplexGame.createLogStreams(multiPlexGameMock.ctx, "")
+	_, _ = multiPlexGameMock.multiplexGame.stdout.Write([]byte("first\nsec"))
+	_, _ = multiPlexGameMock.multiplexGame.stderr.Write([]byte("failure\r\n"))
+	_, _ = multiPlexGameMock.multiplexGame.stdout.Write([]byte("ond\n"))
+
+	//Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"stdout:first", "stderr:failure", "stdout:second"}, lines)
+}
+
+func TestStopWaitsForTerminationMargin(t *testing.T) {
+	// Arrange
+	noticeFile := filepath.Join(t.TempDir(), "termination-notice")
+	cfg := config.Config{
+		TerminationNotice: pkgconfig.TerminationNotice{
+			Enabled:      true,
+			Margin:       time.Minute,
+			File:         noticeFile,
+			StdinCommand: "shutdown {{.TerminationTime}}",
+			Signal:       "SIGUSR1",
+		},
+	}
+	if runtime.GOOS == "windows" {
+		cfg.TerminationNotice.Signal = ""
+	}
+	multiPlexGameMock := createMultiPlexGameWithMocks(cfg)
+	assert.NotNil(t, multiPlexGameMock.multiplexGame)
+	processMock := &mocks.ProcessMock{}
+	multiPlexGameMock.multiplexGame.proc = processMock
+	stdinReader, stdinWriter, err := os.Pipe()
+	assert.NoError(t, err)
+	defer stdinReader.Close()
+	multiPlexGameMock.multiplexGame.stdin = stdinWriter
+	ctx, cancel := context.WithCancel(multiPlexGameMock.ctx)
+	multiPlexGameMock.multiplexGame.cancel = cancel
+	terminationTime := time.Now().Add(time.Minute + time.Second)
+	stopCtx := game.WithTerminationTime(multiPlexGameMock.ctx, terminationTime)
+
+	// Act
+	started := time.Now()
+	err = multiPlexGameMock.multiplexGame.Stop(stopCtx)
+	elapsed := time.Since(started)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Error(t, ctx.Err())
+	assert.GreaterOrEqual(t, elapsed, 900*time.Millisecond)
+	notice, err := os.ReadFile(noticeFile)
+	assert.NoError(t, err)
+	assert.Equal(t, terminationTime.UTC().Format(time.RFC3339)+"\n", string(notice))
+	line := make([]byte, 64)
+	n, err := stdinReader.Read(line)
+	assert.NoError(t, err)
+	assert.Equal(t, "shutdown "+terminationTime.UTC().Format(time.RFC3339)+"\n", string(line[:n]))
+	if runtime.GOOS != "windows" {
+		assert.Len(t, processMock.Signals, 1)
+	}
+}
+
+func TestStopWithoutTerminationNoticeDoesNotWait(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	multiPlexGameMock := createMultiPlexGameWithMocks(cfg)
+	_, cancel := context.WithCancel(multiPlexGameMock.ctx)
+	multiPlexGameMock.multiplexGame.cancel = cancel
+	stopCtx := game.WithTerminationTime(multiPlexGameMock.ctx, time.Now().Add(time.Hour))
+
+	// Act
+	started := time.Now()
+	err := multiPlexGameMock.multiplexGame.Stop(stopCtx)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Less(t, time.Since(started), time.Second)
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package multiplexgame
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/game"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// outcomeRecorder publishes the outcome of a game session to the game-run span, the metrics,
+// the logs and a summary file in the run log directory.
+type outcomeRecorder struct {
+	logger    *slog.Logger
+	sessions  metric.Int64Counter
+	durations metric.Float64Histogram
+}
+
+func (outcomeRecorder *outcomeRecorder) record(ctx context.Context, span trace.Span, outcome *game.Outcome, logDirectory string) {
+	attributes := []attribute.KeyValue{
+		attribute.String("classification", string(outcome.Classification)),
+		attribute.String("trigger", string(outcome.Trigger)),
+	}
+
+	span.SetAttributes(
+		attribute.String("game.outcome.classification", string(outcome.Classification)),
+		attribute.String("game.outcome.trigger", string(outcome.Trigger)),
+		attribute.Int("game.outcome.exit_code", outcome.ExitCode),
+		attribute.String("game.outcome.signal", outcome.Signal),
+		attribute.Float64("game.outcome.duration_seconds", outcome.Duration.Seconds()),
+	)
+
+	outcomeRecorder.sessions.Add(ctx, 1, metric.WithAttributes(attributes...))
+	outcomeRecorder.durations.Record(ctx, outcome.Duration.Seconds(), metric.WithAttributes(attributes...))
+
+	if len(logDirectory) != 0 {
+		if err := writeOutcome(outcome, logDirectory); err != nil {
+			outcomeRecorder.logger.ErrorContext(ctx, "Failed to write game session outcome", "dir", logDirectory, "error", err)
+		}
+	}
+
+	outcomeRecorder.logger.InfoContext(ctx, "Game session ended",
+		"gameSessionId", outcome.GameSessionId,
+		"classification", outcome.Classification,
+		"trigger", outcome.Trigger,
+		"exitCode", outcome.ExitCode,
+		"signal", outcome.Signal,
+		"duration", outcome.Duration,
+		"error", outcome.Error)
+}
+
+func writeOutcome(outcome *game.Outcome, logDirectory string) error {
+	b, err := json.MarshalIndent(outcome, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal outcome")
+	}
+
+	path := filepath.Join(logDirectory, game.OutcomeFileName)
+	if err := os.WriteFile(path, b, 0644); err != nil {
+		return errors.Wrapf(err, "failed to write outcome file %s", path)
+	}
+
+	return nil
+}
+
+func newOutcomeRecorder(logger *slog.Logger, meter metric.Meter) (*outcomeRecorder, error) {
+	sessions, err := meter.Int64Counter("game.session.outcomes",
+		metric.WithDescription("Number of game sessions ended, by classification and trigger"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create game session outcome counter")
+	}
+
+	durations, err := meter.Float64Histogram("game.session.duration",
+		metric.WithDescription("Time the game process ran for a game session"),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create game session duration histogram")
+	}
+
+	return &outcomeRecorder{
+		logger:    logger,
+		sessions:  sessions,
+		durations: durations,
+	}, nil
+}
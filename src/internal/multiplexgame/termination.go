@@ -0,0 +1,150 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package multiplexgame
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+	"time"
+
+	pkgconfig "github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/process"
+)
+
+// terminationNotice tells the game process about a scheduled termination before it is stopped.
+type terminationNotice struct {
+	cfg          pkgconfig.TerminationNotice
+	stdinCommand *template.Template
+	signal       os.Signal
+}
+
+// noticeArgs are the fields available to the stdin command template.
+type noticeArgs struct {
+	TerminationTime string // RFC3339, in UTC
+	Seconds         int    // seconds left before the termination time
+	StopSeconds     int    // seconds left before the game process is stopped
+}
+
+// newTerminationNotice prepares the configured notifications, it returns nil when the notice is disabled.
+func newTerminationNotice(cfg pkgconfig.TerminationNotice) (*terminationNotice, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	notice := &terminationNotice{cfg: cfg}
+
+	if len(cfg.StdinCommand) != 0 {
+		tmpl, err := template.New("stdin-command").Option("missingkey=error").Parse(cfg.StdinCommand)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse termination notice stdin command: %w", err)
+		}
+		notice.stdinCommand = tmpl
+	}
+
+	if len(cfg.Signal) != 0 {
+		sig, err := process.ParseSignal(cfg.Signal)
+		if err != nil {
+			return nil, fmt.Errorf("invalid termination notice signal: %w", err)
+		}
+		notice.signal = sig
+	}
+
+	return notice, nil
+}
+
+// awaitTermination notifies the game process of the termination time, then waits until the margin before it
+// so the game session can wrap up. It returns early when the game process exits or the context is done.
+func (multiplexGame *MultiplexGame) awaitTermination(ctx context.Context, terminationTime time.Time, done <-chan struct{}) {
+	stopAt := terminationTime.Add(-multiplexGame.notice.cfg.Margin)
+	multiplexGame.logger.InfoContext(ctx, "Game server termination scheduled, notifying the game process",
+		"terminationTime", terminationTime,
+		"stopAt", stopAt)
+
+	multiplexGame.notifyTermination(ctx, terminationTime, stopAt)
+
+	timer := time.NewTimer(time.Until(stopAt))
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		multiplexGame.logger.InfoContext(ctx, "Game process exited before the termination time")
+	case <-timer.C:
+		multiplexGame.logger.InfoContext(ctx, "Stopping the game process ahead of the termination time", "terminationTime", terminationTime)
+	case <-ctx.Done():
+	}
+}
+
+// notifyTermination runs every configured notification. Failures are logged, they do not delay the termination.
+func (multiplexGame *MultiplexGame) notifyTermination(ctx context.Context, terminationTime time.Time, stopAt time.Time) {
+	notice := multiplexGame.notice
+
+	if len(notice.cfg.File) != 0 {
+		if err := writeTerminationFile(notice.cfg.File, terminationTime); err != nil {
+			multiplexGame.logger.WarnContext(ctx, "failed to write termination notice file", "file", notice.cfg.File, "err", err)
+		}
+	}
+
+	if notice.stdinCommand != nil {
+		if err := multiplexGame.writeStdinCommand(terminationTime, stopAt); err != nil {
+			multiplexGame.logger.WarnContext(ctx, "failed to write termination notice to game process stdin", "err", err)
+		}
+	}
+
+	if notice.signal != nil {
+		if err := multiplexGame.proc.Signal(notice.signal); err != nil {
+			multiplexGame.logger.WarnContext(ctx, "failed to send termination notice signal to game process", "signal", notice.signal, "err", err)
+		}
+	}
+}
+
+func (multiplexGame *MultiplexGame) writeStdinCommand(terminationTime time.Time, stopAt time.Time) error {
+	var line bytes.Buffer
+	err := multiplexGame.notice.stdinCommand.Execute(&line, noticeArgs{
+		TerminationTime: terminationTime.UTC().Format(time.RFC3339),
+		Seconds:         max(int(time.Until(terminationTime).Seconds()), 0),
+		StopSeconds:     max(int(time.Until(stopAt).Seconds()), 0),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render stdin command: %w", err)
+	}
+	line.WriteByte('\n')
+
+	multiplexGame.mutex.Lock()
+	stdin := multiplexGame.stdin
+	multiplexGame.mutex.Unlock()
+
+	if stdin == nil {
+		return fmt.Errorf("game process stdin is not open")
+	}
+
+	_, err = stdin.Write(line.Bytes())
+	return err
+}
+
+// writeTerminationFile replaces the termination notice file, so the game process never reads a partial write.
+func writeTerminationFile(file string, terminationTime time.Time) error {
+	tmp, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".*")
+	if err != nil {
+		return err
+	}
+
+	_, err = tmp.WriteString(terminationTime.UTC().Format(time.RFC3339) + "\n")
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), file)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+
+	return err
+}
@@ -14,12 +14,14 @@ import (
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/internal/config"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/internal/multiplexgame"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/game"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/logging"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/observability"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/ports"
 	"github.com/pkg/errors"
 )
 
-func getGame(ctx context.Context, cfg *config.Config, logger *slog.Logger, gl logging.Game, spanner observability.Spanner) (game.Server, error) {
+func getGame(ctx context.Context, cfg *config.Config, logger *slog.Logger, gl logging.Game, obs *observability.Observability, hostingService hosting.Service, portAllocator *ports.Allocator) (game.Server, error) {
 	if cfg == nil {
 		return nil, errors.New("Configuration not provided when getting the game")
 	}
@@ -37,11 +39,18 @@ func getGame(ctx context.Context, cfg *config.Config, logger *slog.Logger, gl lo
 		return nil, errors.Errorf("Game server executable not found at path: %s", pathToCheck)
 	}
 
-	multiplexGame, err := multiplexgame.New(*cfg, logger, gl, spanner)
+	multiplexGame, err := multiplexgame.New(*cfg, logger, gl, obs.Spanner, obs.Meter)
 
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to initialize multiplex game")
 	}
 
+	// the reserved ports are freed for the game process to bind them
+	multiplexGame.SetOnLaunch(portAllocator.Release)
+
+	if err := attachLogTriggers(ctx, cfg, multiplexGame, hostingService, logger, obs); err != nil {
+		return nil, errors.Wrap(err, "Failed to initialize log triggers")
+	}
+
 	return multiplexGame, nil
 }
@@ -13,22 +13,60 @@ import (
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting/gamelift/sdk"
 
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/internal/config"
+	pkgconfig "github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting/agones"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting/gamelift"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting/local"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/observability"
 )
 
-func getHosting(ctx context.Context, cfg *config.Config, logger *slog.Logger, spanner observability.Spanner) (hosting.Service, error) {
+func getHosting(ctx context.Context, cfg *config.Config, logger *slog.Logger, obs *observability.Observability) (hosting.Service, error) {
+	if cfg.Hosting.Provider == pkgconfig.ProviderLocal {
+		logger.DebugContext(ctx, "Initializing local hosting service")
+		return local.New(ctx, &local.Config{
+			GamePort:            cfg.Ports.GamePort,
+			ScenarioFile:        cfg.Hosting.Local.ScenarioFile,
+			ApiAddress:          cfg.Hosting.Local.ApiAddress,
+			HealthCheckInterval: cfg.Hosting.Local.HealthCheckInterval,
+			ResultsFile:         cfg.Hosting.Local.ResultsFile,
+		},
+			logger,
+			obs.Spanner,
+		)
+	}
+
+	if cfg.Hosting.Provider == pkgconfig.ProviderAgones {
+		logger.DebugContext(ctx, "Initializing Agones hosting service")
+		return agones.New(ctx, &agones.Config{
+			GamePort:            cfg.Ports.GamePort,
+			SdkAddress:          cfg.Hosting.Agones.SdkAddress,
+			HealthCheckInterval: cfg.Hosting.Agones.HealthCheckInterval,
+			PortName:            cfg.Hosting.Agones.PortName,
+			MetadataPrefix:      cfg.Hosting.Agones.MetadataPrefix,
+		},
+			logger,
+			obs.Spanner,
+		)
+	}
+
 	logger.DebugContext(ctx, "Initializing Amazon GameLift hosting service")
 	return gamelift.New(ctx, &gamelift.Config{
 		GamePort:               cfg.Ports.GamePort,
 		Anywhere:               cfg.Hosting.GameLift.Anywhere,
 		LogDirectory:           cfg.Hosting.LogDirectory,
 		GameServerLogDirectory: cfg.Hosting.AbsoluteGameServerLogDirectory,
+		WaitForReady:           cfg.LogTriggers.WaitForReady,
+		FleetRoleCredentials:   cfg.Hosting.GameLift.FleetRoleCredentials,
+		ComputeCertificate:     cfg.Hosting.GameLift.ComputeCertificate,
+		LogCollection:          cfg.Hosting.LogCollection,
+		LogPaths:               cfg.Hosting.LogPaths,
+		PlayerTracking:         cfg.Hosting.GameLift.PlayerTracking,
 	},
 		logger,
-		spanner,
+		obs.Spanner,
+		obs.Meter,
 		&initialiser.InitialiserServiceFactory{},
-		sdk.NewSdk(ctx, logger),
+		sdk.NewSdk(ctx, logger, obs.Spanner, cfg.Hosting.GameLift.SdkRetry),
 	)
 }
@@ -0,0 +1,34 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package services
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/internal/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/ports"
+)
+
+// allocatePorts picks the ports of the game server and fills them into the configuration. The ports stay
+// reserved until the returned allocator is released.
+func allocatePorts(ctx context.Context, cfg *config.Config, logger *slog.Logger) (*ports.Allocator, error) {
+	allocator := ports.New(logger)
+
+	allocated, err := allocator.Allocate(ctx, cfg.PortAllocation, cfg.Ports.GamePort)
+	if err != nil {
+		return nil, err
+	}
+
+	gamePort := allocated[ports.GamePortName]
+	cfg.Ports.GamePort = gamePort
+	cfg.Ports.Allocated = allocated
+	cfg.Hosting.GameLift.Port = gamePort
+	cfg.Hosting.GameLift.QueryPort = allocated["query"]
+	cfg.Hosting.GameLift.Anywhere.Host.GamePort = gamePort
+
+	return allocator, nil
+}
@@ -13,6 +13,9 @@ import (
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/runner"
 
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/internal/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/constants"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/drain"
+	pkghosting "github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/logging"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/manager"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/observability"
@@ -24,6 +27,7 @@ type Services struct {
 	Logger  *slog.Logger
 	Runner  *runner.Runner
 	Spanner observability.Spanner
+	Drainer *drain.Drainer // nil when the hosting cannot be drained
 }
 
 // Default initializes a new Services instance with all required components.
@@ -31,20 +35,38 @@ type Services struct {
 func Default(ctx context.Context, cfg *config.Config, logger *slog.Logger, obs *observability.Observability, gameLogger logging.Game) (*Services, error) {
 	logger.DebugContext(ctx, "Initializing game server wrapper services")
 
+	portAllocator, err := allocatePorts(ctx, cfg, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "Service initialization failed: failed to allocate ports")
+	}
+
 	if err := cfg.Validate(); err != nil {
+		portAllocator.Release()
 		return nil, errors.Wrap(err, "Service initialization failed: invalid configuration")
 	}
 
-	hosting, err := getHosting(ctx, cfg, logger, obs.Spanner)
+	hosting, err := getHosting(ctx, cfg, logger, obs)
 	if err != nil {
+		portAllocator.Release()
 		return nil, errors.Wrapf(err, "Service initialization failed: failed to get hosting")
 	}
 
-	game, err := getGame(ctx, cfg, logger, gameLogger, obs.Spanner)
+	game, err := getGame(ctx, cfg, logger, gameLogger, obs, hosting, portAllocator)
 	if err != nil {
+		portAllocator.Release()
 		return nil, errors.Wrapf(err, "Service initialization failed: failed to get game")
 	}
 
+	var drainer *drain.Drainer
+	if drainable, ok := hosting.(pkghosting.Drainable); ok {
+		appDir, _ := ctx.Value(string(constants.ContextKeyAppDir)).(string)
+		drainer, err = drain.New(cfg.Drain, appDir, drainable, logger)
+		if err != nil {
+			portAllocator.Release()
+			return nil, errors.Wrapf(err, "Service initialization failed: failed to get drainer")
+		}
+	}
+
 	logger.DebugContext(ctx, "Creating game manager instance")
 	managerInstance := manager.New(&manager.Config{}, game, hosting, logger, obs.Spanner, manager.NewHarness(game, logger, obs.Spanner))
 
@@ -59,6 +81,7 @@ func Default(ctx context.Context, cfg *config.Config, logger *slog.Logger, obs *
 		Logger:  logger,
 		Runner:  runnerInstance,
 		Spanner: obs.Spanner,
+		Drainer: drainer,
 	}
 
 	logger.DebugContext(ctx, "Game server wrapper services initialized successfully")
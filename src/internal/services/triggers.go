@@ -0,0 +1,81 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package services
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/internal/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/internal/multiplexgame"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/observability"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/triggers"
+	"github.com/pkg/errors"
+)
+
+// triggerActions runs the actions of log trigger rules against the game and the hosting provider.
+type triggerActions struct {
+	game    *multiplexgame.MultiplexGame
+	session hosting.SessionActions // nil when the hosting provider does not support game session actions
+}
+
+func (actions *triggerActions) MarkReady(ctx context.Context) error {
+	if actions.session == nil {
+		return errors.New("the hosting provider does not support activating game sessions")
+	}
+	return actions.session.ActivateGameSession(ctx)
+}
+
+func (actions *triggerActions) MarkUnhealthy(ctx context.Context, reason string) error {
+	actions.game.MarkUnhealthy(ctx, reason)
+	return nil
+}
+
+func (actions *triggerActions) AcceptPlayerSession(ctx context.Context, playerSessionId string) error {
+	if actions.session == nil {
+		return errors.New("the hosting provider does not support player sessions")
+	}
+	return actions.session.AcceptPlayerSession(ctx, playerSessionId)
+}
+
+func (actions *triggerActions) RemovePlayerSession(ctx context.Context, playerSessionId string) error {
+	if actions.session == nil {
+		return errors.New("the hosting provider does not support player sessions")
+	}
+	return actions.session.RemovePlayerSession(ctx, playerSessionId)
+}
+
+func (actions *triggerActions) TerminateSession(ctx context.Context, reason string) error {
+	return actions.game.Terminate(ctx, reason)
+}
+
+// attachLogTriggers applies the configured log trigger rules to the output of the game, if there are any.
+func attachLogTriggers(ctx context.Context, cfg *config.Config, multiplexGame *multiplexgame.MultiplexGame, hostingService hosting.Service, logger *slog.Logger, obs *observability.Observability) error {
+	if len(cfg.LogTriggers.Rules) == 0 && len(cfg.LogTriggers.RulesFile) == 0 {
+		return nil
+	}
+
+	actions := &triggerActions{game: multiplexGame}
+	if session, ok := hostingService.(hosting.SessionActions); ok {
+		actions.session = session
+	}
+
+	engine, err := triggers.New(cfg.LogTriggers, actions, logger, obs.Meter)
+	if err != nil {
+		return errors.Wrap(err, "failed to create log trigger engine")
+	}
+
+	if err := engine.Watch(ctx); err != nil {
+		logger.WarnContext(ctx, "log trigger rules will not be reloaded", "err", err)
+	}
+
+	logger.DebugContext(ctx, "Log triggers enabled", "rules", len(engine.Rules()), "rulesFile", cfg.LogTriggers.RulesFile)
+	engine.Start(ctx)
+	multiplexGame.SetOnOutput(engine.Enqueue)
+
+	return nil
+}
@@ -0,0 +1,231 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package agonesserver is a test-only stand-in for the REST API of the Agones SDK sidecar.
+// It lets the Agones hosting provider be tested without a Kubernetes cluster.
+package agonesserver
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// GameServer is the GameServer served by the stand-in, in the format of the sidecar REST API.
+type GameServer struct {
+	ObjectMeta ObjectMeta `json:"object_meta"`
+	Status     Status     `json:"status"`
+}
+
+// ObjectMeta is the Kubernetes metadata of the GameServer.
+type ObjectMeta struct {
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Status is the state, address and ports of the GameServer.
+type Status struct {
+	State   string   `json:"state"`
+	Address string   `json:"address"`
+	Ports   []Port   `json:"ports,omitempty"`
+	Players *Players `json:"players,omitempty"`
+}
+
+// Port is a port of the GameServer.
+type Port struct {
+	Name string `json:"name"`
+	Port int    `json:"port"`
+}
+
+// Players is the player tracking status of the GameServer.
+type Players struct {
+	Count    int64 `json:"count"`
+	Capacity int64 `json:"capacity"`
+}
+
+// Server serves a single GameServer, records the calls it receives and pushes every change of the GameServer
+// to the open watch streams.
+type Server struct {
+	httpServer *httptest.Server
+
+	mutex      sync.Mutex
+	gameServer GameServer
+	calls      []string
+	watchers   map[chan GameServer]struct{}
+}
+
+// New starts a stand-in server listening on the loopback interface, serving the given GameServer.
+func New(gameServer GameServer) *Server {
+	server := &Server{
+		gameServer: gameServer,
+		watchers:   make(map[chan GameServer]struct{}),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /ready", server.setState("ready", "Ready"))
+	mux.HandleFunc("POST /shutdown", server.setState("shutdown", "Shutdown"))
+	mux.HandleFunc("POST /allocate", server.setState("allocate", "Allocated"))
+	mux.HandleFunc("POST /health", func(w http.ResponseWriter, r *http.Request) {
+		server.record("health")
+		writeJSON(w, struct{}{})
+	})
+	mux.HandleFunc("GET /gameserver", func(w http.ResponseWriter, r *http.Request) {
+		server.record("gameserver")
+		writeJSON(w, server.GameServer())
+	})
+	mux.HandleFunc("GET /watch/gameserver", server.watch)
+
+	server.httpServer = httptest.NewServer(mux)
+	return server
+}
+
+// Address returns the host and port of the server, to use as the Agones SDK address.
+func (server *Server) Address() string {
+	return strings.TrimPrefix(server.httpServer.URL, "http://")
+}
+
+// Close ends the watch streams and stops the server.
+func (server *Server) Close() {
+	server.httpServer.CloseClientConnections()
+	server.httpServer.Close()
+}
+
+// Allocate allocates the GameServer with the given labels and annotations, as a GameServerAllocation does.
+func (server *Server) Allocate(labels map[string]string, annotations map[string]string) {
+	server.update(func(gameServer *GameServer) {
+		if gameServer.ObjectMeta.Labels == nil {
+			gameServer.ObjectMeta.Labels = make(map[string]string)
+		}
+		if gameServer.ObjectMeta.Annotations == nil {
+			gameServer.ObjectMeta.Annotations = make(map[string]string)
+		}
+		for key, value := range labels {
+			gameServer.ObjectMeta.Labels[key] = value
+		}
+		for key, value := range annotations {
+			gameServer.ObjectMeta.Annotations[key] = value
+		}
+		gameServer.Status.State = "Allocated"
+	})
+}
+
+// Shutdown shuts the GameServer down, as deleting it does.
+func (server *Server) Shutdown() {
+	server.update(func(gameServer *GameServer) {
+		gameServer.Status.State = "Shutdown"
+	})
+}
+
+// GameServer returns the current GameServer.
+func (server *Server) GameServer() GameServer {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	return server.gameServer.clone()
+}
+
+// Calls returns the name of every call received, optionally filtered by name.
+func (server *Server) Calls(names ...string) []string {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	calls := make([]string, 0)
+	for _, call := range server.calls {
+		if len(names) == 0 || slices.Contains(names, call) {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+func (server *Server) setState(call string, state string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		server.record(call)
+		server.update(func(gameServer *GameServer) {
+			gameServer.Status.State = state
+		})
+		writeJSON(w, struct{}{})
+	}
+}
+
+func (server *Server) watch(w http.ResponseWriter, r *http.Request) {
+	server.record("watch")
+
+	changes := make(chan GameServer, 16)
+	server.mutex.Lock()
+	server.watchers[changes] = struct{}{}
+	current := server.gameServer.clone()
+	server.mutex.Unlock()
+
+	defer func() {
+		server.mutex.Lock()
+		delete(server.watchers, changes)
+		server.mutex.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	encoder := json.NewEncoder(w)
+	gameServer := current
+	for {
+		if err := encoder.Encode(struct {
+			Result GameServer `json:"result"`
+		}{Result: gameServer}); err != nil {
+			return
+		}
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case gameServer = <-changes:
+		}
+	}
+}
+
+func (server *Server) update(f func(gameServer *GameServer)) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	f(&server.gameServer)
+	gameServer := server.gameServer.clone()
+	for watcher := range server.watchers {
+		select {
+		case watcher <- gameServer:
+		default:
+		}
+	}
+}
+
+func (server *Server) record(call string) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.calls = append(server.calls, call)
+}
+
+// clone copies the GameServer, so it can be encoded while the served one changes.
+func (gameServer GameServer) clone() GameServer {
+	gameServer.ObjectMeta.Labels = maps.Clone(gameServer.ObjectMeta.Labels)
+	gameServer.ObjectMeta.Annotations = maps.Clone(gameServer.ObjectMeta.Annotations)
+	gameServer.Status.Ports = slices.Clone(gameServer.Status.Ports)
+	if gameServer.Status.Players != nil {
+		players := *gameServer.Status.Players
+		gameServer.Status.Players = &players
+	}
+	return gameServer
+}
+
+func writeJSON(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
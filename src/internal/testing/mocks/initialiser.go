@@ -11,6 +11,8 @@ type InitMock struct {
 	InitCtx context.Context
 	InitErr error
 
+	StopCalled bool
+
 	CloseCtx context.Context
 	CloseErr error
 }
@@ -20,6 +22,10 @@ func (i *InitMock) InitSdk(ctx context.Context) error {
 	return i.InitErr
 }
 
+func (i *InitMock) Stop() {
+	i.StopCalled = true
+}
+
 func (i *InitMock) Close(ctx context.Context) error {
 	i.CloseCtx = ctx
 	return i.CloseErr
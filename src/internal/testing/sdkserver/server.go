@@ -0,0 +1,318 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package sdkserver is a test-only stand-in for the Amazon GameLift service SDK websocket endpoint.
+// It lets the real server SDK connect locally so the wrapper can be integration-tested without a fleet.
+package sdkserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/message"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/request"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+)
+
+// Connection holds the query parameters the SDK connected with.
+type Connection struct {
+	ProcessId string
+	ComputeId string
+	FleetId   string
+	AuthToken string
+}
+
+// Request is a request received from the SDK.
+type Request struct {
+	Action    message.MessageAction
+	RequestId string
+	Body      []byte
+}
+
+// ProcessReady is an ActivateServerProcess request received from the SDK.
+type ProcessReady struct {
+	Port        int
+	LogPaths    []string
+	SdkToolName string
+}
+
+// GameSession is the game session pushed to the SDK with CreateGameSession.
+type GameSession struct {
+	GameSessionId             string
+	Name                      string
+	IpAddress                 string
+	DnsName                   string
+	Port                      int
+	GameProperties            map[string]string
+	GameSessionData           string
+	MatchmakerData            string
+	MaximumPlayerSessionCount int
+}
+
+type failure struct {
+	statusCode   int
+	errorMessage string
+}
+
+// Server accepts a single SDK websocket connection at a time, acknowledges its requests
+// and records what it received.
+type Server struct {
+	httpServer *httptest.Server
+	upgrader   websocket.Upgrader
+
+	mutex       sync.Mutex
+	writeMutex  sync.Mutex
+	conn        *websocket.Conn
+	connections []Connection
+	requests    []Request
+	failures    map[message.MessageAction]failure
+	changed     chan struct{}
+}
+
+// New starts a stand-in server listening on the loopback interface.
+func New() *Server {
+	server := &Server{
+		failures: make(map[message.MessageAction]failure),
+		changed:  make(chan struct{}),
+	}
+	server.httpServer = httptest.NewServer(http.HandlerFunc(server.serve))
+
+	return server
+}
+
+// URL returns the websocket URL to use as the service SDK endpoint.
+func (server *Server) URL() string {
+	return "ws" + strings.TrimPrefix(server.httpServer.URL, "http")
+}
+
+// Close disconnects the SDK and stops the server.
+func (server *Server) Close() {
+	server.mutex.Lock()
+	if server.conn != nil {
+		_ = server.conn.Close()
+	}
+	server.mutex.Unlock()
+
+	server.httpServer.CloseClientConnections()
+	server.httpServer.Close()
+}
+
+// FailRequests makes the server answer every request with the given action with an error status.
+func (server *Server) FailRequests(action message.MessageAction, statusCode int, errorMessage string) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.failures[action] = failure{statusCode: statusCode, errorMessage: errorMessage}
+}
+
+// CreateGameSession pushes a CreateGameSession message to the connected SDK.
+func (server *Server) CreateGameSession(gameSession GameSession) error {
+	msg := struct {
+		message.CreateGameSessionMessage
+		StatusCode int `json:"StatusCode"`
+	}{
+		CreateGameSessionMessage: message.CreateGameSessionMessage{
+			Message:                   message.NewMessage(message.CreateGameSession),
+			MaximumPlayerSessionCount: gameSession.MaximumPlayerSessionCount,
+			Port:                      gameSession.Port,
+			IPAddress:                 gameSession.IpAddress,
+			GameSessionID:             gameSession.GameSessionId,
+			GameSessionName:           gameSession.Name,
+			GameSessionData:           gameSession.GameSessionData,
+			MatchmakerData:            gameSession.MatchmakerData,
+			DNSName:                   gameSession.DnsName,
+			GameProperties:            gameSession.GameProperties,
+		},
+		StatusCode: http.StatusOK,
+	}
+
+	return server.write(msg)
+}
+
+// TerminateProcess pushes a TerminateProcess message to the connected SDK.
+func (server *Server) TerminateProcess(terminationTime time.Time) error {
+	msg := struct {
+		message.TerminateProcessMessage
+		StatusCode int `json:"StatusCode"`
+	}{
+		TerminateProcessMessage: message.TerminateProcessMessage{
+			Message:         message.NewMessage(message.TerminateProcess),
+			TerminationTime: terminationTime.UnixMilli(),
+		},
+		StatusCode: http.StatusOK,
+	}
+
+	return server.write(msg)
+}
+
+// Connections returns every connection the SDK has opened.
+func (server *Server) Connections() []Connection {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	return append([]Connection{}, server.connections...)
+}
+
+// Requests returns every request received from the SDK, optionally filtered by action.
+func (server *Server) Requests(actions ...message.MessageAction) []Request {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	requests := make([]Request, 0)
+	for _, r := range server.requests {
+		if len(actions) == 0 || containsAction(actions, r.Action) {
+			requests = append(requests, r)
+		}
+	}
+	return requests
+}
+
+// ProcessReadyRequests returns the ActivateServerProcess requests sent by ProcessReady.
+func (server *Server) ProcessReadyRequests() []ProcessReady {
+	result := make([]ProcessReady, 0)
+	for _, r := range server.Requests(message.ActivateServerProcess) {
+		var req request.ActivateServerProcessRequest
+		if err := json.Unmarshal(r.Body, &req); err == nil {
+			result = append(result, ProcessReady{Port: req.Port, LogPaths: req.LogPaths, SdkToolName: req.SdkToolName})
+		}
+	}
+	return result
+}
+
+// HealthReports returns the health status of every heartbeat sent by the SDK.
+func (server *Server) HealthReports() []bool {
+	result := make([]bool, 0)
+	for _, r := range server.Requests(message.HeartbeatServerProcess) {
+		var req request.HeartbeatServerProcessRequest
+		if err := json.Unmarshal(r.Body, &req); err == nil {
+			result = append(result, req.HealthStatus)
+		}
+	}
+	return result
+}
+
+// ActivatedGameSessions returns the game session ids sent by ActivateGameSession.
+func (server *Server) ActivatedGameSessions() []string {
+	result := make([]string, 0)
+	for _, r := range server.Requests(message.ActivateGameSession) {
+		var req request.ActivateGameSessionRequest
+		if err := json.Unmarshal(r.Body, &req); err == nil {
+			result = append(result, req.GameSessionID)
+		}
+	}
+	return result
+}
+
+// WaitFor blocks until the condition holds or the timeout expires. The condition is
+// re-evaluated whenever a connection or request is received.
+func (server *Server) WaitFor(timeout time.Duration, condition func() bool) error {
+	expire := time.After(timeout)
+	for {
+		server.mutex.Lock()
+		changed := server.changed
+		server.mutex.Unlock()
+
+		if condition() {
+			return nil
+		}
+
+		select {
+		case <-changed:
+		case <-expire:
+			return errors.Errorf("condition not met within %s", timeout)
+		}
+	}
+}
+
+func (server *Server) serve(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	conn, err := server.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	server.mutex.Lock()
+	server.conn = conn
+	server.connections = append(server.connections, Connection{
+		ProcessId: query.Get("pID"),
+		ComputeId: query.Get("ComputeId"),
+		FleetId:   query.Get("FleetId"),
+		AuthToken: query.Get("Authorization"),
+	})
+	server.notify()
+	server.mutex.Unlock()
+
+	defer conn.Close()
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var msg message.Message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			continue
+		}
+
+		server.mutex.Lock()
+		server.requests = append(server.requests, Request{Action: msg.Action, RequestId: msg.RequestID, Body: data})
+		f, failed := server.failures[msg.Action]
+		server.notify()
+		server.mutex.Unlock()
+
+		response := message.ResponseMessage{Message: msg, StatusCode: http.StatusOK}
+		if failed {
+			response.StatusCode = f.statusCode
+			response.ErrorMessage = f.errorMessage
+		}
+
+		if err := server.writeTo(conn, response); err != nil {
+			return
+		}
+	}
+}
+
+// notify wakes up WaitFor callers, the mutex must be held.
+func (server *Server) notify() {
+	close(server.changed)
+	server.changed = make(chan struct{})
+}
+
+func (server *Server) write(v any) error {
+	server.mutex.Lock()
+	conn := server.conn
+	server.mutex.Unlock()
+
+	if conn == nil {
+		return errors.New("no sdk connected")
+	}
+
+	return server.writeTo(conn, v)
+}
+
+func (server *Server) writeTo(conn *websocket.Conn, v any) error {
+	server.writeMutex.Lock()
+	defer server.writeMutex.Unlock()
+
+	return errors.Wrap(conn.WriteJSON(v), "failed to write to sdk")
+}
+
+func containsAction(actions []message.MessageAction, action message.MessageAction) bool {
+	for _, a := range actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// NewGameSessionId returns a game session id in the format used by Amazon GameLift.
+func NewGameSessionId(fleetId string) string {
+	return "arn:aws:gamelift:us-west-2::gamesession/" + fleetId + "/custom-location/gsess-" + uuid.NewString()
+}
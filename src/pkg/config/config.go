@@ -5,18 +5,33 @@
 
 package config
 
+import "time"
+
 // Provider represents the hosting service provider type for the game server.
-// It supports GameLift as the primary provider.
+// It supports GameLift as the primary provider, Agones for Kubernetes, and a local provider for offline testing.
 type Provider string
 
 const (
 	ProviderGameLift Provider = "gamelift"
+	ProviderLocal    Provider = "local"
+	ProviderAgones   Provider = "agones"
 )
 
 // Hosting defines logging directory configurations.
 type Hosting struct {
-	LogDirectory                   string `mapstructure:"logDirectory" yaml:"logDirectory"`
-	AbsoluteGameServerLogDirectory string `mapstructure:"gameServerLogDirectory" yaml:"gameServerLogDirectory"`
+	LogDirectory                   string        `mapstructure:"logDirectory" yaml:"logDirectory"`
+	AbsoluteGameServerLogDirectory string        `mapstructure:"gameServerLogDirectory" yaml:"gameServerLogDirectory"`
+	LogCollection                  LogCollection `mapstructure:"logCollection" yaml:"logCollection"`
+	LogPaths                       []string      `mapstructure:"logPaths" yaml:"logPaths"` // extra absolute directories, files or globs uploaded with the run logs
+}
+
+// LogCollection controls which game server logs are collected into the run log directory for upload.
+type LogCollection struct {
+	Include      []string `mapstructure:"include" yaml:"include"`           // globs of the files collected, all files when empty
+	Exclude      []string `mapstructure:"exclude" yaml:"exclude"`           // globs of the files skipped, applied after include
+	MaxFileSize  int64    `mapstructure:"maxFileSize" yaml:"maxFileSize"`   // bytes kept from the end of each file, 0 for no limit
+	MaxTotalSize int64    `mapstructure:"maxTotalSize" yaml:"maxTotalSize"` // bytes collected from all files, 0 for no limit
+	Compress     bool     `mapstructure:"compress" yaml:"compress"`         // gzip the collected files
 }
 
 // ProcessInfo contains the game server process execution configuration.
@@ -27,50 +42,320 @@ type ProcessInfo struct {
 
 // GameLift configuration for Amazon GameLift service.
 type GameLift struct {
-	Anywhere Anywhere `mapstructure:"anywhere" yaml:"anywhere"`
+	Anywhere             Anywhere                 `mapstructure:"anywhere" yaml:"anywhere"`
+	FleetRoleCredentials FleetRoleCredentials     `mapstructure:"fleetRoleCredentials" yaml:"fleetRoleCredentials"`
+	ComputeCertificate   ComputeCertificatePolicy `mapstructure:"computeCertificate" yaml:"computeCertificate"`
+	SdkRetry             SdkRetry                 `mapstructure:"sdkRetry" yaml:"sdkRetry"`
+	PlayerTracking       PlayerTracking           `mapstructure:"playerTracking" yaml:"playerTracking"`
 	/// To be filled in by another source, not config
 	Port      int `mapstructure:"-" yaml:"-"`
 	QueryPort int `mapstructure:"-" yaml:"-"`
 }
 
+// SdkRetry configures how failed InitSDK and ProcessReady calls are retried. Unset fields take the defaults.
+type SdkRetry struct {
+	Attempts       int           `mapstructure:"attempts" yaml:"attempts"`             // including the first attempt
+	InitialBackoff time.Duration `mapstructure:"initialBackoff" yaml:"initialBackoff"` // doubled after every failed attempt
+	MaxBackoff     time.Duration `mapstructure:"maxBackoff" yaml:"maxBackoff"`
+	Jitter         float64       `mapstructure:"jitter" yaml:"jitter"`           // fraction of the backoff randomized, from 0 to 1
+	CallTimeout    time.Duration `mapstructure:"callTimeout" yaml:"callTimeout"` // how long a single attempt is waited for
+}
+
+// ComputeCertificatePolicy controls whether the TLS certificate of a managed EC2 fleet compute is provided to the game server.
+type ComputeCertificatePolicy string
+
+const (
+	// ComputeCertificateDisabled does not request the certificate.
+	ComputeCertificateDisabled ComputeCertificatePolicy = "disabled"
+	// ComputeCertificateOptional provides the certificate when the fleet has one and launches the game server either way.
+	ComputeCertificateOptional ComputeCertificatePolicy = "optional"
+	// ComputeCertificateRequired fails when the fleet has no certificate.
+	ComputeCertificateRequired ComputeCertificatePolicy = "required"
+)
+
+// FleetRoleCredentials configures the loopback endpoint serving the fleet role credentials to the game server,
+// in the format of the AWS SDKs' container credentials provider.
+type FleetRoleCredentials struct {
+	RoleArn         string        `mapstructure:"roleArn" yaml:"roleArn"`                 // empty disables the endpoint
+	RoleSessionName string        `mapstructure:"roleSessionName" yaml:"roleSessionName"` // defaults to the fleet and host id
+	Address         string        `mapstructure:"address" yaml:"address"`                 // loopback address, defaults to a free port
+	RefreshBefore   time.Duration `mapstructure:"refreshBefore" yaml:"refreshBefore"`     // how long before expiry credentials are refreshed
+}
+
+// PlayerTracking configures how the players of the game session are tracked. Players accepted or removed by a
+// log trigger are always tracked.
+type PlayerTracking struct {
+	ApiAddress             string        `mapstructure:"apiAddress" yaml:"apiAddress"`                         // loopback address of the player API, empty disables it
+	ConnectionPollInterval time.Duration `mapstructure:"connectionPollInterval" yaml:"connectionPollInterval"` // interval between counts of the TCP connections to the game port, 0 disables it
+	EnforceCapacity        bool          `mapstructure:"enforceCapacity" yaml:"enforceCapacity"`               // deny new player sessions while the game session is full
+}
+
+// Local configures the offline hosting provider, which simulates Amazon GameLift session events
+// from a scripted scenario or a loopback HTTP API.
+type Local struct {
+	ScenarioFile        string        `mapstructure:"scenarioFile" yaml:"scenarioFile"`
+	ApiAddress          string        `mapstructure:"apiAddress" yaml:"apiAddress"`
+	HealthCheckInterval time.Duration `mapstructure:"healthCheckInterval" yaml:"healthCheckInterval"`
+	ResultsFile         string        `mapstructure:"resultsFile" yaml:"resultsFile"`
+}
+
+// Agones configures the Agones hosting provider, which talks to the Agones SDK sidecar of the game server pod.
+type Agones struct {
+	SdkAddress          string        `mapstructure:"sdkAddress" yaml:"sdkAddress"`                   // address of the sidecar REST API, defaults to localhost and AGONES_SDK_HTTP_PORT
+	HealthCheckInterval time.Duration `mapstructure:"healthCheckInterval" yaml:"healthCheckInterval"` // interval between health pings
+	PortName            string        `mapstructure:"portName" yaml:"portName"`                       // GameServer port given to the game, the first port when empty
+	MetadataPrefix      string        `mapstructure:"metadataPrefix" yaml:"metadataPrefix"`           // prefix of the labels and annotations passed to the game
+}
+
 // AnywhereHostConfig defines the configuration for an Amazon GameLift Anywhere host.
 type AnywhereHostConfig struct {
-	HostName           string `mapstructure:"hostname" yaml:"hostName"`
-	ServiceSdkEndpoint string `mapstructure:"serviceSdkEndpoint" yaml:"serviceSdkEndpoint"`
-	AuthToken          string `mapstructure:"authToken" yaml:"authToken"`
-	LocationArn        string `mapstructure:"locationArn" yaml:"locationArn"`
-	FleetArn           string `mapstructure:"fleetArn" yaml:"fleetArn"`
-	IPv4Address        string `mapstructure:"ipv4" yaml:"ipv4"`
+	HostName             string                     `mapstructure:"hostname" yaml:"hostName"`
+	ServiceSdkEndpoint   string                     `mapstructure:"serviceSdkEndpoint" yaml:"serviceSdkEndpoint"`
+	AuthToken            string                     `mapstructure:"authToken" yaml:"authToken"`
+	LocationArn          string                     `mapstructure:"locationArn" yaml:"locationArn"`
+	FleetArn             string                     `mapstructure:"fleetArn" yaml:"fleetArn"`
+	Fleet                string                     `mapstructure:"fleet" yaml:"fleet"`       // fleet id, fleet name or alias, resolved when the fleet arn is empty
+	Location             string                     `mapstructure:"location" yaml:"location"` // custom location name, used when the location arn is empty
+	IPv4Address          string                     `mapstructure:"ipv4" yaml:"ipv4"`
+	IPv6Address          string                     `mapstructure:"ipv6" yaml:"ipv6"`
+	AddressDiscovery     AddressDiscovery           `mapstructure:"addressDiscovery" yaml:"addressDiscovery"`
+	DeregisterCompute    DeregisterComputeMode      `mapstructure:"deregisterCompute" yaml:"deregisterCompute"`
+	ComputeNameTemplate  string                     `mapstructure:"computeNameTemplate" yaml:"computeNameTemplate"`
+	ComputeNameCollision ComputeNameCollisionPolicy `mapstructure:"computeNameCollision" yaml:"computeNameCollision"`
+	/// To be filled in by another source, not config
+	GamePort      int `mapstructure:"-" yaml:"-"`
+	InstanceIndex int `mapstructure:"-" yaml:"-"`
 }
 
+// ComputeNameCollisionPolicy controls what happens when the compute name is already registered
+// in the fleet with a different IP address than the one of this host.
+type ComputeNameCollisionPolicy string
+
+const (
+	// ComputeNameCollisionReuse uses the registered compute regardless of its address.
+	ComputeNameCollisionReuse ComputeNameCollisionPolicy = "reuse"
+	// ComputeNameCollisionFail stops the wrapper.
+	ComputeNameCollisionFail ComputeNameCollisionPolicy = "fail"
+	// ComputeNameCollisionSuffix appends a number to the compute name until it is free.
+	ComputeNameCollisionSuffix ComputeNameCollisionPolicy = "suffix"
+)
+
+// AddressSource is where the IP address registered for an Anywhere compute comes from.
+type AddressSource string
+
+const (
+	// AddressSourceStatic uses the configured IPv4 or IPv6 address.
+	AddressSourceStatic AddressSource = "static"
+	// AddressSourceInterface uses an address of a network interface, selected by name or CIDR.
+	AddressSourceInterface AddressSource = "interface"
+	// AddressSourceDefaultRoute uses the source address of the default route.
+	AddressSourceDefaultRoute AddressSource = "default-route"
+)
+
+// AddressFamily is the IP version of the address registered for an Anywhere compute.
+type AddressFamily string
+
+const (
+	AddressFamilyIPv4 AddressFamily = "ipv4"
+	AddressFamilyIPv6 AddressFamily = "ipv6"
+)
+
+// AddressDiscovery defines how the IP address of an Anywhere compute is found.
+type AddressDiscovery struct {
+	Source        AddressSource `mapstructure:"source" yaml:"source"`
+	Interface     string        `mapstructure:"interface" yaml:"interface"`
+	Cidr          string        `mapstructure:"cidr" yaml:"cidr"`
+	Family        AddressFamily `mapstructure:"family" yaml:"family"`
+	CheckInterval time.Duration `mapstructure:"checkInterval" yaml:"checkInterval"`
+}
+
+// DeregisterComputeMode controls whether an Anywhere compute is deregistered when the wrapper shuts down.
+// Computes pre-registered through a configured compute name are never deregistered.
+type DeregisterComputeMode string
+
+const (
+	// DeregisterComputeAuto deregisters the compute only if the wrapper registered it during this run.
+	DeregisterComputeAuto DeregisterComputeMode = "auto"
+	// DeregisterComputeKeep leaves the compute registered.
+	DeregisterComputeKeep DeregisterComputeMode = "keep"
+	// DeregisterComputeAlways deregisters the compute even if it was found already registered under the hostname.
+	DeregisterComputeAlways DeregisterComputeMode = "always"
+)
+
 // Anywhere defines the complete configuration for Amazon GameLift Anywhere deployment.
 type Anywhere struct {
-	Config AwsConfig          `mapstructure:"config" yaml:"config"`
-	Host   AnywhereHostConfig `mapstructure:"host" yaml:"host"`
+	Config  AwsConfig          `mapstructure:"config" yaml:"config"`
+	Host    AnywhereHostConfig `mapstructure:"host" yaml:"host"`
+	SdkInit SdkInitMode        `mapstructure:"sdkInit" yaml:"sdkInit"`
+	Network Network            `mapstructure:"network" yaml:"network"`
 }
 
+// Network defines how the wrapper reaches the Amazon GameLift API and the telemetry collector.
+type Network struct {
+	GameLiftEndpoint string        `mapstructure:"gameLiftEndpoint" yaml:"gameLiftEndpoint,omitempty"` // Replaces the regional Amazon GameLift API endpoint
+	Proxy            NetworkProxy  `mapstructure:"proxy" yaml:"proxy,omitempty"`
+	CaBundle         string        `mapstructure:"caBundle" yaml:"caBundle,omitempty"`             // PEM file of certificate authorities trusted on top of the system ones
+	ConnectTimeout   time.Duration `mapstructure:"connectTimeout" yaml:"connectTimeout,omitempty"` // Bounds establishing a connection, including the TLS handshake
+	RequestTimeout   time.Duration `mapstructure:"requestTimeout" yaml:"requestTimeout,omitempty"` // Bounds a whole request, including reading the response
+}
+
+// NetworkProxy defines the HTTP proxies used instead of the ones given by the HTTP_PROXY, HTTPS_PROXY and
+// NO_PROXY environment variables.
+type NetworkProxy struct {
+	Http    string `mapstructure:"http" yaml:"http,omitempty"`
+	Https   string `mapstructure:"https" yaml:"https,omitempty"`
+	NoProxy string `mapstructure:"noProxy" yaml:"noProxy,omitempty"` // Comma separated hosts, domains and CIDRs reached directly
+}
+
+// SdkInitMode selects where the parameters the server SDK is initialized with come from.
+type SdkInitMode string
+
+const (
+	// SdkInitAuto reads the parameters from the environment when it provides them, and uses the configuration otherwise.
+	SdkInitAuto SdkInitMode = "auto"
+	// SdkInitConfig uses the Anywhere configuration, or the managed fleet defaults without one.
+	SdkInitConfig SdkInitMode = "config"
+	// SdkInitEnvironment reads the parameters from the GAMELIFT_* environment variables with InitSDKFromEnvironment.
+	SdkInitEnvironment SdkInitMode = "environment"
+)
+
 // AwsConfigProvider represents the type of AWS configuration provider to use.
 // Supports profile-based and SSO-file based authentication.
 type AwsConfigProvider string
 
 const (
-	AwsConfigProviderProfile AwsConfigProvider = "aws-profile"
-	AwsConfigProviderSSOFile AwsConfigProvider = "sso-file"
+	AwsConfigProviderProfile           AwsConfigProvider = "aws-profile"
+	AwsConfigProviderSSOFile           AwsConfigProvider = "sso-file"
+	AwsConfigProviderLiteral           AwsConfigProvider = "literal"
+	AwsConfigProviderDefault           AwsConfigProvider = "default"
+	AwsConfigProviderCredentialProcess AwsConfigProvider = "credential-process"
+	AwsConfigProviderWebIdentity       AwsConfigProvider = "web-identity"
 )
 
 // AwsConfig defines the configuration for AWS service access.
 type AwsConfig struct {
-	Region   string            `mapstructure:"region" yaml:"region,omitempty"`
-	Provider AwsConfigProvider `mapstructure:"provider" yaml:"provider,omitempty"`
-	Literal  AwsConfigLiteral  `mapstructure:"literal" yaml:"literal,omitempty"`
-	Profile  string            `mapstructure:"profile" yaml:"profile,omitempty"`
-	SSOFile  string            `mapstructure:"ssoFile" yaml:"ssoFile,omitempty"`
+	Region            string                  `mapstructure:"region" yaml:"region,omitempty"`
+	Provider          AwsConfigProvider       `mapstructure:"provider" yaml:"provider,omitempty"`
+	Literal           AwsConfigLiteral        `mapstructure:"literal" yaml:"literal,omitempty"`
+	Profile           string                  `mapstructure:"profile" yaml:"profile,omitempty"`
+	SSOFile           string                  `mapstructure:"ssoFile" yaml:"ssoFile,omitempty"`
+	CredentialProcess string                  `mapstructure:"credentialProcess" yaml:"credentialProcess,omitempty"` // Command printing credentials in the credential_process format
+	WebIdentity       AwsConfigWebIdentity    `mapstructure:"webIdentity" yaml:"webIdentity,omitempty"`
+	SSOFileRefresh    AwsConfigSSOFileRefresh `mapstructure:"ssoFileRefresh" yaml:"ssoFileRefresh,omitempty"`
+}
+
+// AwsConfigSSOFileRefresh defines how the credentials of the sso file are watched for expiry and refreshed.
+type AwsConfigSSOFileRefresh struct {
+	Command       string        `mapstructure:"command" yaml:"command,omitempty"`             // Command rewriting the sso file with new credentials
+	RefreshBefore time.Duration `mapstructure:"refreshBefore" yaml:"refreshBefore,omitempty"` // How long before expiry the command is run
+	WarnBefore    time.Duration `mapstructure:"warnBefore" yaml:"warnBefore,omitempty"`       // How long before expiry warnings are logged
+	ProbeInterval time.Duration `mapstructure:"probeInterval" yaml:"probeInterval,omitempty"` // How often STS is probed when the file has no expiration, 0 disables the probe
 }
 
-// AwsConfigLiteral contains direct AWS credentials.
+// AwsConfigLiteral references static AWS credentials, read from a credentials file or from environment
+// variables so they are not written in the configuration.
 type AwsConfigLiteral struct {
-	AccessKeyId     string `mapstructure:"accessKey" yaml:"accessKeyId"`
-	AccessKeySecret string `mapstructure:"secretKey" yaml:"accessKeySecret"`
+	File               string `mapstructure:"file" yaml:"file,omitempty"`                             // Credentials file with a single profile, in the format of the sso file
+	AccessKeyIdEnv     string `mapstructure:"accessKeyIdEnv" yaml:"accessKeyIdEnv,omitempty"`         // Environment variable holding the access key id
+	SecretAccessKeyEnv string `mapstructure:"secretAccessKeyEnv" yaml:"secretAccessKeyEnv,omitempty"` // Environment variable holding the secret access key
+	SessionTokenEnv    string `mapstructure:"sessionTokenEnv" yaml:"sessionTokenEnv,omitempty"`       // Environment variable holding the session token, optional
+}
+
+// AwsConfigWebIdentity contains the role assumed with a web identity token, such as a Kubernetes service account token.
+type AwsConfigWebIdentity struct {
+	RoleArn         string `mapstructure:"roleArn" yaml:"roleArn,omitempty"`
+	TokenFile       string `mapstructure:"tokenFile" yaml:"tokenFile,omitempty"`
+	RoleSessionName string `mapstructure:"roleSessionName" yaml:"roleSessionName,omitempty"`
+}
+
+// TriggerStream is the game output stream a log trigger rule applies to.
+type TriggerStream string
+
+const (
+	TriggerStreamStdout TriggerStream = "stdout"
+	TriggerStreamStderr TriggerStream = "stderr"
+)
+
+// TriggerAction is what the wrapper does when a log trigger rule matches a line of game output.
+type TriggerAction string
+
+const (
+	// TriggerActionMarkReady activates the game session with the hosting provider.
+	TriggerActionMarkReady TriggerAction = "mark-ready"
+	// TriggerActionMarkUnhealthy reports the game as unhealthy in subsequent health checks.
+	TriggerActionMarkUnhealthy TriggerAction = "mark-unhealthy"
+	// TriggerActionAcceptPlayerSession accepts the player session captured as playerSessionId.
+	TriggerActionAcceptPlayerSession TriggerAction = "accept-player-session"
+	// TriggerActionRemovePlayerSession removes the player session captured as playerSessionId.
+	TriggerActionRemovePlayerSession TriggerAction = "remove-player-session"
+	// TriggerActionMetric increments a counter.
+	TriggerActionMetric TriggerAction = "metric"
+	// TriggerActionSpanEvent adds an event with the captures to the game session span.
+	TriggerActionSpanEvent TriggerAction = "span-event"
+	// TriggerActionTerminateSession stops the game process, ending the game session.
+	TriggerActionTerminateSession TriggerAction = "terminate-session"
+)
+
+// TriggerRule maps the lines of game output matching a regular expression to an action.
+type TriggerRule struct {
+	Name       string        `mapstructure:"name" yaml:"name"`
+	Stream     TriggerStream `mapstructure:"stream" yaml:"stream"` // empty matches both streams
+	Pattern    string        `mapstructure:"pattern" yaml:"pattern"`
+	Action     TriggerAction `mapstructure:"action" yaml:"action"`
+	Metric     string        `mapstructure:"metric" yaml:"metric"`         // counter incremented by the metric action
+	Attributes []string      `mapstructure:"attributes" yaml:"attributes"` // captures added as metric attributes
+}
+
+// LogTriggers configures the rules applied to the output of the game process.
+type LogTriggers struct {
+	WaitForReady bool          `mapstructure:"waitForReady" yaml:"waitForReady"` // defer game session activation until a mark-ready rule matches
+	RulesFile    string        `mapstructure:"rulesFile" yaml:"rulesFile"`       // reloaded when it changes
+	Rules        []TriggerRule `mapstructure:"rules" yaml:"rules"`
+}
+
+// TerminationNotice configures how the game process is told about a scheduled termination, and how long it is
+// given to wrap up before it is stopped.
+type TerminationNotice struct {
+	Enabled      bool          `mapstructure:"enabled" yaml:"enabled"`
+	Margin       time.Duration `mapstructure:"margin" yaml:"margin"`             // the game process is stopped this long before the termination time
+	File         string        `mapstructure:"file" yaml:"file"`                 // written with the termination time, its path is given to the game process in env
+	StdinCommand string        `mapstructure:"stdinCommand" yaml:"stdinCommand"` // template of the line written to the game process stdin
+	Signal       string        `mapstructure:"signal" yaml:"signal"`             // sent to the game process, unix only
+}
+
+// Supervisor configures the supervisor, which runs several wrapper instances on one host.
+type Supervisor struct {
+	Instances       int           `mapstructure:"instances" yaml:"instances"`
+	StartInterval   time.Duration `mapstructure:"startInterval" yaml:"startInterval"`     // delay between starting two instances
+	StopTimeout     time.Duration `mapstructure:"stopTimeout" yaml:"stopTimeout"`         // how long instances are given to stop before they are killed
+	RestartDelay    time.Duration `mapstructure:"restartDelay" yaml:"restartDelay"`       // delay before restarting an instance, doubled while it keeps failing
+	MaxRestartDelay time.Duration `mapstructure:"maxRestartDelay" yaml:"maxRestartDelay"` // upper bound of the restart delay
+	StatusInterval  time.Duration `mapstructure:"statusInterval" yaml:"statusInterval"`   // how often the status of the instances is logged
+}
+
+// Drain configures how the wrapper is drained for maintenance: it stops taking new game sessions and players, lets
+// the current game session end and exits. SIGUSR1 always drains the wrapper on unix.
+type Drain struct {
+	File         string        `mapstructure:"file" yaml:"file"`                 // drains the wrapper once it exists, relative to the app directory
+	PollInterval time.Duration `mapstructure:"pollInterval" yaml:"pollInterval"` // how often the file is checked
+	AdminAddress string        `mapstructure:"adminAddress" yaml:"adminAddress"` // loopback address of the admin endpoint, empty disables it
+}
+
+// PortRange is an inclusive range of ports, From equals To for a single port.
+type PortRange struct {
+	From int `mapstructure:"from" yaml:"from"`
+	To   int `mapstructure:"to" yaml:"to"`
+}
+
+// IsZero reports whether the range is unset.
+func (portRange PortRange) IsZero() bool {
+	return portRange.From == 0 && portRange.To == 0
+}
+
+// PortAllocation configures the ports allocated to the game server when the wrapper starts.
+type PortAllocation struct {
+	Game  PortRange            `mapstructure:"game" yaml:"game"`   // unset when the game port is static
+	Named map[string]PortRange `mapstructure:"named" yaml:"named"` // extra ports, such as query, RCON or metrics
 }
 
 // CliArg represents a command-line argument configuration for the game server.
@@ -8,11 +8,13 @@ package constants
 type ContextKey string
 
 const (
-	ContextKeySource         ContextKey = "src"
-	ContextKeyPackageName    ContextKey = "pkg"
-	ContextKeyVersion        ContextKey = "ver"
-	ContextKeyAppDir         ContextKey = "appdir"
-	ContextKeyRunId          ContextKey = "runid"
-	ContextKeyRunLogDir      ContextKey = "runlogdir"
-	ContextKeyWrapperLogPath ContextKey = "wrapperlogpath"
+	ContextKeySource             ContextKey = "src"
+	ContextKeyPackageName        ContextKey = "pkg"
+	ContextKeyVersion            ContextKey = "ver"
+	ContextKeyAppDir             ContextKey = "appdir"
+	ContextKeyRunId              ContextKey = "runid"
+	ContextKeyRunLogDir          ContextKey = "runlogdir"
+	ContextKeyWrapperLogPath     ContextKey = "wrapperlogpath"
+	ContextKeyTerminationTrigger ContextKey = "terminationtrigger"
+	ContextKeyTerminationTime    ContextKey = "terminationtime"
 )
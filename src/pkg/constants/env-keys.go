@@ -9,4 +9,16 @@ const (
 	EnvironmentKeySDKToolName    string = "GAMELIFT_SDK_TOOL_NAME"
 	EnvironmentKeySDKToolVersion string = "GAMELIFT_SDK_TOOL_VERSION"
 	EnvironmentKeyStartDir       string = "START_DIR"
+
+	EnvironmentKeyComputeCertificatePath string = "GAMELIFT_COMPUTE_CERTIFICATE_PATH"
+	EnvironmentKeyComputeHostname        string = "GAMELIFT_COMPUTE_HOSTNAME"
+	EnvironmentKeyTerminationNoticeFile  string = "GAMELIFT_TERMINATION_NOTICE_FILE"
+	EnvironmentKeyPlayerApiUrl           string = "GAMELIFT_PLAYER_API_URL"
+
+	// set by Agones on the game server pod
+	EnvironmentKeyAgonesSdkHttpPort string = "AGONES_SDK_HTTP_PORT"
+
+	// set by the supervisor on the wrapper instances it starts
+	EnvironmentKeyInstanceIndex string = "GAMELIFT_WRAPPER_INSTANCE_INDEX"
+	EnvironmentKeyInstanceCount string = "GAMELIFT_WRAPPER_INSTANCE_COUNT"
 )
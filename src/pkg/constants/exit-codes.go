@@ -0,0 +1,10 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package constants
+
+// ExitCodeDrained is the exit code of a wrapper that exited after being drained, the supervisor does not restart
+// it. It matches EX_TEMPFAIL, so service managers can be told not to restart it either.
+const ExitCodeDrained = 75
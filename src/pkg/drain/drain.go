@@ -0,0 +1,269 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package drain lets an operator drain the wrapper for maintenance. A drained wrapper takes no new game sessions
+// or players, lets the current game session end and exits instead of waiting for another one.
+package drain
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/network"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/process"
+	"github.com/pkg/errors"
+)
+
+const (
+	// Signal drains the wrapper, and the supervisor forwards it to its instances. It is not available on windows.
+	Signal = "SIGUSR1"
+
+	DefaultFileName     = "drain"
+	DefaultPollInterval = 5 * time.Second
+
+	drainPath = "/drain"
+)
+
+// Status describes whether the wrapper is draining.
+type Status struct {
+	Draining bool      `json:"draining"`
+	Reason   string    `json:"reason,omitempty"`
+	Since    time.Time `json:"since,omitempty"`
+}
+
+// Drainer drains the hosting when told to by a signal, a file or the admin endpoint. When no game session is
+// running the wrapper is stopped right away, otherwise it keeps reporting healthy and exits once the game
+// session ends.
+type Drainer struct {
+	cfg    config.Drain
+	target hosting.Drainable
+	logger *slog.Logger
+
+	mutex  sync.Mutex
+	status Status
+	stop   func() // stops the wrapper, set by Start
+
+	signals   chan os.Signal
+	done      chan struct{}
+	closeOnce sync.Once
+	server    *http.Server
+	addr      net.Addr // address of the admin endpoint once it listens
+}
+
+// Start listens for the drain triggers until the drainer is closed.
+//
+// Parameters:
+//   - ctx: Context bounding the triggers
+//   - stop: Stops the wrapper, called when the wrapper is drained while no game session is running
+//
+// Returns:
+//   - error: Any error listening on the admin address
+func (drainer *Drainer) Start(ctx context.Context, stop func()) error {
+	drainer.mutex.Lock()
+	drainer.stop = stop
+	drainer.mutex.Unlock()
+
+	if sig, err := process.ParseSignal(Signal); err != nil {
+		drainer.logger.DebugContext(ctx, "the wrapper cannot be drained by signal", "err", err)
+	} else {
+		signal.Notify(drainer.signals, sig)
+	}
+
+	if len(drainer.cfg.AdminAddress) != 0 {
+		if err := drainer.listen(ctx); err != nil {
+			signal.Stop(drainer.signals)
+			return err
+		}
+	}
+
+	go drainer.watch(ctx)
+
+	drainer.logger.DebugContext(ctx, "drain triggers started", "file", drainer.cfg.File, "admin", drainer.cfg.AdminAddress)
+	return nil
+}
+
+// Drain drains the wrapper. Draining an already draining wrapper has no effect.
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - reason: What drained the wrapper, for the logs
+func (drainer *Drainer) Drain(ctx context.Context, reason string) {
+	drainer.mutex.Lock()
+	if drainer.status.Draining {
+		drainer.mutex.Unlock()
+		return
+	}
+	drainer.status = Status{Draining: true, Reason: reason, Since: time.Now()}
+	stop := drainer.stop
+	drainer.mutex.Unlock()
+
+	drainer.logger.InfoContext(ctx, "draining the wrapper", "reason", reason)
+
+	sessionRunning, err := drainer.target.Drain(ctx)
+	if err != nil {
+		// the game session still ends on its own, the wrapper exits then
+		drainer.logger.ErrorContext(ctx, "failed to deny new player sessions", "err", err)
+	}
+
+	if sessionRunning {
+		drainer.logger.InfoContext(ctx, "waiting for the game session to end before exiting")
+		return
+	}
+
+	drainer.logger.InfoContext(ctx, "no game session running, stopping the wrapper")
+	if stop != nil {
+		stop()
+	}
+}
+
+// Status returns whether the wrapper is draining.
+//
+// Returns:
+//   - Status: The drain status
+func (drainer *Drainer) Status() Status {
+	drainer.mutex.Lock()
+	defer drainer.mutex.Unlock()
+
+	return drainer.status
+}
+
+// Draining reports whether the wrapper was drained.
+//
+// Returns:
+//   - bool: True once the wrapper is draining
+func (drainer *Drainer) Draining() bool {
+	return drainer.Status().Draining
+}
+
+// Close stops listening for the drain triggers.
+//
+// Parameters:
+//   - ctx: Context bounding the shutdown of the admin endpoint
+//
+// Returns:
+//   - error: Any error shutting down the admin endpoint
+func (drainer *Drainer) Close(ctx context.Context) error {
+	var err error
+	drainer.closeOnce.Do(func() {
+		signal.Stop(drainer.signals)
+		close(drainer.done)
+		if drainer.server != nil {
+			err = drainer.server.Shutdown(ctx)
+		}
+	})
+	return err
+}
+
+// watch drains the wrapper on the signal, or once the file exists.
+func (drainer *Drainer) watch(ctx context.Context) {
+	ticker := time.NewTicker(drainer.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-drainer.done:
+			return
+		case sig := <-drainer.signals:
+			drainer.Drain(ctx, "received signal "+sig.String())
+		case <-ticker.C:
+			if drainer.Draining() {
+				continue
+			}
+			if _, err := os.Stat(drainer.cfg.File); err == nil {
+				drainer.Drain(ctx, "found drain file '"+drainer.cfg.File+"'")
+			}
+		}
+	}
+}
+
+// listen serves the admin endpoint on the loopback address.
+//
+//	POST /drain  drains the wrapper
+//	GET  /drain  returns the drain status
+func (drainer *Drainer) listen(ctx context.Context) error {
+	listener, err := net.Listen("tcp", drainer.cfg.AdminAddress)
+	if err != nil {
+		return errors.Wrapf(err, "failed to listen on '%s'", drainer.cfg.AdminAddress)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+drainPath, func(w http.ResponseWriter, r *http.Request) {
+		drainer.Drain(ctx, "requested on the admin endpoint")
+		drainer.writeStatus(w, http.StatusAccepted)
+	})
+	mux.HandleFunc("GET "+drainPath, func(w http.ResponseWriter, r *http.Request) {
+		drainer.writeStatus(w, http.StatusOK)
+	})
+
+	drainer.addr = listener.Addr()
+	drainer.server = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	drainer.logger.InfoContext(ctx, "drain admin endpoint listening", "address", listener.Addr().String())
+	go func() {
+		if err := drainer.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			drainer.logger.ErrorContext(ctx, "drain admin endpoint failed", "err", err)
+		}
+	}()
+
+	return nil
+}
+
+func (drainer *Drainer) writeStatus(w http.ResponseWriter, code int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(drainer.Status())
+}
+
+// New creates a drainer.
+//
+// Parameters:
+//   - cfg: The drain file and admin endpoint
+//   - appDir: The directory a relative drain file is resolved against
+//   - target: The hosting drained
+//   - logger: Logger for the drainer
+//
+// Returns:
+//   - *Drainer: The drainer, its triggers not yet started
+//   - error: An error if the admin address is not a loopback address
+func New(cfg config.Drain, appDir string, target hosting.Drainable, logger *slog.Logger) (*Drainer, error) {
+	if len(cfg.AdminAddress) != 0 {
+		if err := network.ValidateLoopbackAddress(cfg.AdminAddress); err != nil {
+			return nil, errors.Wrapf(err, "invalid drain admin address '%s'", cfg.AdminAddress)
+		}
+	}
+
+	if len(cfg.File) == 0 {
+		cfg.File = DefaultFileName
+	}
+	if !filepath.IsAbs(cfg.File) {
+		cfg.File = filepath.Join(appDir, cfg.File)
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultPollInterval
+	}
+
+	return &Drainer{
+		cfg:     cfg,
+		target:  target,
+		logger:  logger,
+		signals: make(chan os.Signal, 1),
+		done:    make(chan struct{}),
+	}, nil
+}
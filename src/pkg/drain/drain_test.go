@@ -0,0 +1,123 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package drain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/process"
+	"github.com/stretchr/testify/assert"
+)
+
+type targetMock struct {
+	sessionRunning bool
+	drains         atomic.Int32
+}
+
+func (target *targetMock) Drain(ctx context.Context) (bool, error) {
+	target.drains.Add(1)
+	return target.sessionRunning, nil
+}
+
+func createDrainerHelper(t *testing.T, cfg config.Drain, sessionRunning bool) (*Drainer, *targetMock, *atomic.Bool, string) {
+	dir := t.TempDir()
+	target := &targetMock{sessionRunning: sessionRunning}
+	logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+
+	drainer, err := New(cfg, dir, target, logger)
+	assert.NoError(t, err)
+
+	stopped := &atomic.Bool{}
+	assert.NoError(t, drainer.Start(context.Background(), func() { stopped.Store(true) }))
+	t.Cleanup(func() { _ = drainer.Close(context.Background()) })
+
+	return drainer, target, stopped, dir
+}
+
+func TestDrainer_FileStopsIdleWrapper(t *testing.T) {
+	//arrange
+	drainer, target, stopped, dir := createDrainerHelper(t, config.Drain{PollInterval: 10 * time.Millisecond}, false)
+
+	//act
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, DefaultFileName), nil, 0644))
+
+	//assert
+	assert.Eventually(t, stopped.Load, 5*time.Second, 10*time.Millisecond)
+	assert.True(t, drainer.Draining())
+	assert.Contains(t, drainer.Status().Reason, "drain file")
+	assert.Equal(t, int32(1), target.drains.Load())
+}
+
+func TestDrainer_WaitsForRunningGameSession(t *testing.T) {
+	//arrange
+	drainer, target, stopped, _ := createDrainerHelper(t, config.Drain{}, true)
+
+	//act
+	drainer.Drain(context.Background(), "Unit Test")
+	drainer.Drain(context.Background(), "Unit Test")
+
+	//assert
+	assert.True(t, drainer.Draining())
+	assert.False(t, stopped.Load())
+	assert.Equal(t, int32(1), target.drains.Load())
+}
+
+func TestDrainer_Signal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("windows has no SIGUSR1")
+	}
+
+	//arrange
+	drainer, _, stopped, _ := createDrainerHelper(t, config.Drain{}, false)
+
+	sig, err := process.ParseSignal(Signal)
+	assert.NoError(t, err)
+	self, err := os.FindProcess(os.Getpid())
+	assert.NoError(t, err)
+
+	//act
+	assert.NoError(t, self.Signal(sig))
+
+	//assert
+	assert.Eventually(t, stopped.Load, 5*time.Second, 10*time.Millisecond)
+	assert.Contains(t, drainer.Status().Reason, "signal")
+}
+
+func TestDrainer_AdminEndpoint(t *testing.T) {
+	//arrange
+	drainer, _, _, _ := createDrainerHelper(t, config.Drain{AdminAddress: "127.0.0.1:0"}, true)
+
+	//act
+	response, err := http.Post("http://"+drainer.addr.String()+drainPath, "", nil)
+	assert.NoError(t, err)
+	defer response.Body.Close()
+
+	//assert
+	assert.Equal(t, http.StatusAccepted, response.StatusCode)
+	var status Status
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&status))
+	assert.True(t, status.Draining)
+	assert.Equal(t, "requested on the admin endpoint", status.Reason)
+}
+
+func TestNew_RejectsNonLoopbackAdminAddress(t *testing.T) {
+	//act
+	_, err := New(config.Drain{AdminAddress: "0.0.0.0:37091"}, "", &targetMock{}, slog.Default())
+
+	//assert
+	assert.ErrorContains(t, err, "loopback")
+}
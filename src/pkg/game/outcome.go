@@ -0,0 +1,131 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/constants"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/types/events"
+)
+
+// OutcomeClassification describes how a game session ended.
+type OutcomeClassification string
+
+const (
+	// OutcomeCompleted means the game process exited cleanly on its own.
+	OutcomeCompleted OutcomeClassification = "completed"
+	// OutcomeCrashed means the game process exited on its own with a non-zero code or a signal.
+	OutcomeCrashed OutcomeClassification = "crashed"
+	// OutcomeTerminated means the game process was stopped by the wrapper.
+	OutcomeTerminated OutcomeClassification = "terminated"
+	// OutcomeFailedToStart means the game process was never launched.
+	OutcomeFailedToStart OutcomeClassification = "failed-to-start"
+)
+
+// TerminationTrigger describes what caused a game session to end.
+type TerminationTrigger string
+
+const (
+	TerminationTriggerGameExit        TerminationTrigger = "game-exit"
+	TerminationTriggerHostingShutdown TerminationTrigger = "hosting-shutdown"
+	TerminationTriggerTimeout         TerminationTrigger = "timeout"
+	TerminationTriggerHealthFailure   TerminationTrigger = "health-failure"
+	TerminationTriggerWrapperShutdown TerminationTrigger = "wrapper-shutdown"
+	TerminationTriggerLogRule         TerminationTrigger = "log-rule"
+)
+
+// OutcomeFileName is the name of the session summary written to the run log directory.
+const OutcomeFileName = "game-session-outcome.json"
+
+// Outcome is a structured summary of how a game session ended.
+type Outcome struct {
+	GameSessionId  string                `json:"gameSessionId"`
+	Classification OutcomeClassification `json:"classification"`
+	Trigger        TerminationTrigger    `json:"trigger"`
+	ExitCode       int                   `json:"exitCode"`
+	Signal         string                `json:"signal,omitempty"`
+	Error          string                `json:"error,omitempty"`
+	StartedAt      time.Time             `json:"startedAt"`
+	EndedAt        time.Time             `json:"endedAt"`
+	Duration       time.Duration         `json:"-"`
+}
+
+// MarshalJSON writes the duration in seconds alongside the other outcome fields.
+func (outcome Outcome) MarshalJSON() ([]byte, error) {
+	type alias Outcome
+	return json.Marshal(struct {
+		alias
+		DurationSeconds float64 `json:"durationSeconds"`
+	}{
+		alias:           alias(outcome),
+		DurationSeconds: outcome.Duration.Seconds(),
+	})
+}
+
+// Classify determines the outcome classification from what is known about the end of a session.
+//
+// Parameters:
+//   - launched: Whether the game process was started
+//   - trigger: What caused the session to end
+//   - exitCode: The exit code of the game process
+//   - signal: The signal that ended the game process, empty if none
+//
+// Returns:
+//   - OutcomeClassification: The classification of the session outcome
+func Classify(launched bool, trigger TerminationTrigger, exitCode int, signal string) OutcomeClassification {
+	if !launched {
+		return OutcomeFailedToStart
+	}
+
+	if trigger != TerminationTriggerGameExit {
+		return OutcomeTerminated
+	}
+
+	if exitCode == 0 && len(signal) == 0 {
+		return OutcomeCompleted
+	}
+
+	return OutcomeCrashed
+}
+
+// TriggerForTerminateReason maps a hosting termination reason onto a termination trigger.
+func TriggerForTerminateReason(reason events.HostingTerminateReason) TerminationTrigger {
+	switch reason {
+	case events.HostingTerminateReasonContextExpiry:
+		return TerminationTriggerTimeout
+	case events.HostingTerminateReasonHealthCheckFailed:
+		return TerminationTriggerHealthFailure
+	case events.HostingTerminateReasonHostingShutdown:
+		return TerminationTriggerHostingShutdown
+	default:
+		return TerminationTriggerWrapperShutdown
+	}
+}
+
+// WithTerminationTrigger returns a context that tells Server.Stop why the game is being stopped.
+func WithTerminationTrigger(ctx context.Context, trigger TerminationTrigger) context.Context {
+	return context.WithValue(ctx, constants.ContextKeyTerminationTrigger, trigger)
+}
+
+// WithTerminationTime returns a context that tells Server.Stop when the hosting shuts the process down.
+func WithTerminationTime(ctx context.Context, terminationTime time.Time) context.Context {
+	return context.WithValue(ctx, constants.ContextKeyTerminationTime, terminationTime)
+}
+
+// TerminationTimeFromContext returns the termination time stored in the context, if any.
+func TerminationTimeFromContext(ctx context.Context) (time.Time, bool) {
+	terminationTime, ok := ctx.Value(constants.ContextKeyTerminationTime).(time.Time)
+	return terminationTime, ok && !terminationTime.IsZero()
+}
+
+// TerminationTriggerFromContext returns the termination trigger stored in the context, if any.
+func TerminationTriggerFromContext(ctx context.Context) (TerminationTrigger, bool) {
+	trigger, ok := ctx.Value(constants.ContextKeyTerminationTrigger).(TerminationTrigger)
+	return trigger, ok
+}
@@ -0,0 +1,54 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package game
+
+import (
+	"context"
+	"testing"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/types/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name     string
+		launched bool
+		trigger  TerminationTrigger
+		exitCode int
+		signal   string
+		expected OutcomeClassification
+	}{
+		{"not launched", false, TerminationTriggerGameExit, -1, "", OutcomeFailedToStart},
+		{"clean exit", true, TerminationTriggerGameExit, 0, "", OutcomeCompleted},
+		{"non-zero exit", true, TerminationTriggerGameExit, 3, "", OutcomeCrashed},
+		{"signalled", true, TerminationTriggerGameExit, -1, "segmentation fault", OutcomeCrashed},
+		{"hosting shutdown", true, TerminationTriggerHostingShutdown, -1, "killed", OutcomeTerminated},
+		{"timeout", true, TerminationTriggerTimeout, -1, "killed", OutcomeTerminated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Classify(tt.launched, tt.trigger, tt.exitCode, tt.signal))
+		})
+	}
+}
+
+func TestTriggerForTerminateReason(t *testing.T) {
+	assert.Equal(t, TerminationTriggerTimeout, TriggerForTerminateReason(events.HostingTerminateReasonContextExpiry))
+	assert.Equal(t, TerminationTriggerHealthFailure, TriggerForTerminateReason(events.HostingTerminateReasonHealthCheckFailed))
+	assert.Equal(t, TerminationTriggerHostingShutdown, TriggerForTerminateReason(events.HostingTerminateReasonHostingShutdown))
+	assert.Equal(t, TerminationTriggerWrapperShutdown, TriggerForTerminateReason(events.HostingTerminateReasonUnspecified))
+}
+
+func TestTerminationTriggerFromContext(t *testing.T) {
+	_, ok := TerminationTriggerFromContext(context.Background())
+	assert.False(t, ok)
+
+	trigger, ok := TerminationTriggerFromContext(WithTerminationTrigger(context.Background(), TerminationTriggerHealthFailure))
+	assert.True(t, ok)
+	assert.Equal(t, TerminationTriggerHealthFailure, trigger)
+}
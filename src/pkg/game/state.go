@@ -0,0 +1,198 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package game
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/types"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/types/events"
+)
+
+// validTransitions lists the states that can be entered from each game state.
+var validTransitions = map[types.GameState][]types.GameState{
+	types.GameStateInitialising: {
+		types.GameStateStartingGame,
+		types.GameStateShuttingDown,
+		types.GameStateFailed,
+	},
+	types.GameStateStartingGame: {
+		types.GameStateRunning,
+		types.GameStateShuttingDown,
+		types.GameStateFailed,
+	},
+	types.GameStateRunning: {
+		types.GameStateFinishingGame,
+		types.GameStateShuttingDown,
+		types.GameStateFailed,
+	},
+	types.GameStateFinishingGame: {
+		types.GameStateShuttingDown,
+		types.GameStateFailed,
+	},
+	types.GameStateFailed: {
+		types.GameStateShuttingDown,
+	},
+	types.GameStateShuttingDown: {},
+}
+
+// Transition describes a single change of game state.
+type Transition struct {
+	From   types.GameState
+	To     types.GameState
+	Reason string
+	At     time.Time
+	// Elapsed is the time spent in the From state.
+	Elapsed time.Duration
+}
+
+// InvalidTransitionError is returned when a transition is not permitted from the current state.
+type InvalidTransitionError struct {
+	From types.GameState
+	To   types.GameState
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("invalid game state transition from '%s' to '%s'", e.From, e.To)
+}
+
+// StateMachine tracks the lifecycle state of a game server and notifies subscribers of transitions.
+// It is safe for concurrent use.
+type StateMachine struct {
+	mutex       sync.RWMutex
+	notifyMutex sync.Mutex
+	state       types.GameState
+	enteredAt   time.Time
+	history     []Transition
+	subscribers []func(ctx context.Context, t Transition)
+	now         func() time.Time
+}
+
+// Current returns the current state and the time it was entered.
+func (stateMachine *StateMachine) Current() (types.GameState, time.Time) {
+	stateMachine.mutex.RLock()
+	defer stateMachine.mutex.RUnlock()
+	return stateMachine.state, stateMachine.enteredAt
+}
+
+// State returns the current state.
+func (stateMachine *StateMachine) State() types.GameState {
+	state, _ := stateMachine.Current()
+	return state
+}
+
+// History returns a copy of all transitions made so far, oldest first.
+func (stateMachine *StateMachine) History() []Transition {
+	stateMachine.mutex.RLock()
+	defer stateMachine.mutex.RUnlock()
+	history := make([]Transition, len(stateMachine.history))
+	copy(history, stateMachine.history)
+	return history
+}
+
+// Subscribe registers a function that is called after every successful transition.
+// Subscribers are called in registration order and must not trigger transitions themselves.
+func (stateMachine *StateMachine) Subscribe(f func(ctx context.Context, t Transition)) {
+	stateMachine.mutex.Lock()
+	defer stateMachine.mutex.Unlock()
+	stateMachine.subscribers = append(stateMachine.subscribers, f)
+}
+
+// CanTransition reports whether the state machine may move from its current state to the target state.
+func (stateMachine *StateMachine) CanTransition(to types.GameState) bool {
+	return isValidTransition(stateMachine.State(), to)
+}
+
+// Transition moves the state machine to the target state and notifies subscribers.
+//
+// Parameters:
+//   - ctx: Context passed to the subscribers
+//   - to: The state to enter
+//   - reason: A short description of why the transition happened
+//
+// Returns:
+//   - error: An *InvalidTransitionError if the transition is not permitted from the current state
+func (stateMachine *StateMachine) Transition(ctx context.Context, to types.GameState, reason string) error {
+	stateMachine.notifyMutex.Lock()
+	defer stateMachine.notifyMutex.Unlock()
+
+	stateMachine.mutex.Lock()
+	from := stateMachine.state
+	if !isValidTransition(from, to) {
+		stateMachine.mutex.Unlock()
+		return &InvalidTransitionError{From: from, To: to}
+	}
+
+	at := stateMachine.now()
+	transition := Transition{
+		From:    from,
+		To:      to,
+		Reason:  reason,
+		At:      at,
+		Elapsed: at.Sub(stateMachine.enteredAt),
+	}
+	stateMachine.state = to
+	stateMachine.enteredAt = at
+	stateMachine.history = append(stateMachine.history, transition)
+	subscribers := make([]func(ctx context.Context, t Transition), len(stateMachine.subscribers))
+	copy(subscribers, stateMachine.subscribers)
+	stateMachine.mutex.Unlock()
+
+	for _, subscriber := range subscribers {
+		subscriber(ctx, transition)
+	}
+
+	return nil
+}
+
+// GameStatus maps the current state onto the status reported to the hosting provider.
+func (stateMachine *StateMachine) GameStatus() events.GameStatus {
+	return StatusForState(stateMachine.State())
+}
+
+// StatusForState maps a game state onto the status reported to the hosting provider.
+func StatusForState(state types.GameState) events.GameStatus {
+	switch state {
+	case types.GameStateInitialising:
+		return events.GameStatusWaiting
+	case types.GameStateStartingGame, types.GameStateRunning:
+		return events.GameStatusRunning
+	case types.GameStateFinishingGame:
+		return events.GameStatusFinished
+	case types.GameStateShuttingDown:
+		return events.GameStatusTerminated
+	case types.GameStateFailed:
+		return events.GameStatusErrored
+	default:
+		return events.GameStatusErrored
+	}
+}
+
+func isValidTransition(from, to types.GameState) bool {
+	for _, s := range validTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// NewStateMachine creates a state machine in the initialising state.
+//
+// Returns:
+//   - *StateMachine: A new state machine
+func NewStateMachine() *StateMachine {
+	stateMachine := &StateMachine{
+		state: types.GameStateInitialising,
+		now:   time.Now,
+	}
+	stateMachine.enteredAt = stateMachine.now()
+
+	return stateMachine
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package game
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/types"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/types/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateMachine_Transition_HappyPath(t *testing.T) {
+	//arrange
+	stateMachine := NewStateMachine()
+	now := time.Now()
+	stateMachine.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+	observed := make([]Transition, 0)
+	stateMachine.Subscribe(func(ctx context.Context, t Transition) {
+		observed = append(observed, t)
+	})
+
+	//act
+	errs := []error{
+		stateMachine.Transition(context.Background(), types.GameStateStartingGame, "start"),
+		stateMachine.Transition(context.Background(), types.GameStateRunning, "run"),
+		stateMachine.Transition(context.Background(), types.GameStateFinishingGame, "exit"),
+		stateMachine.Transition(context.Background(), types.GameStateShuttingDown, "stop"),
+	}
+
+	//assert
+	for _, err := range errs {
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, types.GameStateShuttingDown, stateMachine.State())
+	assert.Equal(t, events.GameStatusTerminated, stateMachine.GameStatus())
+	assert.Len(t, observed, 4)
+	assert.Equal(t, observed, stateMachine.History())
+	assert.Equal(t, types.GameStateRunning, observed[2].From)
+	assert.Equal(t, "exit", observed[2].Reason)
+	assert.Equal(t, time.Second, observed[2].Elapsed)
+}
+
+func TestStateMachine_Transition_Invalid(t *testing.T) {
+	//arrange
+	stateMachine := NewStateMachine()
+	called := false
+	stateMachine.Subscribe(func(ctx context.Context, t Transition) {
+		called = true
+	})
+
+	//act
+	err := stateMachine.Transition(context.Background(), types.GameStateRunning, "skip starting")
+
+	//assert
+	var invalid *InvalidTransitionError
+	assert.True(t, errors.As(err, &invalid))
+	assert.Equal(t, types.GameStateInitialising, invalid.From)
+	assert.Equal(t, types.GameStateRunning, invalid.To)
+	assert.Equal(t, types.GameStateInitialising, stateMachine.State())
+	assert.False(t, called)
+	assert.Empty(t, stateMachine.History())
+}
+
+func TestStateMachine_Transition_Concurrent(t *testing.T) {
+	//arrange
+	stateMachine := NewStateMachine()
+	assert.Nil(t, stateMachine.Transition(context.Background(), types.GameStateStartingGame, "start"))
+	assert.Nil(t, stateMachine.Transition(context.Background(), types.GameStateRunning, "run"))
+
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	succeeded := 0
+
+	//act
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := stateMachine.Transition(context.Background(), types.GameStateShuttingDown, "stop"); err == nil {
+				mutex.Lock()
+				succeeded++
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	//assert
+	assert.Equal(t, 1, succeeded)
+	assert.Equal(t, types.GameStateShuttingDown, stateMachine.State())
+}
+
+func TestStatusForState(t *testing.T) {
+	tests := []struct {
+		state    types.GameState
+		expected events.GameStatus
+	}{
+		{types.GameStateInitialising, events.GameStatusWaiting},
+		{types.GameStateStartingGame, events.GameStatusRunning},
+		{types.GameStateRunning, events.GameStatusRunning},
+		{types.GameStateFinishingGame, events.GameStatusFinished},
+		{types.GameStateShuttingDown, events.GameStatusTerminated},
+		{types.GameStateFailed, events.GameStatusErrored},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.state), func(t *testing.T) {
+			assert.Equal(t, tt.expected, StatusForState(tt.state))
+		})
+	}
+}
@@ -0,0 +1,364 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package agones is a hosting provider for game servers run on Kubernetes with Agones. It talks to the REST API
+// of the Agones SDK sidecar: the game server is marked ready, kept healthy, and started when it is allocated.
+package agones
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/constants"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/observability"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/types/events"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultHealthCheckInterval is well within the default Agones health check period of 5 seconds.
+	DefaultHealthCheckInterval = 2 * time.Second
+	// DefaultSdkHttpPort is the port of the sidecar REST API when AGONES_SDK_HTTP_PORT is not set.
+	DefaultSdkHttpPort = "9358"
+	// DefaultMetadataPrefix is the prefix of the labels and annotations passed to the game server.
+	DefaultMetadataPrefix = "gamelift-wrapper/"
+
+	watchRetryDelay = time.Second
+)
+
+// Config contains the settings of the Agones hosting provider.
+type Config struct {
+	GamePort            int           // port the game server listens on in its container
+	SdkAddress          string        // address of the sidecar REST API, defaults to localhost and AGONES_SDK_HTTP_PORT
+	HealthCheckInterval time.Duration // interval between health pings, defaults to DefaultHealthCheckInterval
+	PortName            string        // GameServer port given to the game server, the first port when empty
+	MetadataPrefix      string        // prefix of the labels and annotations passed on, defaults to DefaultMetadataPrefix
+}
+
+type agones struct {
+	logger  *slog.Logger
+	spanner observability.Spanner
+	ctx     context.Context
+	cfg     *Config
+	client  *client
+	ec      chan error
+	logDir  string
+
+	mutex          sync.Mutex
+	sessionStarted bool
+	terminated     bool
+
+	onHealthCheck      func(ctx context.Context) events.GameStatus
+	onHostingStart     func(ctx context.Context, h *events.HostingStart, end <-chan error) error
+	onHostingTerminate func(ctx context.Context, h *events.HostingTerminate) error
+}
+
+// Init checks that the Agones SDK sidecar can be reached.
+//
+// Parameters:
+//   - ctx: Context for the initialization process
+//   - args: Initialization arguments
+//
+// Returns:
+//   - *hosting.InitMeta: Metadata about the initialized hosting option
+//   - error: Any error that occurred during initialization
+func (agones *agones) Init(ctx context.Context, args *hosting.InitArgs) (*hosting.InitMeta, error) {
+	agones.ctx = ctx
+
+	ctx, span, _ := agones.spanner.NewSpan(ctx, "Agones init", nil)
+	defer span.End()
+
+	if logDir, ok := ctx.Value(constants.ContextKeyRunLogDir).(string); ok {
+		agones.logDir = logDir
+	}
+
+	gameServer, err := agones.client.gameServer(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to reach the Agones SDK sidecar at '%s'", agones.client.baseUrl)
+	}
+
+	workingDirectory, err := os.Getwd()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get working directory")
+	}
+
+	agones.logger.InfoContext(ctx, "Agones hosting initialized",
+		"gameServer", gameServer.ObjectMeta.Name,
+		"namespace", gameServer.ObjectMeta.Namespace,
+		"state", gameServer.Status.State)
+
+	return &hosting.InitMeta{
+		InstanceWorkingDirectory: workingDirectory,
+	}, nil
+}
+
+// Run marks the game server as ready, sends health pings and watches the game server until the context is done
+// or an error occurs. The game session is started when the game server is allocated.
+//
+// Parameters:
+//   - ctx: Context for managing the hosting lifecycle
+//
+// Returns:
+//   - error: Any error that occurred while running
+func (agones *agones) Run(ctx context.Context) error {
+	agones.ctx = ctx
+
+	ctx, span, _ := agones.spanner.NewSpan(ctx, "Agones run", nil)
+	defer span.End()
+
+	go agones.watchLoop(ctx)
+
+	if err := agones.client.ready(ctx); err != nil {
+		return errors.Wrap(err, "failed to mark the game server as ready")
+	}
+	agones.logger.InfoContext(ctx, "game server marked as ready")
+
+	go agones.healthCheckLoop(ctx)
+
+	select {
+	case <-ctx.Done():
+		return nil
+	case err := <-agones.ec:
+		agones.logger.WarnContext(ctx, "error returned from Agones hosting", "err", err)
+		return err
+	}
+}
+
+// SetOnHostingStart registers a callback function that will be invoked when a hosting
+// start event occurs. The callback receives the hosting start event and an error channel.
+func (agones *agones) SetOnHostingStart(f func(ctx context.Context, h *events.HostingStart, end <-chan error) error) {
+	agones.onHostingStart = f
+}
+
+// SetOnHostingTerminate registers a callback function that will be invoked when a hosting
+// termination event occurs.
+func (agones *agones) SetOnHostingTerminate(f func(ctx context.Context, h *events.HostingTerminate) error) {
+	agones.onHostingTerminate = f
+}
+
+// SetOnHealthCheck registers a callback function that will be invoked to check the health
+// status of the game server. The callback should return the current game status.
+func (agones *agones) SetOnHealthCheck(f func(ctx context.Context) events.GameStatus) {
+	agones.onHealthCheck = f
+}
+
+// Close marks the game server for shut down, Agones then deletes its pod.
+func (agones *agones) Close(ctx context.Context) error {
+	agones.logger.InfoContext(ctx, "cleaning up Agones resources")
+
+	if err := agones.client.shutdown(ctx); err != nil {
+		agones.logger.ErrorContext(ctx, "failed to shut down the game server", "err", err)
+		return err
+	}
+	return nil
+}
+
+// watchLoop watches the game server, and reconnects when the watch stream ends before ctx is done.
+func (agones *agones) watchLoop(ctx context.Context) {
+	for {
+		err := agones.client.watch(ctx, agones.onGameServer)
+		if ctx.Err() != nil {
+			return
+		}
+		agones.logger.WarnContext(ctx, "game server watch ended, reconnecting", "err", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(watchRetryDelay):
+		}
+	}
+}
+
+func (agones *agones) onGameServer(gameServer *GameServer) {
+	agones.logger.DebugContext(agones.ctx, "game server changed", "name", gameServer.ObjectMeta.Name, "state", gameServer.Status.State)
+
+	switch gameServer.Status.State {
+	case StateAllocated:
+		agones.startGameSession(gameServer)
+	case StateShutdown:
+		agones.terminate()
+	}
+}
+
+func (agones *agones) healthCheckLoop(ctx context.Context) {
+	ticker := time.NewTicker(agones.cfg.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			agones.healthCheck(ctx)
+		}
+	}
+}
+
+// healthCheck sends a health ping while the game server is healthy. Agones marks the game server unhealthy
+// once the pings stop.
+func (agones *agones) healthCheck(ctx context.Context) {
+	if agones.onHealthCheck == nil {
+		return
+	}
+
+	status := agones.onHealthCheck(ctx)
+	if status != events.GameStatusWaiting && status != events.GameStatusRunning {
+		agones.logger.WarnContext(ctx, "game server is not healthy, skipping health ping", "status", status)
+		return
+	}
+
+	if err := agones.client.health(ctx); err != nil && ctx.Err() == nil {
+		agones.logger.WarnContext(ctx, "failed to send health ping", "err", err)
+	}
+}
+
+// startGameSession sends a hosting start event for the allocated game server. A game server is allocated once.
+func (agones *agones) startGameSession(gameServer *GameServer) {
+	agones.mutex.Lock()
+	if agones.sessionStarted {
+		agones.mutex.Unlock()
+		return
+	}
+	agones.sessionStarted = true
+	agones.mutex.Unlock()
+
+	hse, err := agones.newHostingStart(gameServer)
+	if err != nil {
+		agones.fail(err)
+		return
+	}
+
+	go func() {
+		agones.logger.DebugContext(agones.ctx, "calling onHostingStart", "event", hse)
+		if err := agones.onHostingStart(agones.ctx, hse, nil); err != nil {
+			agones.fail(err)
+		}
+	}()
+}
+
+// terminate sends a hosting terminate event when the game server is shut down outside of the wrapper.
+func (agones *agones) terminate() {
+	agones.mutex.Lock()
+	if agones.terminated {
+		agones.mutex.Unlock()
+		return
+	}
+	agones.terminated = true
+	agones.mutex.Unlock()
+
+	go func() {
+		err := agones.onHostingTerminate(agones.ctx, &events.HostingTerminate{
+			Reason: events.HostingTerminateReasonHostingShutdown,
+		})
+		if err != nil {
+			agones.fail(err)
+		}
+	}()
+}
+
+// newHostingStart maps the allocated game server to a hosting start event. The labels and annotations under the
+// metadata prefix are passed as game properties, except for game-session-data, matchmaker-data and
+// game-session-name which have their own fields.
+func (agones *agones) newHostingStart(gameServer *GameServer) (*events.HostingStart, error) {
+	gamePort, err := gameServer.port(agones.cfg.PortName)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := gameServer.metadata(agones.cfg.MetadataPrefix)
+	properties, err := gameProperties(metadata)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode game properties")
+	}
+
+	gameSessionName := metadata[metadataGameSessionName]
+	if len(gameSessionName) == 0 {
+		gameSessionName = gameServer.ObjectMeta.Name
+	}
+
+	hse := &events.HostingStart{
+		CliArgs:         make([]config.CliArg, 0),
+		ContainerPort:   agones.cfg.GamePort,
+		FleetId:         gameServer.ObjectMeta.Labels[LabelFleet],
+		GamePort:        gamePort,
+		GameProperties:  properties,
+		GameSessionData: metadata[metadataGameSessionData],
+		GameSessionId:   gameServer.ObjectMeta.Name,
+		GameSessionName: gameSessionName,
+		IpAddress:       gameServer.Status.Address,
+		LogDirectory:    hosting.SessionLogDirectory(agones.logDir, gameServer.ObjectMeta.Name),
+		MatchmakerData:  metadata[metadataMatchmakerData],
+		Provider:        config.ProviderAgones,
+	}
+	if gameServer.Status.Players != nil {
+		hse.MaximumPlayerSessionCount = int(gameServer.Status.Players.Capacity)
+	}
+
+	return hse, nil
+}
+
+// fail reports an error to Run without blocking once Run has returned.
+func (agones *agones) fail(err error) {
+	select {
+	case agones.ec <- err:
+	default:
+		agones.logger.ErrorContext(agones.ctx, "Agones hosting error not delivered", "err", err)
+	}
+}
+
+// New creates the Agones hosting provider.
+//
+// Parameters:
+//   - ctx: Context for creating the provider
+//   - cfg: Agones hosting configuration
+//   - logger: Logger for hosting operations
+//   - spanner: Tracing provider
+//
+// Returns:
+//   - *agones: The Agones hosting provider
+//   - error: Any error validating the configuration
+func New(ctx context.Context, cfg *Config, logger *slog.Logger, spanner observability.Spanner) (*agones, error) {
+	if cfg.GamePort <= 0 || cfg.GamePort >= 65535 {
+		return nil, errors.Errorf("game port needs to be a valid port: '%d'", cfg.GamePort)
+	}
+
+	if len(cfg.SdkAddress) == 0 {
+		port, ok := os.LookupEnv(constants.EnvironmentKeyAgonesSdkHttpPort)
+		if !ok || len(port) == 0 {
+			port = DefaultSdkHttpPort
+		}
+		cfg.SdkAddress = net.JoinHostPort("localhost", port)
+	}
+	if _, _, err := net.SplitHostPort(cfg.SdkAddress); err != nil {
+		return nil, errors.Wrapf(err, "invalid Agones SDK address '%s'", cfg.SdkAddress)
+	}
+
+	if cfg.HealthCheckInterval <= 0 {
+		cfg.HealthCheckInterval = DefaultHealthCheckInterval
+	}
+	if len(cfg.MetadataPrefix) == 0 {
+		cfg.MetadataPrefix = DefaultMetadataPrefix
+	}
+
+	return &agones{
+		cfg:     cfg,
+		ctx:     ctx,
+		ec:      make(chan error, 1),
+		logger:  logger,
+		spanner: spanner,
+		client: &client{
+			baseUrl:    "http://" + cfg.SdkAddress,
+			httpClient: &http.Client{},
+		},
+	}, nil
+}
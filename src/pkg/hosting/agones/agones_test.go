@@ -0,0 +1,209 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package agones
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/internal/mocks"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/internal/testing/agonesserver"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/constants"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/types/events"
+	"github.com/stretchr/testify/assert"
+)
+
+type AgonesMockHelper struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	agones *agones
+	server *agonesserver.Server
+	done   chan error
+
+	starts     chan *events.HostingStart
+	terminates chan *events.HostingTerminate
+}
+
+// createAgonesMockHelper runs the provider against a stand-in sidecar serving a ready-to-start game server.
+func createAgonesMockHelper(t *testing.T, status events.GameStatus) *AgonesMockHelper {
+	server := agonesserver.New(agonesserver.GameServer{
+		ObjectMeta: agonesserver.ObjectMeta{
+			Name:      "game-server-abc12",
+			Namespace: "default",
+			Labels:    map[string]string{LabelFleet: "my-fleet"},
+		},
+		Status: agonesserver.Status{
+			State:   "Scheduled",
+			Address: "10.0.0.12",
+			Ports: []agonesserver.Port{
+				{Name: "query", Port: 7001},
+				{Name: "default", Port: 7654},
+			},
+			Players: &agonesserver.Players{Capacity: 8},
+		},
+	})
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	ctx = context.WithValue(ctx, constants.ContextKeyRunLogDir, t.TempDir())
+	logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+
+	a, err := New(ctx, &Config{
+		GamePort:            7777,
+		SdkAddress:          server.Address(),
+		HealthCheckInterval: 10 * time.Millisecond,
+		PortName:            "default",
+	}, logger, &mocks.SpannerMock{})
+	assert.NoError(t, err)
+
+	helper := &AgonesMockHelper{
+		ctx:        ctx,
+		cancel:     cancel,
+		agones:     a,
+		server:     server,
+		done:       make(chan error, 1),
+		starts:     make(chan *events.HostingStart, 1),
+		terminates: make(chan *events.HostingTerminate, 1),
+	}
+
+	a.SetOnHealthCheck(func(ctx context.Context) events.GameStatus {
+		return status
+	})
+	a.SetOnHostingStart(func(ctx context.Context, h *events.HostingStart, end <-chan error) error {
+		helper.starts <- h
+		return nil
+	})
+	a.SetOnHostingTerminate(func(ctx context.Context, h *events.HostingTerminate) error {
+		helper.terminates <- h
+		return nil
+	})
+
+	_, err = a.Init(ctx, &hosting.InitArgs{})
+	assert.NoError(t, err)
+
+	go func() {
+		helper.done <- a.Run(ctx)
+	}()
+	assert.Eventually(t, func() bool {
+		return server.GameServer().Status.State == StateReady
+	}, 5*time.Second, 10*time.Millisecond)
+
+	return helper
+}
+
+func TestAgones_StartsGameSessionWhenAllocated(t *testing.T) {
+	//arrange
+	helper := createAgonesMockHelper(t, events.GameStatusWaiting)
+
+	//act
+	helper.server.Allocate(
+		map[string]string{"gamelift-wrapper/map": "harbor"},
+		map[string]string{
+			"gamelift-wrapper/mode":              "ranked",
+			"gamelift-wrapper/map":               "docks",
+			"gamelift-wrapper/game-session-data": "session-data",
+			"gamelift-wrapper/matchmaker-data":   "matchmaker-data",
+			"unrelated":                          "ignored",
+		})
+
+	//assert
+	var hse *events.HostingStart
+	select {
+	case hse = <-helper.starts:
+	case <-time.After(5 * time.Second):
+		t.Fatal("game session was not started")
+	}
+
+	assert.Equal(t, "game-server-abc12", hse.GameSessionId)
+	assert.Equal(t, "game-server-abc12", hse.GameSessionName)
+	assert.Equal(t, "my-fleet", hse.FleetId)
+	assert.Equal(t, "10.0.0.12", hse.IpAddress)
+	assert.Equal(t, 7654, hse.GamePort)
+	assert.Equal(t, 7777, hse.ContainerPort)
+	assert.Equal(t, 8, hse.MaximumPlayerSessionCount)
+	assert.Equal(t, "session-data", hse.GameSessionData)
+	assert.Equal(t, "matchmaker-data", hse.MatchmakerData)
+	assert.Equal(t, config.ProviderAgones, hse.Provider)
+
+	var properties map[string]string
+	assert.NoError(t, json.Unmarshal([]byte(hse.GameProperties), &properties))
+	assert.Equal(t, map[string]string{"map": "docks", "mode": "ranked"}, properties)
+}
+
+func TestAgones_HealthPingsOnlyWhileHealthy(t *testing.T) {
+	//arrange
+	healthy := createAgonesMockHelper(t, events.GameStatusRunning)
+	unhealthy := createAgonesMockHelper(t, events.GameStatusErrored)
+
+	//act
+	time.Sleep(100 * time.Millisecond)
+
+	//assert
+	assert.NotEmpty(t, healthy.server.Calls("health"))
+	assert.Empty(t, unhealthy.server.Calls("health"))
+}
+
+func TestAgones_TerminatesOnShutdown(t *testing.T) {
+	//arrange
+	helper := createAgonesMockHelper(t, events.GameStatusRunning)
+
+	//act
+	helper.server.Shutdown()
+
+	//assert
+	select {
+	case h := <-helper.terminates:
+		assert.Equal(t, events.HostingTerminateReasonHostingShutdown, h.Reason)
+	case <-time.After(5 * time.Second):
+		t.Fatal("hosting was not terminated")
+	}
+}
+
+func TestAgones_CloseShutsDownGameServer(t *testing.T) {
+	//arrange
+	helper := createAgonesMockHelper(t, events.GameStatusRunning)
+	helper.cancel()
+	assert.NoError(t, <-helper.done)
+
+	//act
+	err := helper.agones.Close(context.Background())
+
+	//assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"shutdown"}, helper.server.Calls("shutdown"))
+	assert.Equal(t, StateShutdown, helper.server.GameServer().Status.State)
+}
+
+func TestAgones_UnknownPortName(t *testing.T) {
+	//arrange
+	gameServer := &GameServer{Status: GameServerStatus{Ports: []Port{{Name: "default", Port: 7654}}}}
+
+	//act
+	_, err := gameServer.port("query")
+
+	//assert
+	assert.ErrorContains(t, err, "no port named 'query'")
+}
+
+func TestNew_DefaultSdkAddressFromEnvironment(t *testing.T) {
+	//arrange
+	t.Setenv(constants.EnvironmentKeyAgonesSdkHttpPort, "9400")
+
+	//act
+	a, err := New(context.Background(), &Config{GamePort: 7777}, slog.Default(), &mocks.SpannerMock{})
+
+	//assert
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost:9400", a.client.baseUrl)
+	assert.Equal(t, DefaultMetadataPrefix, a.cfg.MetadataPrefix)
+}
@@ -0,0 +1,144 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package agones
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// requestTimeout bounds the calls to the sidecar, except for the watch stream.
+const requestTimeout = 10 * time.Second
+
+// client calls the REST API of the Agones SDK sidecar.
+type client struct {
+	baseUrl    string
+	httpClient *http.Client
+}
+
+// ready marks the game server as ready to be allocated.
+func (client *client) ready(ctx context.Context) error {
+	return client.post(ctx, "/ready")
+}
+
+// health sends a health ping.
+func (client *client) health(ctx context.Context) error {
+	return client.post(ctx, "/health")
+}
+
+// shutdown marks the game server for shut down.
+func (client *client) shutdown(ctx context.Context) error {
+	return client.post(ctx, "/shutdown")
+}
+
+// gameServer returns the GameServer of the pod.
+func (client *client) gameServer(ctx context.Context) (*GameServer, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, client.baseUrl+"/gameserver", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create game server request")
+	}
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get game server")
+	}
+	defer resp.Body.Close()
+
+	if err := checkStatus(resp); err != nil {
+		return nil, errors.Wrap(err, "failed to get game server")
+	}
+
+	gameServer := &GameServer{}
+	if err := json.NewDecoder(resp.Body).Decode(gameServer); err != nil {
+		return nil, errors.Wrap(err, "failed to decode game server")
+	}
+	return gameServer, nil
+}
+
+// watch calls f with the GameServer every time it changes, until ctx is done or the sidecar ends the stream.
+func (client *client) watch(ctx context.Context, f func(gameServer *GameServer)) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, client.baseUrl+"/watch/gameserver", nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create watch request")
+	}
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to watch game server")
+	}
+	defer resp.Body.Close()
+
+	if err := checkStatus(resp); err != nil {
+		return errors.Wrap(err, "failed to watch game server")
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var result watchResult
+		if err := json.Unmarshal(line, &result); err != nil {
+			return errors.Wrap(err, "failed to decode watched game server")
+		}
+		if result.Error != nil {
+			return errors.Errorf("watch failed: %s", result.Error.Message)
+		}
+		if result.Result != nil {
+			f(result.Result)
+		}
+	}
+
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return errors.Wrap(err, "failed to read watch stream")
+	}
+	return nil
+}
+
+func (client *client) post(ctx context.Context, path string) error {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, client.baseUrl+path, bytes.NewBufferString("{}"))
+	if err != nil {
+		return errors.Wrapf(err, "failed to create '%s' request", path)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to call '%s'", path)
+	}
+	defer resp.Body.Close()
+
+	if err := checkStatus(resp); err != nil {
+		return errors.Wrapf(err, "failed to call '%s'", path)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+func checkStatus(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return errors.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+}
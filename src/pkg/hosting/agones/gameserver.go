@@ -0,0 +1,121 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package agones
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// GameServer states reported by the Agones SDK sidecar.
+const (
+	StateReady     = "Ready"
+	StateAllocated = "Allocated"
+	StateShutdown  = "Shutdown"
+)
+
+const (
+	// LabelFleet is set by Agones on the game servers of a fleet.
+	LabelFleet = "agones.dev/fleet"
+
+	// keys under the metadata prefix that are mapped to their own hosting start fields
+	metadataGameSessionData = "game-session-data"
+	metadataMatchmakerData  = "matchmaker-data"
+	metadataGameSessionName = "game-session-name"
+)
+
+// GameServer is the GameServer resource as returned by the REST API of the Agones SDK sidecar.
+type GameServer struct {
+	ObjectMeta ObjectMeta       `json:"object_meta"`
+	Status     GameServerStatus `json:"status"`
+}
+
+// ObjectMeta is the Kubernetes metadata of the GameServer.
+type ObjectMeta struct {
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace"`
+	Uid         string            `json:"uid"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// GameServerStatus is the state, address and ports of the GameServer.
+type GameServerStatus struct {
+	State   string         `json:"state"`
+	Address string         `json:"address"`
+	Ports   []Port         `json:"ports,omitempty"`
+	Players *PlayersStatus `json:"players,omitempty"`
+}
+
+// Port is a port of the GameServer, as seen from outside the cluster.
+type Port struct {
+	Name string `json:"name"`
+	Port int    `json:"port"`
+}
+
+// PlayersStatus is the player tracking status of the GameServer, when the feature is enabled.
+type PlayersStatus struct {
+	Count    int64    `json:"count"`
+	Capacity int64    `json:"capacity"`
+	Ids      []string `json:"ids,omitempty"`
+}
+
+// watchResult is a single message of the watch stream.
+type watchResult struct {
+	Result *GameServer `json:"result"`
+	Error  *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// port returns the port with the given name, or the first port when name is empty.
+func (gameServer *GameServer) port(name string) (int, error) {
+	if len(gameServer.Status.Ports) == 0 {
+		return 0, fmt.Errorf("game server '%s' has no ports", gameServer.ObjectMeta.Name)
+	}
+	if len(name) == 0 {
+		return gameServer.Status.Ports[0].Port, nil
+	}
+	for _, p := range gameServer.Status.Ports {
+		if p.Name == name {
+			return p.Port, nil
+		}
+	}
+	return 0, fmt.Errorf("game server '%s' has no port named '%s'", gameServer.ObjectMeta.Name, name)
+}
+
+// metadata returns the labels and annotations starting with the prefix, keyed without it. Annotations take
+// precedence over labels with the same key.
+func (gameServer *GameServer) metadata(prefix string) map[string]string {
+	metadata := make(map[string]string)
+	for _, values := range []map[string]string{gameServer.ObjectMeta.Labels, gameServer.ObjectMeta.Annotations} {
+		for key, value := range values {
+			if name, ok := strings.CutPrefix(key, prefix); ok && len(name) != 0 {
+				metadata[name] = value
+			}
+		}
+	}
+	return metadata
+}
+
+// gameProperties encodes the metadata that has no hosting start field of its own as a JSON object.
+func gameProperties(metadata map[string]string) (string, error) {
+	properties := make(map[string]string, len(metadata))
+	for key, value := range metadata {
+		switch key {
+		case metadataGameSessionData, metadataMatchmakerData, metadataGameSessionName:
+		default:
+			properties[key] = value
+		}
+	}
+
+	b, err := json.Marshal(properties)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
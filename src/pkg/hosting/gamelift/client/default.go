@@ -0,0 +1,30 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package client
+
+import (
+	"context"
+
+	awscfg "github.com/aws/aws-sdk-go-v2/config"
+)
+
+var _ ConfigProvider = (*DefaultProvider)(nil)
+
+// DefaultProvider leaves the credentials to the default chain of the AWS SDK: the AWS_* environment variables,
+// the shared config and credentials files, web identity, and the container or instance metadata endpoints.
+type DefaultProvider struct{}
+
+func (defaultProvider *DefaultProvider) Init(ctx context.Context) error {
+	return nil
+}
+
+func (defaultProvider *DefaultProvider) GetOpts(ctx context.Context) ([]func(options *awscfg.LoadOptions) error, error) {
+	return []func(options *awscfg.LoadOptions) error{}, nil
+}
+
+func NewDefaultProvider() *DefaultProvider {
+	return &DefaultProvider{}
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package client
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	pkgConfig "github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/constants"
+	awscfg "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/pkg/errors"
+)
+
+var _ ConfigProvider = (*LiteralProvider)(nil)
+
+// LiteralProvider uses static credentials read once from a credentials file or from environment variables.
+type LiteralProvider struct {
+	cfg    pkgConfig.AwsConfigLiteral
+	opts   []func(options *awscfg.LoadOptions) error
+	logger *slog.Logger
+}
+
+func (literalProvider *LiteralProvider) Init(ctx context.Context) error {
+	var creds SSOFileConfig
+	var err error
+	if len(literalProvider.cfg.File) != 0 {
+		creds, err = literalProvider.readFile(ctx)
+	} else {
+		creds, err = literalProvider.readEnv()
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(creds.AccessKey) == 0 || len(creds.SecretKey) == 0 {
+		return errors.New("literal credentials need an access key id and a secret access key")
+	}
+
+	literalProvider.logger.DebugContext(ctx, "loaded literal aws credentials",
+		"file", literalProvider.cfg.File,
+		"accessKeyId", redact(creds.AccessKey),
+		"secretAccessKey", redact(creds.SecretKey),
+		"sessionToken", redact(creds.SessionToken))
+
+	literalProvider.opts = []func(options *awscfg.LoadOptions) error{
+		awscfg.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(creds.AccessKey, creds.SecretKey, creds.SessionToken)),
+	}
+
+	return nil
+}
+
+func (literalProvider *LiteralProvider) GetOpts(ctx context.Context) ([]func(options *awscfg.LoadOptions) error, error) {
+	if literalProvider.opts == nil {
+		return nil, errors.New("no aws config, did you call Init()?")
+	}
+
+	return literalProvider.opts, nil
+}
+
+func (literalProvider *LiteralProvider) readFile(ctx context.Context) (SSOFileConfig, error) {
+	path := literalProvider.cfg.File
+	appPath, ok := ctx.Value(string(constants.ContextKeyAppDir)).(string)
+	if ok && len(appPath) > 0 && !filepath.IsAbs(path) {
+		path = filepath.Join(appPath, path)
+	}
+
+	return readCredentialsFile(path)
+}
+
+func (literalProvider *LiteralProvider) readEnv() (SSOFileConfig, error) {
+	creds := SSOFileConfig{}
+	vars := []struct {
+		name  string
+		value *string
+	}{
+		{name: literalProvider.cfg.AccessKeyIdEnv, value: &creds.AccessKey},
+		{name: literalProvider.cfg.SecretAccessKeyEnv, value: &creds.SecretKey},
+		{name: literalProvider.cfg.SessionTokenEnv, value: &creds.SessionToken},
+	}
+
+	for _, v := range vars {
+		if len(v.name) == 0 {
+			continue
+		}
+		value, ok := os.LookupEnv(v.name)
+		if !ok {
+			return SSOFileConfig{}, errors.Errorf("environment variable %s is not set", v.name)
+		}
+		*v.value = strings.TrimSpace(value)
+	}
+
+	return creds, nil
+}
+
+func NewLiteralProvider(cfg pkgConfig.AwsConfigLiteral, logger *slog.Logger) *LiteralProvider {
+	p := &LiteralProvider{
+		cfg:    cfg,
+		logger: logger,
+	}
+
+	return p
+}
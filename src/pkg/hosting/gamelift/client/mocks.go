@@ -49,10 +49,28 @@ type ClientGameLiftMock struct {
 	DeregisterComputeInput  *gamelift.DeregisterComputeInput
 	DeregisterComputeResult *gamelift.DeregisterComputeOutput
 	DeregisterComputeError  error
+	DeregisterComputeCalls  int
 
 	GetComputeAuthTokenInput  *gamelift.GetComputeAuthTokenInput
 	GetComputeAuthTokenResult *gamelift.GetComputeAuthTokenOutput
 	GetComputeAuthTokenError  error
+
+	DescribeFleetAttributesInput  *gamelift.DescribeFleetAttributesInput
+	DescribeFleetAttributesResult *gamelift.DescribeFleetAttributesOutput
+	DescribeFleetAttributesError  error
+	DescribeFleetAttributesCalls  int
+
+	DescribeFleetLocationAttributesInput  *gamelift.DescribeFleetLocationAttributesInput
+	DescribeFleetLocationAttributesResult *gamelift.DescribeFleetLocationAttributesOutput
+	DescribeFleetLocationAttributesError  error
+
+	ListAliasesInput  *gamelift.ListAliasesInput
+	ListAliasesResult *gamelift.ListAliasesOutput
+	ListAliasesError  error
+
+	ResolveAliasInput  *gamelift.ResolveAliasInput
+	ResolveAliasResult *gamelift.ResolveAliasOutput
+	ResolveAliasError  error
 }
 
 func (clientGameLiftMock *ClientGameLiftMock) ListCompute(ctx context.Context, params *gamelift.ListComputeInput, optFns ...func(*gamelift.Options)) (*gamelift.ListComputeOutput, error) {
@@ -67,6 +85,7 @@ func (clientGameLiftMock *ClientGameLiftMock) RegisterCompute(ctx context.Contex
 
 func (clientGameLiftMock *ClientGameLiftMock) DeregisterCompute(ctx context.Context, params *gamelift.DeregisterComputeInput, optFns ...func(*gamelift.Options)) (*gamelift.DeregisterComputeOutput, error) {
 	clientGameLiftMock.DeregisterComputeInput = params
+	clientGameLiftMock.DeregisterComputeCalls++
 	return clientGameLiftMock.DeregisterComputeResult, clientGameLiftMock.DeregisterComputeError
 }
 
@@ -75,6 +94,27 @@ func (clientGameLiftMock *ClientGameLiftMock) GetComputeAuthToken(ctx context.Co
 	return clientGameLiftMock.GetComputeAuthTokenResult, clientGameLiftMock.GetComputeAuthTokenError
 }
 
+func (clientGameLiftMock *ClientGameLiftMock) DescribeFleetAttributes(ctx context.Context, params *gamelift.DescribeFleetAttributesInput, optFns ...func(*gamelift.Options)) (*gamelift.DescribeFleetAttributesOutput, error) {
+	clientGameLiftMock.DescribeFleetAttributesInput = params
+	clientGameLiftMock.DescribeFleetAttributesCalls++
+	return clientGameLiftMock.DescribeFleetAttributesResult, clientGameLiftMock.DescribeFleetAttributesError
+}
+
+func (clientGameLiftMock *ClientGameLiftMock) DescribeFleetLocationAttributes(ctx context.Context, params *gamelift.DescribeFleetLocationAttributesInput, optFns ...func(*gamelift.Options)) (*gamelift.DescribeFleetLocationAttributesOutput, error) {
+	clientGameLiftMock.DescribeFleetLocationAttributesInput = params
+	return clientGameLiftMock.DescribeFleetLocationAttributesResult, clientGameLiftMock.DescribeFleetLocationAttributesError
+}
+
+func (clientGameLiftMock *ClientGameLiftMock) ListAliases(ctx context.Context, params *gamelift.ListAliasesInput, optFns ...func(*gamelift.Options)) (*gamelift.ListAliasesOutput, error) {
+	clientGameLiftMock.ListAliasesInput = params
+	return clientGameLiftMock.ListAliasesResult, clientGameLiftMock.ListAliasesError
+}
+
+func (clientGameLiftMock *ClientGameLiftMock) ResolveAlias(ctx context.Context, params *gamelift.ResolveAliasInput, optFns ...func(*gamelift.Options)) (*gamelift.ResolveAliasOutput, error) {
+	clientGameLiftMock.ResolveAliasInput = params
+	return clientGameLiftMock.ResolveAliasResult, clientGameLiftMock.ResolveAliasError
+}
+
 type MockReadCloser struct {
 	ExpectedData []byte
 	ExpectedErr  error
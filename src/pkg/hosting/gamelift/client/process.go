@@ -0,0 +1,60 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package client
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awscfg "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/processcreds"
+	"github.com/pkg/errors"
+)
+
+var _ ConfigProvider = (*ProcessProvider)(nil)
+
+// ProcessProvider runs an external command printing credentials in the credential_process format, again
+// whenever the credentials it printed expire.
+type ProcessProvider struct {
+	command string
+	opts    []func(options *awscfg.LoadOptions) error
+	logger  *slog.Logger
+}
+
+func (processProvider *ProcessProvider) Init(ctx context.Context) error {
+	command := strings.TrimSpace(processProvider.command)
+	if len(command) == 0 {
+		return errors.New("no credential process command provided")
+	}
+
+	// the arguments can carry secrets, so only the executable is logged
+	processProvider.logger.DebugContext(ctx, "using credential process", "executable", strings.Fields(command)[0])
+
+	processProvider.opts = []func(options *awscfg.LoadOptions) error{
+		awscfg.WithCredentialsProvider(aws.NewCredentialsCache(processcreds.NewProvider(command))),
+	}
+
+	return nil
+}
+
+func (processProvider *ProcessProvider) GetOpts(ctx context.Context) ([]func(options *awscfg.LoadOptions) error, error) {
+	if processProvider.opts == nil {
+		return nil, errors.New("no aws config, did you call Init()?")
+	}
+
+	return processProvider.opts, nil
+}
+
+func NewProcessProvider(command string, logger *slog.Logger) *ProcessProvider {
+	p := &ProcessProvider{
+		command: command,
+		logger:  logger,
+	}
+
+	return p
+}
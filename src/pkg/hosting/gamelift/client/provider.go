@@ -11,9 +11,12 @@ import (
 	"log/slog"
 
 	pkgConfig "github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/network"
 	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	awscfg "github.com/aws/aws-sdk-go-v2/config"
 	gl "github.com/aws/aws-sdk-go-v2/service/gamelift"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/aws/smithy-go/logging"
 	"github.com/pkg/errors"
 )
@@ -34,9 +37,18 @@ type ConfigProvider interface {
 type provider struct {
 	optProvider ConfigProvider
 	cfg         pkgConfig.Anywhere
+	httpClient  *awshttp.BuildableClient // nil for the default AWS SDK client
 	logger      *slog.Logger
 }
 
+// redact keeps a credential out of the logs, while still showing whether it is set.
+func redact(value string) string {
+	if len(value) == 0 {
+		return ""
+	}
+	return "<REDACTED>"
+}
+
 func (provider *provider) Init(ctx context.Context) error {
 	return provider.optProvider.Init(ctx)
 }
@@ -67,6 +79,9 @@ func (provider *provider) GetAwsConfig(ctx context.Context) (aws.Config, error)
 	}
 
 	opts = append(opts, awscfg.WithRegion(provider.cfg.Config.Region))
+	if provider.httpClient != nil {
+		opts = append(opts, awscfg.WithHTTPClient(provider.httpClient))
+	}
 
 	return awscfg.LoadDefaultConfig(ctx, opts...)
 }
@@ -77,14 +92,69 @@ func (provider *provider) GetGameLift(ctx context.Context) (GameLift, error) {
 		return nil, err
 	}
 
-	return gl.NewFromConfig(cfg), nil
+	endpoint := provider.cfg.Network.GameLiftEndpoint
+	return gl.NewFromConfig(cfg, func(options *gl.Options) {
+		if len(endpoint) != 0 {
+			options.BaseEndpoint = aws.String(endpoint)
+		}
+	}), nil
+}
+
+// newSTSClient returns an STS client sending its requests with httpClient, unless it is nil.
+func newSTSClient(region string, credentials aws.CredentialsProvider, httpClient *awshttp.BuildableClient) *sts.Client {
+	options := sts.Options{
+		Region:      region,
+		Credentials: credentials,
+	}
+	if httpClient != nil {
+		options.HTTPClient = httpClient
+	}
+
+	return sts.New(options)
+}
+
+// profileRegion returns the region of the aws profile of cfg, or the default region of the environment
+// when the credentials do not come from a profile.
+func profileRegion(cfg pkgConfig.AwsConfig) (string, error) {
+	var opts []func(*awscfg.LoadOptions) error
+	if cfg.Provider == pkgConfig.AwsConfigProviderProfile && len(cfg.Profile) != 0 {
+		opts = append(opts, awscfg.WithSharedConfigProfile(cfg.Profile))
+	}
+
+	awsConfig, err := awscfg.LoadDefaultConfig(context.Background(), opts...)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read the region of the aws profile")
+	}
+
+	return awsConfig.Region, nil
 }
 
 func NewProvider(cfg pkgConfig.Anywhere, logger *slog.Logger) (Provider, error) {
 	var configProvider ConfigProvider
 	if len(cfg.Config.Region) == 0 {
-		return nil, errors.New("no region specified")
+		region, err := profileRegion(cfg.Config)
+		if err != nil {
+			return nil, err
+		}
+		if len(region) == 0 {
+			return nil, errors.New("no region specified, provide a fleet or location arn or set the region of the aws profile")
+		}
+		logger.Debug("using the region of the aws profile", "region", region)
+		cfg.Config.Region = region
+	}
+
+	// a buildable client lets the AWS SDK add the CA bundle of its own configuration on top of ours
+	var httpClient *awshttp.BuildableClient
+	if !network.IsDefault(cfg.Network) {
+		configure, err := network.TransportOptions(cfg.Network)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create http client")
+		}
+		httpClient = awshttp.NewBuildableClient().
+			WithTransportOptions(configure).
+			WithTimeout(cfg.Network.RequestTimeout)
 	}
+	logger.Debug("aws client network settings", "network", network.LogValue(cfg.Network))
 
 	switch cfg.Config.Provider {
 	case pkgConfig.AwsConfigProviderProfile:
@@ -93,7 +163,15 @@ func NewProvider(cfg pkgConfig.Anywhere, logger *slog.Logger) (Provider, error)
 		if len(cfg.Config.SSOFile) == 0 {
 			return nil, fmt.Errorf("no sso env file path provided")
 		}
-		configProvider = NewSSOFileProvider(cfg.Config.SSOFile, logger)
+		configProvider = NewSSOFileProvider(cfg.Config.SSOFile, cfg.Config.SSOFileRefresh, cfg.Config.Region, httpClient, logger)
+	case pkgConfig.AwsConfigProviderLiteral:
+		configProvider = NewLiteralProvider(cfg.Config.Literal, logger)
+	case pkgConfig.AwsConfigProviderDefault:
+		configProvider = NewDefaultProvider()
+	case pkgConfig.AwsConfigProviderCredentialProcess:
+		configProvider = NewProcessProvider(cfg.Config.CredentialProcess, logger)
+	case pkgConfig.AwsConfigProviderWebIdentity:
+		configProvider = NewWebIdentityProvider(cfg.Config.WebIdentity, cfg.Config.Region, httpClient, logger)
 	default:
 		return nil, fmt.Errorf("unknown provider: %s", cfg.Config.Provider)
 	}
@@ -101,6 +179,7 @@ func NewProvider(cfg pkgConfig.Anywhere, logger *slog.Logger) (Provider, error)
 	p := &provider{
 		optProvider: configProvider,
 		cfg:         cfg,
+		httpClient:  httpClient,
 		logger:      logger,
 	}
 
@@ -16,4 +16,8 @@ type GameLift interface {
 	RegisterCompute(ctx context.Context, params *gamelift.RegisterComputeInput, optFns ...func(*gamelift.Options)) (*gamelift.RegisterComputeOutput, error)
 	DeregisterCompute(ctx context.Context, params *gamelift.DeregisterComputeInput, optFns ...func(*gamelift.Options)) (*gamelift.DeregisterComputeOutput, error)
 	GetComputeAuthToken(ctx context.Context, params *gamelift.GetComputeAuthTokenInput, optFns ...func(*gamelift.Options)) (*gamelift.GetComputeAuthTokenOutput, error)
+	DescribeFleetAttributes(ctx context.Context, params *gamelift.DescribeFleetAttributesInput, optFns ...func(*gamelift.Options)) (*gamelift.DescribeFleetAttributesOutput, error)
+	DescribeFleetLocationAttributes(ctx context.Context, params *gamelift.DescribeFleetLocationAttributesInput, optFns ...func(*gamelift.Options)) (*gamelift.DescribeFleetLocationAttributesOutput, error)
+	ListAliases(ctx context.Context, params *gamelift.ListAliasesInput, optFns ...func(*gamelift.Options)) (*gamelift.ListAliasesOutput, error)
+	ResolveAlias(ctx context.Context, params *gamelift.ResolveAliasInput, optFns ...func(*gamelift.Options)) (*gamelift.ResolveAliasOutput, error)
 }
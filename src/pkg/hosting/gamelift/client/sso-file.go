@@ -8,33 +8,63 @@ package client
 import (
 	"context"
 	"log/slog"
+	"os/exec"
 	"reflect"
+	"runtime"
 	"strings"
 	"sync"
+	"time"
 
-	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/constants"
+	pkgConfig "github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	awscfg "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go"
 	"github.com/fsnotify/fsnotify"
 	"github.com/mitchellh/mapstructure"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 )
 
+const (
+	DefaultSSOFileRefreshBefore = 5 * time.Minute
+	DefaultSSOFileWarnBefore    = 15 * time.Minute
+
+	ssoFileCheckInterval  = 30 * time.Second
+	ssoFileRefreshTimeout = time.Minute
+)
+
 var _ ConfigProvider = (*SSOFileProvider)(nil)
 
+// SSOFileProvider reads credentials from a file rewritten by an external SSO tool, reloading them whenever the
+// file changes. When the file gives an expiration, or when STS reports the credentials as expired, it warns
+// ahead of expiry, runs the refresh command and fails calls with a clear error once they have expired.
 type SSOFileProvider struct {
-	path   string
-	opts   []func(options *awscfg.LoadOptions) error
-	logger *slog.Logger
-	v      *viper.Viper
-	mutex  sync.Mutex
+	path          string
+	cfg           pkgConfig.AwsConfigSSOFileRefresh
+	region        string
+	httpClient    *awshttp.BuildableClient
+	opts          []func(options *awscfg.LoadOptions) error
+	logger        *slog.Logger
+	v             *viper.Viper
+	mutex         sync.Mutex
+	checkInterval time.Duration
+
+	creds      SSOFileConfig
+	expiration time.Time // zero when the file gives none
+	expired    bool      // set when STS reports the credentials as expired
+	lastProbe  time.Time
+	warnedFor  time.Time
+	cache      *aws.CredentialsCache
 }
 
 type SSOFileConfig struct {
 	AccessKey    string `mapstructure:"aws_access_key_id"`
 	SecretKey    string `mapstructure:"aws_secret_access_key"`
 	SessionToken string `mapstructure:"aws_session_token"`
+	Expiration   string `mapstructure:"expiration"`
 }
 
 func (ssoFileProvider *SSOFileProvider) onConfigChange(ctx context.Context, e fsnotify.Event) {
@@ -72,19 +102,32 @@ func ssoDecodeHook(f reflect.Type, t reflect.Type, data interface{}) (interface{
 	return s, nil
 }
 
-func (ssoFileProvider *SSOFileProvider) loadOpts(ctx context.Context) error {
-	ssoFileProvider.mutex.Lock()
-	defer ssoFileProvider.mutex.Unlock()
+// readCredentialsFile reads a credentials file holding a single profile.
+func readCredentialsFile(path string) (SSOFileConfig, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return SSOFileConfig{}, errors.Wrap(err, "failed to read credentials file")
+	}
 
-	ssoFileProvider.logger.DebugContext(ctx, "loading aws config")
-	profile := SSOFileConfig{}
-	if err := ssoFileProvider.v.Unmarshal(&profile, viper.DecodeHook(ssoDecodeHook)); err != nil {
-		return errors.Wrap(err, "failed to unmarshal config")
+	creds := SSOFileConfig{}
+	if err := v.Unmarshal(&creds, viper.DecodeHook(ssoDecodeHook)); err != nil {
+		return SSOFileConfig{}, errors.Wrap(err, "failed to unmarshal credentials file")
 	}
 
-	profile.AccessKey = strings.TrimSpace(profile.AccessKey)
-	profile.SecretKey = strings.TrimSpace(profile.SecretKey)
-	profile.SessionToken = strings.TrimSpace(profile.SessionToken)
+	creds.AccessKey = strings.TrimSpace(creds.AccessKey)
+	creds.SecretKey = strings.TrimSpace(creds.SecretKey)
+	creds.SessionToken = strings.TrimSpace(creds.SessionToken)
+	creds.Expiration = strings.TrimSpace(creds.Expiration)
+	return creds, nil
+}
+
+func (ssoFileProvider *SSOFileProvider) loadOpts(ctx context.Context) error {
+	ssoFileProvider.logger.DebugContext(ctx, "loading aws config")
+	profile, err := readCredentialsFile(ssoFileProvider.path)
+	if err != nil {
+		return err
+	}
 
 	if l := len(profile.AccessKey); l < 16 {
 		return errors.Errorf("invalid access key length: %d", l)
@@ -98,25 +141,190 @@ func (ssoFileProvider *SSOFileProvider) loadOpts(ctx context.Context) error {
 		return errors.Errorf("invalid session token length: %d", l)
 	}
 
-	scp := credentials.NewStaticCredentialsProvider(profile.AccessKey, profile.SecretKey, profile.SessionToken)
-	scp.Value.CanExpire = false
+	var expiration time.Time
+	if len(profile.Expiration) != 0 {
+		if expiration, err = time.Parse(time.RFC3339, profile.Expiration); err != nil {
+			return errors.Wrap(err, "invalid expiration, expected an RFC 3339 time")
+		}
+	}
+
+	ssoFileProvider.mutex.Lock()
+	defer ssoFileProvider.mutex.Unlock()
+
+	ssoFileProvider.creds = profile
+	ssoFileProvider.expiration = expiration
+	ssoFileProvider.expired = false
+	ssoFileProvider.logger.DebugContext(ctx, "loaded aws credentials",
+		"accessKeyId", redact(profile.AccessKey),
+		"expiration", profile.Expiration)
+
+	if ssoFileProvider.cache == nil {
+		ssoFileProvider.cache = aws.NewCredentialsCache(aws.CredentialsProviderFunc(ssoFileProvider.retrieve))
+	} else {
+		ssoFileProvider.cache.Invalidate()
+	}
 
 	ssoFileProvider.opts = []func(options *awscfg.LoadOptions) error{
-		awscfg.WithCredentialsProvider(scp),
+		awscfg.WithCredentialsProvider(ssoFileProvider.cache),
 		awscfg.WithDefaultRegion("us-east-1"),
 	}
 
 	return nil
 }
 
-func (ssoFileProvider *SSOFileProvider) Init(ctx context.Context) error {
+// retrieve returns the credentials last read from the file, and fails once they have expired.
+func (ssoFileProvider *SSOFileProvider) retrieve(ctx context.Context) (aws.Credentials, error) {
+	ssoFileProvider.mutex.Lock()
+	defer ssoFileProvider.mutex.Unlock()
 
-	ssoFileProvider.v = viper.New()
-	appPath, ok := ctx.Value(string(constants.ContextKeyAppDir)).(string)
-	if ok && len(appPath) > 0 {
-		ssoFileProvider.v.AddConfigPath(appPath)
+	if err := ssoFileProvider.expiredError(); err != nil {
+		return aws.Credentials{}, err
+	}
+
+	return aws.Credentials{
+		AccessKeyID:     ssoFileProvider.creds.AccessKey,
+		SecretAccessKey: ssoFileProvider.creds.SecretKey,
+		SessionToken:    ssoFileProvider.creds.SessionToken,
+		Source:          "SSOFileProvider",
+		CanExpire:       !ssoFileProvider.expiration.IsZero(),
+		Expires:         ssoFileProvider.expiration,
+	}, nil
+}
+
+// expiredError returns why the credentials cannot be used anymore, nil while they can. The mutex must be held.
+func (ssoFileProvider *SSOFileProvider) expiredError() error {
+	if ssoFileProvider.expired {
+		return errors.Errorf("aws credentials in %s have expired, rewrite the file with new credentials", ssoFileProvider.path)
+	}
+
+	if !ssoFileProvider.expiration.IsZero() && !time.Now().Before(ssoFileProvider.expiration) {
+		return errors.Errorf("aws credentials in %s expired at %s, rewrite the file with new credentials",
+			ssoFileProvider.path, ssoFileProvider.expiration.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// probe asks STS whether the credentials have expired, for files that do not give an expiration.
+func (ssoFileProvider *SSOFileProvider) probe(ctx context.Context) {
+	ssoFileProvider.mutex.Lock()
+	creds := ssoFileProvider.creds
+	ssoFileProvider.lastProbe = time.Now()
+	ssoFileProvider.mutex.Unlock()
+
+	stsClient := newSTSClient(ssoFileProvider.region,
+		credentials.NewStaticCredentialsProvider(creds.AccessKey, creds.SecretKey, creds.SessionToken),
+		ssoFileProvider.httpClient)
+
+	_, err := stsClient.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err == nil {
+		return
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && (apiErr.ErrorCode() == "ExpiredToken" || apiErr.ErrorCode() == "ExpiredTokenException") {
+		ssoFileProvider.markExpired(creds)
+		return
+	}
+
+	ssoFileProvider.logger.WarnContext(ctx, "failed to probe aws credentials", "err", err)
+}
+
+// markExpired flags creds as expired, unless the file was reloaded meanwhile. The cached credentials are
+// invalidated, as credentials without an expiration would otherwise be served from the cache forever.
+func (ssoFileProvider *SSOFileProvider) markExpired(creds SSOFileConfig) {
+	ssoFileProvider.mutex.Lock()
+	defer ssoFileProvider.mutex.Unlock()
+
+	if ssoFileProvider.creds != creds {
+		return
 	}
 
+	ssoFileProvider.expired = true
+	if ssoFileProvider.cache != nil {
+		ssoFileProvider.cache.Invalidate()
+	}
+}
+
+// check warns about credentials close to expiry, and runs the refresh command when they are within the refresh
+// window or have expired.
+func (ssoFileProvider *SSOFileProvider) check(ctx context.Context) {
+	ssoFileProvider.mutex.Lock()
+	shouldProbe := ssoFileProvider.expiration.IsZero() && ssoFileProvider.cfg.ProbeInterval > 0 &&
+		time.Since(ssoFileProvider.lastProbe) >= ssoFileProvider.cfg.ProbeInterval
+	ssoFileProvider.mutex.Unlock()
+
+	if shouldProbe {
+		ssoFileProvider.probe(ctx)
+	}
+
+	ssoFileProvider.mutex.Lock()
+	expiration := ssoFileProvider.expiration
+	expiredErr := ssoFileProvider.expiredError()
+	ssoFileProvider.mutex.Unlock()
+
+	remaining := time.Until(expiration)
+	refresh := expiredErr != nil
+	switch {
+	case expiredErr != nil:
+		ssoFileProvider.logger.ErrorContext(ctx, "aws credentials have expired", "err", expiredErr)
+	case expiration.IsZero():
+	default:
+		if remaining <= ssoFileProvider.cfg.WarnBefore && !ssoFileProvider.warnedFor.Equal(expiration) {
+			ssoFileProvider.warnedFor = expiration
+			ssoFileProvider.logger.WarnContext(ctx, "aws credentials expire soon",
+				"file", ssoFileProvider.path, "expiration", expiration.Format(time.RFC3339), "expiresIn", remaining.Round(time.Second).String())
+		}
+		refresh = remaining <= ssoFileProvider.cfg.RefreshBefore
+	}
+
+	if refresh && len(ssoFileProvider.cfg.Command) != 0 {
+		if err := ssoFileProvider.refresh(ctx); err != nil {
+			ssoFileProvider.logger.ErrorContext(ctx, "failed to refresh aws credentials", "err", err)
+		}
+	}
+}
+
+// refresh runs the refresh command and reloads the file it rewrote.
+func (ssoFileProvider *SSOFileProvider) refresh(ctx context.Context) error {
+	command := ssoFileProvider.cfg.Command
+
+	// the arguments can carry secrets, so only the executable is logged
+	ssoFileProvider.logger.InfoContext(ctx, "refreshing aws credentials", "executable", strings.Fields(command)[0])
+
+	ctx, cancel := context.WithTimeout(ctx, ssoFileRefreshTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd.exe", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+
+	if err := cmd.Run(); err != nil {
+		return errors.Wrap(err, "refresh command failed")
+	}
+
+	return ssoFileProvider.loadOpts(ctx)
+}
+
+func (ssoFileProvider *SSOFileProvider) run(ctx context.Context) {
+	ticker := time.NewTicker(ssoFileProvider.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ssoFileProvider.check(ctx)
+		}
+	}
+}
+
+func (ssoFileProvider *SSOFileProvider) Init(ctx context.Context) error {
+	ssoFileProvider.v = viper.New()
 	ssoFileProvider.v.SetConfigFile(ssoFileProvider.path)
 
 	if err := ssoFileProvider.v.ReadInConfig(); err != nil {
@@ -127,9 +335,34 @@ func (ssoFileProvider *SSOFileProvider) Init(ctx context.Context) error {
 		return errors.Wrap(err, "failed to load config")
 	}
 
+	if ssoFileProvider.cfg.ProbeInterval > 0 {
+		ssoFileProvider.probe(ctx)
+	}
+
+	ssoFileProvider.mutex.Lock()
+	err := ssoFileProvider.expiredError()
+	ssoFileProvider.mutex.Unlock()
+	if err != nil {
+		if len(ssoFileProvider.cfg.Command) == 0 {
+			return err
+		}
+		if err := ssoFileProvider.refresh(ctx); err != nil {
+			return errors.Wrap(err, "failed to refresh expired aws credentials")
+		}
+
+		ssoFileProvider.mutex.Lock()
+		err = ssoFileProvider.expiredError()
+		ssoFileProvider.mutex.Unlock()
+		if err != nil {
+			return errors.Wrap(err, "refresh command did not renew the credentials")
+		}
+	}
+
 	ssoFileProvider.v.WatchConfig()
 	ssoFileProvider.v.OnConfigChange(func(e fsnotify.Event) { ssoFileProvider.onConfigChange(ctx, e) })
 
+	go ssoFileProvider.run(ctx)
+
 	return nil
 }
 
@@ -144,10 +377,22 @@ func (ssoFileProvider *SSOFileProvider) GetOpts(ctx context.Context) ([]func(opt
 	return ssoFileProvider.opts, nil
 }
 
-func NewSSOFileProvider(path string, logger *slog.Logger) *SSOFileProvider {
+func NewSSOFileProvider(path string, cfg pkgConfig.AwsConfigSSOFileRefresh, region string, httpClient *awshttp.BuildableClient, logger *slog.Logger) *SSOFileProvider {
+	cfg.Command = strings.TrimSpace(cfg.Command)
+	if cfg.RefreshBefore == 0 {
+		cfg.RefreshBefore = DefaultSSOFileRefreshBefore
+	}
+	if cfg.WarnBefore == 0 {
+		cfg.WarnBefore = DefaultSSOFileWarnBefore
+	}
+
 	p := &SSOFileProvider{
-		path:   path,
-		logger: logger,
+		path:          path,
+		cfg:           cfg,
+		region:        region,
+		httpClient:    httpClient,
+		logger:        logger,
+		checkInterval: ssoFileCheckInterval,
 	}
 
 	return p
@@ -0,0 +1,209 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	pkgConfig "github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	awscfg "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSessionToken = "FwoGZXIvYXdzEXAMPLESESSIONTOKEN"
+
+// writeSSOFile writes an sso file with the test credentials, with an expiration when it is not zero.
+func writeSSOFile(t *testing.T, path string, accessKeyId string, expiration time.Time) {
+	contents := "[gamelift]\naws_access_key_id = " + accessKeyId +
+		"\naws_secret_access_key = " + testSecretAccessKey +
+		"\naws_session_token = " + testSessionToken + "\n"
+	if !expiration.IsZero() {
+		contents += "expiration = " + expiration.UTC().Format(time.RFC3339) + "\n"
+	}
+	assert.NoError(t, os.WriteFile(path, []byte(contents), 0600))
+}
+
+func createSSOFileProvider(t *testing.T, path string, cfg pkgConfig.AwsConfigSSOFileRefresh) (*SSOFileProvider, *bytes.Buffer) {
+	logBuffer := &bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(logBuffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	return NewSSOFileProvider(path, cfg, "us-west-2", nil, logger), logBuffer
+}
+
+func Test_SSOFileProvider_Expiration(t *testing.T) {
+	//arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	path := filepath.Join(t.TempDir(), "sso.ini")
+	expiration := time.Now().Add(time.Hour).Truncate(time.Second)
+	writeSSOFile(t, path, testAccessKeyId, expiration)
+	p, _ := createSSOFileProvider(t, path, pkgConfig.AwsConfigSSOFileRefresh{})
+
+	//act
+	assert.NoError(t, p.Init(ctx))
+	creds, err := p.retrieve(ctx)
+
+	//assert
+	assert.NoError(t, err)
+	assert.Equal(t, testAccessKeyId, creds.AccessKeyID)
+	assert.True(t, creds.CanExpire)
+	assert.True(t, expiration.Equal(creds.Expires))
+}
+
+func Test_SSOFileProvider_Expired(t *testing.T) {
+	//arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	path := filepath.Join(t.TempDir(), "sso.ini")
+	writeSSOFile(t, path, testAccessKeyId, time.Now().Add(-time.Minute))
+	p, _ := createSSOFileProvider(t, path, pkgConfig.AwsConfigSSOFileRefresh{})
+
+	//act
+	err := p.Init(ctx)
+
+	//assert
+	assert.ErrorContains(t, err, "aws credentials in "+path+" expired at")
+}
+
+func Test_SSOFileProvider_Retrieve_Expired(t *testing.T) {
+	//arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	path := filepath.Join(t.TempDir(), "sso.ini")
+	writeSSOFile(t, path, testAccessKeyId, time.Now().Add(time.Hour))
+	p, _ := createSSOFileProvider(t, path, pkgConfig.AwsConfigSSOFileRefresh{})
+	assert.NoError(t, p.Init(ctx))
+
+	//act
+	p.mutex.Lock()
+	p.expiration = time.Now().Add(-time.Second)
+	p.mutex.Unlock()
+	_, err := p.retrieve(ctx)
+
+	//assert
+	assert.ErrorContains(t, err, "rewrite the file with new credentials")
+}
+
+func Test_SSOFileProvider_Cache_MarkExpired(t *testing.T) {
+	//arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	path := filepath.Join(t.TempDir(), "sso.ini")
+	writeSSOFile(t, path, testAccessKeyId, time.Time{})
+	p, _ := createSSOFileProvider(t, path, pkgConfig.AwsConfigSSOFileRefresh{})
+	assert.NoError(t, p.Init(ctx))
+	opts, err := p.GetOpts(ctx)
+	assert.NoError(t, err)
+	cfg, err := awscfg.LoadDefaultConfig(ctx, opts...)
+	assert.NoError(t, err)
+	_, err = cfg.Credentials.Retrieve(ctx)
+	assert.NoError(t, err)
+
+	//act
+	p.mutex.Lock()
+	creds := p.creds
+	p.mutex.Unlock()
+	p.markExpired(creds)
+	_, err = cfg.Credentials.Retrieve(ctx)
+
+	//assert
+	assert.ErrorContains(t, err, "rewrite the file with new credentials")
+}
+
+func Test_NewSSOFileProvider_BlankCommand(t *testing.T) {
+	//arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	path := filepath.Join(t.TempDir(), "sso.ini")
+	writeSSOFile(t, path, testAccessKeyId, time.Now().Add(-time.Minute))
+	p, _ := createSSOFileProvider(t, path, pkgConfig.AwsConfigSSOFileRefresh{Command: "   "})
+
+	//act
+	err := p.Init(ctx)
+
+	//assert
+	assert.ErrorContains(t, err, "expired at")
+}
+
+func Test_SSOFileProvider_Expired_RefreshCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the command is a shell command")
+	}
+
+	//arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sso.ini")
+	fresh := filepath.Join(dir, "fresh.ini")
+	writeSSOFile(t, path, testAccessKeyId, time.Now().Add(-time.Minute))
+	writeSSOFile(t, fresh, "AKIAI44QH8DHBEXAMPLE", time.Now().Add(time.Hour))
+	p, logBuffer := createSSOFileProvider(t, path, pkgConfig.AwsConfigSSOFileRefresh{
+		Command: "cp " + fresh + " " + path,
+	})
+
+	//act
+	err := p.Init(ctx)
+	creds, retrieveErr := p.retrieve(ctx)
+
+	//assert
+	assert.NoError(t, err)
+	assert.NoError(t, retrieveErr)
+	assert.Equal(t, "AKIAI44QH8DHBEXAMPLE", creds.AccessKeyID)
+	assert.Contains(t, logBuffer.String(), "refreshing aws credentials")
+	assert.NotContains(t, logBuffer.String(), testSecretAccessKey)
+}
+
+func Test_SSOFileProvider_Check(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the command is a shell command")
+	}
+
+	tests := []struct {
+		name          string
+		expiresIn     time.Duration
+		expectWarning bool
+		expectRefresh bool
+	}{
+		{name: "valid", expiresIn: time.Hour},
+		{name: "warn window", expiresIn: 10 * time.Minute, expectWarning: true},
+		{name: "refresh window", expiresIn: 2 * time.Minute, expectWarning: true, expectRefresh: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			//arrange
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+			dir := t.TempDir()
+			path := filepath.Join(dir, "sso.ini")
+			marker := filepath.Join(dir, "refreshed")
+			writeSSOFile(t, path, testAccessKeyId, time.Now().Add(tt.expiresIn))
+			p, logBuffer := createSSOFileProvider(t, path, pkgConfig.AwsConfigSSOFileRefresh{
+				Command: "touch " + marker,
+			})
+			assert.NoError(t, p.Init(ctx))
+
+			//act
+			p.check(ctx)
+			p.check(ctx)
+
+			//assert
+			_, err := os.Stat(marker)
+			assert.Equal(t, tt.expectRefresh, err == nil)
+			assert.Equal(t, tt.expectWarning, bytes.Contains(logBuffer.Bytes(), []byte("aws credentials expire soon")))
+			if tt.expectWarning {
+				assert.Equal(t, 1, bytes.Count(logBuffer.Bytes(), []byte("aws credentials expire soon")))
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package client
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+
+	pkgConfig "github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/constants"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	awscfg "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/pkg/errors"
+)
+
+var _ ConfigProvider = (*WebIdentityProvider)(nil)
+
+// WebIdentityProvider assumes a role with the web identity token read from a file, such as a Kubernetes
+// service account token. The token file is read again every time the credentials are refreshed.
+type WebIdentityProvider struct {
+	cfg        pkgConfig.AwsConfigWebIdentity
+	region     string
+	httpClient *awshttp.BuildableClient
+	opts       []func(options *awscfg.LoadOptions) error
+	logger     *slog.Logger
+}
+
+func (webIdentityProvider *WebIdentityProvider) Init(ctx context.Context) error {
+	if len(webIdentityProvider.cfg.RoleArn) == 0 {
+		return errors.New("no web identity role arn provided")
+	}
+	if len(webIdentityProvider.cfg.TokenFile) == 0 {
+		return errors.New("no web identity token file provided")
+	}
+
+	tokenFile := webIdentityProvider.cfg.TokenFile
+	appPath, ok := ctx.Value(string(constants.ContextKeyAppDir)).(string)
+	if ok && len(appPath) > 0 && !filepath.IsAbs(tokenFile) {
+		tokenFile = filepath.Join(appPath, tokenFile)
+	}
+
+	webIdentityProvider.logger.DebugContext(ctx, "using web identity",
+		"roleArn", webIdentityProvider.cfg.RoleArn,
+		"tokenFile", tokenFile)
+
+	stsClient := newSTSClient(webIdentityProvider.region, nil, webIdentityProvider.httpClient)
+	provider := stscreds.NewWebIdentityRoleProvider(stsClient, webIdentityProvider.cfg.RoleArn, stscreds.IdentityTokenFile(tokenFile),
+		func(options *stscreds.WebIdentityRoleOptions) {
+			options.RoleSessionName = webIdentityProvider.cfg.RoleSessionName
+		})
+
+	webIdentityProvider.opts = []func(options *awscfg.LoadOptions) error{
+		awscfg.WithCredentialsProvider(aws.NewCredentialsCache(provider)),
+	}
+
+	return nil
+}
+
+func (webIdentityProvider *WebIdentityProvider) GetOpts(ctx context.Context) ([]func(options *awscfg.LoadOptions) error, error) {
+	if webIdentityProvider.opts == nil {
+		return nil, errors.New("no aws config, did you call Init()?")
+	}
+
+	return webIdentityProvider.opts, nil
+}
+
+func NewWebIdentityProvider(cfg pkgConfig.AwsConfigWebIdentity, region string, httpClient *awshttp.BuildableClient, logger *slog.Logger) *WebIdentityProvider {
+	p := &WebIdentityProvider{
+		cfg:        cfg,
+		region:     region,
+		httpClient: httpClient,
+		logger:     logger,
+	}
+
+	return p
+}
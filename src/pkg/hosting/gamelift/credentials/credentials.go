@@ -0,0 +1,272 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package credentials serves the fleet role credentials to the game server on a loopback endpoint
+// compatible with the container credentials provider of the AWS SDKs.
+package credentials
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/network"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/request"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/result"
+	"github.com/pkg/errors"
+)
+
+const (
+	// EnvironmentKeyFullUri is read by the AWS SDKs to find the container credentials endpoint.
+	EnvironmentKeyFullUri = "AWS_CONTAINER_CREDENTIALS_FULL_URI"
+	// EnvironmentKeyAuthorizationToken is sent by the AWS SDKs in the Authorization header of credentials requests.
+	EnvironmentKeyAuthorizationToken = "AWS_CONTAINER_AUTHORIZATION_TOKEN"
+
+	DefaultAddress       = "127.0.0.1:0"
+	DefaultRefreshBefore = 5 * time.Minute
+
+	credentialsPath = "/credentials"
+	retryInterval   = 30 * time.Second
+)
+
+// Source fetches fleet role credentials, it is implemented by the Amazon GameLift SDK.
+type Source interface {
+	GetFleetRoleCredentials(ctx context.Context, req request.GetFleetRoleCredentialsRequest) (result.GetFleetRoleCredentialsResult, error)
+}
+
+// Credentials is the response of the endpoint, in the format expected by the AWS SDKs.
+type Credentials struct {
+	AccessKeyId     string `json:"AccessKeyId"`
+	SecretAccessKey string `json:"SecretAccessKey"`
+	Token           string `json:"Token"`
+	Expiration      string `json:"Expiration"`
+
+	expiration time.Time
+}
+
+// Server caches the fleet role credentials, refreshes them ahead of expiry and serves them on a loopback address.
+type Server struct {
+	cfg    config.FleetRoleCredentials
+	source Source
+	logger *slog.Logger
+	token  string
+	now    func() time.Time
+
+	credentialsMutex sync.Mutex // also serializes fetches
+	credentials      *Credentials
+
+	mutex    sync.Mutex
+	listener net.Listener
+	server   *http.Server
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// Env returns the environment variables pointing the AWS SDKs of the game server to the endpoint.
+// It is empty until the server is started.
+func (server *Server) Env() map[string]string {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	if server.listener == nil {
+		return map[string]string{}
+	}
+
+	return map[string]string{
+		EnvironmentKeyFullUri:            "http://" + server.listener.Addr().String() + credentialsPath,
+		EnvironmentKeyAuthorizationToken: server.token,
+	}
+}
+
+// Start listens on the loopback address and keeps the credentials fresh until the server is closed.
+// The Amazon GameLift SDK only returns credentials once the process is ready.
+//
+// Parameters:
+//   - ctx: Context bounding the credential refreshes
+//
+// Returns:
+//   - error: Any error listening on the address
+func (server *Server) Start(ctx context.Context) error {
+	listener, err := net.Listen("tcp", server.cfg.Address)
+	if err != nil {
+		return errors.Wrapf(err, "failed to listen on '%s'", server.cfg.Address)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+credentialsPath, server.handle)
+
+	server.mutex.Lock()
+	server.listener = listener
+	server.server = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	server.mutex.Unlock()
+
+	server.logger.InfoContext(ctx, "fleet role credentials endpoint listening", "address", listener.Addr().String(), "roleArn", server.cfg.RoleArn)
+	go func() {
+		if err := server.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			server.logger.ErrorContext(ctx, "fleet role credentials endpoint failed", "err", err)
+		}
+	}()
+
+	go server.refreshLoop(ctx)
+
+	return nil
+}
+
+// Close stops refreshing and serving the credentials.
+//
+// Parameters:
+//   - ctx: Context bounding the shutdown of the endpoint
+//
+// Returns:
+//   - error: Any error shutting down the endpoint
+func (server *Server) Close(ctx context.Context) error {
+	server.stopOnce.Do(func() {
+		close(server.stop)
+	})
+
+	server.mutex.Lock()
+	httpServer := server.server
+	server.mutex.Unlock()
+
+	if httpServer == nil {
+		return nil
+	}
+
+	return httpServer.Shutdown(ctx)
+}
+
+func (server *Server) handle(w http.ResponseWriter, r *http.Request) {
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte(server.token)) != 1 {
+		http.Error(w, "invalid authorization token", http.StatusUnauthorized)
+		return
+	}
+
+	credentials, err := server.get(r.Context())
+	if err != nil {
+		server.logger.WarnContext(r.Context(), "failed to serve fleet role credentials", "err", err)
+		http.Error(w, "fleet role credentials are not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(credentials)
+}
+
+// get returns the cached credentials, fetching new ones when they are due to be refreshed.
+// Credentials that have not expired yet are still returned if the refresh fails.
+func (server *Server) get(ctx context.Context) (*Credentials, error) {
+	server.credentialsMutex.Lock()
+	defer server.credentialsMutex.Unlock()
+
+	now := server.now()
+	cached := server.credentials
+	if cached != nil && now.Before(cached.expiration.Add(-server.cfg.RefreshBefore)) {
+		return cached, nil
+	}
+
+	req := request.NewGetFleetRoleCredentials()
+	req.RoleArn = server.cfg.RoleArn
+	req.RoleSessionName = server.cfg.RoleSessionName
+
+	res, err := server.source.GetFleetRoleCredentials(ctx, req)
+	if err != nil {
+		if cached != nil && now.Before(cached.expiration) {
+			server.logger.WarnContext(ctx, "failed to refresh fleet role credentials, serving the cached credentials", "expiration", cached.expiration, "err", err)
+			return cached, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get fleet role credentials for '%s'", server.cfg.RoleArn)
+	}
+
+	expiration := time.UnixMilli(res.Expiration).UTC()
+	server.credentials = &Credentials{
+		AccessKeyId:     res.AccessKeyID,
+		SecretAccessKey: res.SecretAccessKey,
+		Token:           res.SessionToken,
+		Expiration:      expiration.Format(time.RFC3339),
+		expiration:      expiration,
+	}
+	server.logger.DebugContext(ctx, "fetched fleet role credentials", "roleArn", server.cfg.RoleArn, "expiration", expiration)
+
+	return server.credentials, nil
+}
+
+// refreshLoop refreshes the credentials ahead of expiry, so requests from the game server do not wait on the SDK.
+func (server *Server) refreshLoop(ctx context.Context) {
+	for {
+		wait := retryInterval
+		if credentials, err := server.get(ctx); err != nil {
+			server.logger.WarnContext(ctx, "failed to refresh fleet role credentials", "retryIn", wait, "err", err)
+		} else if next := credentials.expiration.Add(-server.cfg.RefreshBefore).Sub(server.now()); next > 0 {
+			wait = next
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-server.stop:
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "failed to generate authorization token")
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// New creates a fleet role credentials server.
+//
+// Parameters:
+//   - cfg: The fleet role credentials configuration
+//   - source: Source of the fleet role credentials
+//   - logger: Logger for the endpoint
+//
+// Returns:
+//   - *Server: The credentials server, not yet started
+//   - error: Any error in the configuration
+func New(cfg config.FleetRoleCredentials, source Source, logger *slog.Logger) (*Server, error) {
+	if len(cfg.RoleArn) == 0 {
+		return nil, errors.New("fleet role credentials need a role arn")
+	}
+	if len(cfg.Address) == 0 {
+		cfg.Address = DefaultAddress
+	}
+	if cfg.RefreshBefore <= 0 {
+		cfg.RefreshBefore = DefaultRefreshBefore
+	}
+	// the loopback interface is also the only plain HTTP host the AWS SDKs accept for a full credentials URI
+	if err := network.ValidateLoopbackAddress(cfg.Address); err != nil {
+		return nil, errors.Wrapf(err, "invalid fleet role credentials address '%s'", cfg.Address)
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Server{
+		cfg:    cfg,
+		source: source,
+		logger: logger,
+		token:  token,
+		now:    time.Now,
+		stop:   make(chan struct{}),
+	}, nil
+}
@@ -0,0 +1,178 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/internal/mocks"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/result"
+	"github.com/stretchr/testify/assert"
+)
+
+const testRoleArn = "arn:aws:iam::123456789012:role/game-role"
+
+type credentialsMockHelper struct {
+	server    *Server
+	sdk       *mocks.GameLiftSdkMock
+	logBuffer *bytes.Buffer
+	now       time.Time
+}
+
+func createCredentialsMockHelper(t *testing.T) *credentialsMockHelper {
+	logBuffer := bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(&logBuffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	sdk := &mocks.GameLiftSdkMock{
+		FleetRoleCredentials: result.GetFleetRoleCredentialsResult{
+			AccessKeyID:     "AKIAEXAMPLE",
+			SecretAccessKey: "secret",
+			SessionToken:    "session",
+			Expiration:      now.Add(time.Hour).UnixMilli(),
+		},
+	}
+
+	server, err := New(config.FleetRoleCredentials{RoleArn: testRoleArn}, sdk, logger)
+	assert.NoError(t, err)
+
+	helper := &credentialsMockHelper{server: server, sdk: sdk, logBuffer: &logBuffer, now: now}
+	server.now = func() time.Time { return helper.now }
+	return helper
+}
+
+func (helper *credentialsMockHelper) request(token string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, credentialsPath, nil)
+	r.Header.Set("Authorization", token)
+	w := httptest.NewRecorder()
+	helper.server.handle(w, r)
+	return w
+}
+
+func TestServer_Unauthorized(t *testing.T) {
+	//arrange
+	helper := createCredentialsMockHelper(t)
+
+	//act
+	w := helper.request("wrong")
+
+	//assert
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Empty(t, helper.sdk.FleetRoleCredentialsRequests)
+}
+
+func TestServer_ServesCachedCredentials(t *testing.T) {
+	//arrange
+	helper := createCredentialsMockHelper(t)
+
+	//act
+	first := helper.request(helper.server.token)
+	second := helper.request(helper.server.token)
+
+	//assert
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, http.StatusOK, second.Code)
+	assert.Len(t, helper.sdk.FleetRoleCredentialsRequests, 1)
+	assert.Equal(t, testRoleArn, helper.sdk.FleetRoleCredentialsRequests[0].RoleArn)
+
+	credentials := Credentials{}
+	assert.NoError(t, json.Unmarshal(first.Body.Bytes(), &credentials))
+	assert.Equal(t, "AKIAEXAMPLE", credentials.AccessKeyId)
+	assert.Equal(t, "secret", credentials.SecretAccessKey)
+	assert.Equal(t, "session", credentials.Token)
+	assert.Equal(t, "2025-01-01T13:00:00Z", credentials.Expiration)
+	assert.NotContains(t, helper.logBuffer.String(), "secret")
+}
+
+func TestServer_RefreshesAheadOfExpiry(t *testing.T) {
+	//arrange
+	helper := createCredentialsMockHelper(t)
+	helper.request(helper.server.token)
+	helper.now = helper.now.Add(time.Hour - DefaultRefreshBefore + time.Second)
+
+	//act
+	w := helper.request(helper.server.token)
+
+	//assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, helper.sdk.FleetRoleCredentialsRequests, 2)
+}
+
+func TestServer_RefreshFailed(t *testing.T) {
+	//arrange
+	helper := createCredentialsMockHelper(t)
+	helper.request(helper.server.token)
+	helper.sdk.FleetRoleCredentialsError = errors.New("Unit Test")
+
+	//act
+	helper.now = helper.now.Add(time.Hour - time.Minute)
+	unexpired := helper.request(helper.server.token)
+	helper.now = helper.now.Add(2 * time.Minute)
+	expired := helper.request(helper.server.token)
+
+	//assert
+	assert.Equal(t, http.StatusOK, unexpired.Code)
+	assert.Equal(t, http.StatusServiceUnavailable, expired.Code)
+	assert.Contains(t, helper.logBuffer.String(), "serving the cached credentials")
+}
+
+func TestServer_Start(t *testing.T) {
+	//arrange
+	helper := createCredentialsMockHelper(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	//act
+	envBeforeStart := helper.server.Env()
+	err := helper.server.Start(ctx)
+	env := helper.server.Env()
+
+	//assert
+	assert.NoError(t, err)
+	assert.Empty(t, envBeforeStart)
+	assert.Equal(t, helper.server.token, env[EnvironmentKeyAuthorizationToken])
+
+	r, err := http.NewRequest(http.MethodGet, env[EnvironmentKeyFullUri], nil)
+	assert.NoError(t, err)
+	r.Header.Set("Authorization", env[EnvironmentKeyAuthorizationToken])
+	res, err := http.DefaultClient.Do(r)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	_ = res.Body.Close()
+
+	assert.NoError(t, helper.server.Close(ctx))
+}
+
+func TestNew_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      config.FleetRoleCredentials
+		expected string
+	}{
+		{"no role", config.FleetRoleCredentials{}, "need a role arn"},
+		{"not loopback", config.FleetRoleCredentials{RoleArn: testRoleArn, Address: "0.0.0.0:8080"}, "loopback"},
+		{"invalid address", config.FleetRoleCredentials{RoleArn: testRoleArn, Address: "localhost"}, "invalid fleet role credentials address"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			//act
+			server, err := New(tt.cfg, &mocks.GameLiftSdkMock{}, slog.Default())
+
+			//assert
+			assert.Nil(t, server)
+			assert.ErrorContains(t, err, tt.expected)
+		})
+	}
+}
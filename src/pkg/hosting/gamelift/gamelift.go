@@ -6,30 +6,40 @@
 package gamelift
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"log/slog"
 	"os"
-	"os/exec"
-	"runtime"
+	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/internal"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/constants"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/result"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server"
 
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting/gamelift/credentials"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting/gamelift/initialiser"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting/gamelift/platform"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting/gamelift/players"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting/gamelift/sdk"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/logcollector"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/observability"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/types/events"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type gamelift struct {
@@ -42,12 +52,26 @@ type gamelift struct {
 	logDir           string
 	runId            uuid.UUID
 	gameServerLogDir string
+	unhealthy        atomic.Bool // set when the last health check reported the game as unhealthy
+	activated        atomic.Bool // set once the current game session was activated
 
 	onHealthCheck      func(ctx context.Context) events.GameStatus
 	onHostingStart     func(ctx context.Context, h *events.HostingStart, end <-chan error) error
 	onHostingTerminate func(ctx context.Context, h *events.HostingTerminate) error
 	onError            func(err error)
 	init               initialiser.Service
+	credentials        *credentials.Server // nil when fleet role credentials are not served
+	logCollector       *logcollector.Collector
+	players            *players.Tracker
+
+	drainMutex     sync.Mutex
+	draining       bool // set once drained, no game session is started anymore
+	sessionStarted bool
+
+	certificateMutex   sync.Mutex
+	certificateFetched bool
+	certificate        result.GetComputeCertificateResult
+	certificateErr     error // set when the fleet has no certificate, failed calls are not kept
 }
 
 type Config struct {
@@ -55,6 +79,12 @@ type Config struct {
 	Anywhere               config.Anywhere // Contains configuration for GameLift Anywhere fleet
 	LogDirectory           string          // Specifies the directory for general logging
 	GameServerLogDirectory string          // Specifies the directory for game server specific logs
+	WaitForReady           bool            // Leaves game session activation to ActivateGameSession
+	FleetRoleCredentials   config.FleetRoleCredentials
+	ComputeCertificate     config.ComputeCertificatePolicy // Whether the compute TLS certificate is provided to the game server
+	LogCollection          config.LogCollection            // Which game server logs are collected at shutdown
+	LogPaths               []string                        // Extra directories, files or globs uploaded with the logs
+	PlayerTracking         config.PlayerTracking           // How players are tracked and whether capacity is enforced
 }
 
 // Init initializes the Amazon GameLift SDK with the provided configuration.
@@ -110,6 +140,7 @@ func (gameLift *gamelift) Run(ctx context.Context) error {
 	} else {
 		gameLift.logger.WarnContext(ctx, "no log directory specified - no logs will be saved to Amazon GameLift")
 	}
+	logPaths = gameLift.appendLogPaths(ctx, logPaths)
 
 	// Set SDK tool name and version before calling ProcessReady
 	err := os.Setenv(constants.EnvironmentKeySDKToolName, internal.AppName())
@@ -137,6 +168,22 @@ func (gameLift *gamelift) Run(ctx context.Context) error {
 		return errors.Wrapf(err, "failed to call process ready")
 	}
 
+	if gameLift.credentials != nil {
+		if err := gameLift.credentials.Start(ctx); err != nil {
+			return errors.Wrap(err, "failed to start fleet role credentials endpoint")
+		}
+	}
+
+	if err := gameLift.players.Start(ctx); err != nil {
+		return errors.Wrap(err, "failed to start player tracking")
+	}
+
+	// the SDK only hands out the certificate once the process is ready, fetch it now so a
+	// missing certificate fails the launch before the first game session
+	if _, err := gameLift.computeCertificate(ctx); err != nil {
+		return err
+	}
+
 	select {
 	case <-ctx.Done():
 		return nil
@@ -164,16 +211,21 @@ func (gameLift *gamelift) SetOnHealthCheck(f func(ctx context.Context) events.Ga
 	gameLift.onHealthCheck = f
 }
 
-// Close performs a shutdown of the server process. The cleanup includes copying game
-// server logs to the designated log directory, notifying GameLift SDK that the process
-// is ending, destroying the SDK resources, and closing the initializer service.
+// Close performs a shutdown of the server process. The cleanup includes stopping the initializer
+// from reconnecting the SDK, copying game server logs to the designated log directory, notifying
+// GameLift SDK that the process is ending, destroying the SDK resources, and closing the initializer service.
 func (gameLift *gamelift) Close(ctx context.Context) error {
 	gameLift.logger.InfoContext(ctx, "cleaning up Amazon GameLift resources")
 
-	copyErr := copyGameServerLogs(gameLift.logger, gameLift.gameServerLogDir, gameLift.logDir)
+	// a connection lost from here on must not bring the SDK back while it is torn down
+	gameLift.init.Stop()
 
-	if copyErr != nil {
-		gameLift.ec <- copyErr
+	gameLift.collectGameServerLogs(ctx)
+
+	status := gameLift.players.Status()
+	gameLift.logger.InfoContext(ctx, "players of the game session", "current", status.Current, "peak", status.Peak, "maximum", status.Maximum)
+	if e := gameLift.players.Close(ctx); e != nil {
+		gameLift.logger.ErrorContext(ctx, "failed to close player tracking", "err", e)
 	}
 
 	var err error
@@ -192,10 +244,31 @@ func (gameLift *gamelift) Close(ctx context.Context) error {
 		}
 	}
 
+	if gameLift.credentials != nil {
+		if e := gameLift.credentials.Close(ctx); e != nil {
+			gameLift.logger.ErrorContext(ctx, "failed to close fleet role credentials endpoint", "err", e)
+		}
+	}
+
+	if e := gameLift.init.Close(ctx); e != nil {
+		gameLift.logger.ErrorContext(ctx, "failed to close initialiser", "err", e)
+		if err != nil {
+			err = errors.Wrapf(err, "%s", e.Error())
+		} else {
+			err = e
+		}
+	}
+
 	return err
 }
 
 func (gameLift *gamelift) glHealthcheck() bool {
+	healthy := gameLift.healthcheck()
+	gameLift.unhealthy.Store(!healthy)
+	return healthy
+}
+
+func (gameLift *gamelift) healthcheck() bool {
 	res := gameLift.onHealthCheck(gameLift.ctx)
 	switch res {
 	case events.GameStatusWaiting:
@@ -214,9 +287,25 @@ func (gameLift *gamelift) glHealthcheck() bool {
 }
 
 func (gameLift *gamelift) glOnProcessTerminate() {
-	err := gameLift.onHostingTerminate(gameLift.ctx, &events.HostingTerminate{
-		Reason: events.HostingTerminateReasonHostingShutdown,
-	})
+	reason := events.HostingTerminateReasonHostingShutdown
+	if gameLift.unhealthy.Load() {
+		// Amazon GameLift terminates processes that keep failing health checks
+		reason = events.HostingTerminateReasonHealthCheckFailed
+	}
+
+	terminate := &events.HostingTerminate{
+		Reason: reason,
+	}
+
+	// spot interruptions and scale-in give the process until the termination time to wrap up
+	if terminationTime, err := gameLift.sdk.GetTerminationTime(gameLift.ctx); err != nil || terminationTime <= 0 {
+		gameLift.logger.DebugContext(gameLift.ctx, "no termination time available", "err", err)
+	} else {
+		terminate.TerminationTime = time.Unix(terminationTime, 0)
+		gameLift.logger.InfoContext(gameLift.ctx, "process termination scheduled", "terminationTime", terminate.TerminationTime)
+	}
+
+	err := gameLift.onHostingTerminate(gameLift.ctx, terminate)
 
 	if err != nil {
 		gameLift.ec <- err
@@ -227,12 +316,13 @@ func (gameLift *gamelift) glOnUpdateGameSession(gs model.UpdateGameSession) {
 	gameLift.logger.DebugContext(gameLift.ctx, "update game session called, but no action is taken by the Game Server Wrapper")
 }
 
-func (gameLift *gamelift) glOnError(err error) {
-	gameLift.logger.Error("Amazon GameLift error", "err", err)
-	err = gameLift.Close(gameLift.ctx)
-	if err != nil {
-		gameLift.logger.Error("Error closing game after Amazon GameLift error.", "err", err)
-		return
+// glOnError is told about SDK calls that failed for good. The failed call also returns its error, which ends
+// the hosting, so the span of the run is only marked here.
+func (gameLift *gamelift) glOnError(call string, err error) {
+	gameLift.logger.Error("Amazon GameLift error", "call", call, "err", err)
+	if gameLift.ctx != nil {
+		span := trace.SpanFromContext(gameLift.ctx)
+		span.RecordError(err, trace.WithAttributes(attribute.String("call", call)))
 	}
 }
 
@@ -244,6 +334,12 @@ func (gameLift *gamelift) glOnStartGameSession(gs model.GameSession) {
 
 	gameLift.logger.DebugContext(gameLift.ctx, "start game sessions called", "gs", gs)
 
+	if !gameLift.startSession() {
+		// the wrapper is exiting, ProcessEnding hands the game session back to Amazon GameLift
+		gameLift.logger.WarnContext(gameLift.ctx, "the wrapper is draining, the game session is not started", "gameSessionId", gs.GameSessionID)
+		return
+	}
+
 	gameLift.logger.DebugContext(gameLift.ctx, "manager onHostingStart", "event", gs)
 	cliArgs := make([]config.CliArg, 0)
 
@@ -263,17 +359,50 @@ func (gameLift *gamelift) glOnStartGameSession(gs model.GameSession) {
 		GameSessionId:             gs.GameSessionID,
 		GameSessionName:           gs.Name,
 		IpAddress:                 gs.IPAddress,
-		LogDirectory:              gameLift.logDir,
+		LogDirectory:              hosting.SessionLogDirectory(gameLift.logDir, gs.GameSessionID),
 		MatchmakerData:            gs.MatchmakerData,
 		MaximumPlayerSessionCount: gs.MaximumPlayerSessionCount,
 		Provider:                  config.ProviderGameLift,
 	}
 
+	if gameLift.credentials != nil {
+		hse.EnvVars = gameLift.credentials.Env()
+	}
+
+	if env := gameLift.players.Env(); len(env) != 0 {
+		if hse.EnvVars == nil {
+			hse.EnvVars = make(map[string]string, len(env))
+		}
+		for key, value := range env {
+			hse.EnvVars[key] = value
+		}
+	}
+
+	certificate, err := gameLift.computeCertificate(gameLift.ctx)
+	if err != nil {
+		gameLift.ec <- err
+		return
+	}
+	if len(certificate.CertificatePath) != 0 {
+		hse.CertificatePath = certificate.CertificatePath
+		hse.ComputeHostname = certificate.ComputeName
+		if hse.EnvVars == nil {
+			hse.EnvVars = make(map[string]string, 2)
+		}
+		hse.EnvVars[constants.EnvironmentKeyComputeCertificatePath] = certificate.CertificatePath
+		hse.EnvVars[constants.EnvironmentKeyComputeHostname] = certificate.ComputeName
+	}
+
 	if strings.HasPrefix(hse.FleetId, "containerfleet-") {
 		hse.ContainerPort = gameLift.cfg.GamePort
 	}
 
-	if err := gameLift.sdk.ActivateGameSession(gameLift.ctx); err != nil {
+	gameLift.players.Reset(gameLift.ctx, gs.MaximumPlayerSessionCount)
+
+	gameLift.activated.Store(false)
+	if gameLift.cfg.WaitForReady {
+		gameLift.logger.DebugContext(gameLift.ctx, "waiting for the game server to be ready before activating the game session")
+	} else if err := gameLift.ActivateGameSession(gameLift.ctx); err != nil {
 		gameLift.ec <- err
 		return
 	}
@@ -285,13 +414,134 @@ func (gameLift *gamelift) glOnStartGameSession(gs model.GameSession) {
 
 }
 
+// computeCertificate fetches the compute TLS certificate once, according to the configured policy, asking
+// again after a failed call. An empty result means the game server is launched without a certificate.
+func (gameLift *gamelift) computeCertificate(ctx context.Context) (result.GetComputeCertificateResult, error) {
+	policy := gameLift.cfg.ComputeCertificate
+	if len(policy) == 0 || policy == config.ComputeCertificateDisabled {
+		return result.GetComputeCertificateResult{}, nil
+	}
+
+	gameLift.certificateMutex.Lock()
+	defer gameLift.certificateMutex.Unlock()
+
+	err := gameLift.certificateErr
+	if !gameLift.certificateFetched {
+		var certificate result.GetComputeCertificateResult
+		certificate, err = gameLift.sdk.GetComputeCertificate(ctx)
+		// the certificate, or the fleet having none, holds for the life of the process, while a
+		// network or service error is not kept so the next game session asks again
+		if err == nil {
+			gameLift.certificateFetched = true
+			gameLift.certificate = certificate
+			if len(certificate.CertificatePath) == 0 {
+				err = errors.New("no certificate path returned, TLS certificate generation is not enabled for the fleet")
+				gameLift.certificateErr = err
+			}
+		}
+
+		if err == nil {
+			gameLift.logger.InfoContext(ctx, "compute TLS certificate available", "path", certificate.CertificatePath, "hostname", certificate.ComputeName)
+		} else if policy == config.ComputeCertificateRequired {
+			gameLift.logger.ErrorContext(ctx, "compute TLS certificate is required but not available", "err", err)
+		} else {
+			gameLift.logger.WarnContext(ctx, "compute TLS certificate not available, the game server is launched without it", "err", err)
+		}
+	}
+
+	if err != nil {
+		if policy == config.ComputeCertificateRequired {
+			return result.GetComputeCertificateResult{}, errors.Wrap(err, "failed to get compute TLS certificate")
+		}
+		return result.GetComputeCertificateResult{}, nil
+	}
+
+	return gameLift.certificate, nil
+}
+
+// ActivateGameSession activates the current game session, unless it is already active.
+//
+// Parameters:
+//   - ctx: Context for the operation
+//
+// Returns:
+//   - error: Any error returned by the Amazon GameLift SDK
+func (gameLift *gamelift) ActivateGameSession(ctx context.Context) error {
+	if !gameLift.activated.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	if err := gameLift.sdk.ActivateGameSession(ctx); err != nil {
+		gameLift.activated.Store(false)
+		return errors.Wrap(err, "failed to activate game session")
+	}
+
+	return nil
+}
+
+// AcceptPlayerSession validates a player connecting to the current game session, and tracks the player.
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - playerSessionId: Id of the player session
+//
+// Returns:
+//   - error: Any error returned by the Amazon GameLift SDK
+func (gameLift *gamelift) AcceptPlayerSession(ctx context.Context, playerSessionId string) error {
+	return gameLift.players.Join(ctx, playerSessionId)
+}
+
+// RemovePlayerSession reports that a player left the current game session.
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - playerSessionId: Id of the player session
+//
+// Returns:
+//   - error: Any error returned by the Amazon GameLift SDK
+func (gameLift *gamelift) RemovePlayerSession(ctx context.Context, playerSessionId string) error {
+	return gameLift.players.Leave(ctx, playerSessionId)
+}
+
+// Drain denies new player sessions of the current game session, and keeps the wrapper from starting another one.
+//
+// Parameters:
+//   - ctx: Context for the operation
+//
+// Returns:
+//   - bool: Whether a game session is running
+//   - error: Any error returned by the Amazon GameLift SDK
+func (gameLift *gamelift) Drain(ctx context.Context) (bool, error) {
+	gameLift.drainMutex.Lock()
+	gameLift.draining = true
+	sessionStarted := gameLift.sessionStarted
+	gameLift.drainMutex.Unlock()
+
+	if !sessionStarted {
+		return false, nil
+	}
+	return true, gameLift.players.Drain(ctx)
+}
+
+// startSession reports whether a game session can be started, and records it as started.
+func (gameLift *gamelift) startSession() bool {
+	gameLift.drainMutex.Lock()
+	defer gameLift.drainMutex.Unlock()
+
+	if gameLift.draining {
+		return false
+	}
+	gameLift.sessionStarted = true
+	return true
+}
+
 type InitialiserServiceFactory interface {
-	GetService(ctx context.Context, anywhere config.Anywhere, gameLiftSdk sdk.GameLiftSdk, logger *slog.Logger) (initialiser.Service, error)
+	GetService(ctx context.Context, anywhere config.Anywhere, gameLiftSdk sdk.GameLiftSdk, logger *slog.Logger, meter metric.Meter) (initialiser.Service, error)
 }
 
-func New(ctx context.Context, cfg *Config, logger *slog.Logger, spanner observability.Spanner, initialiserServiceFactory InitialiserServiceFactory, gameLiftSdk sdk.GameLiftSdk) (*gamelift, error) {
+func New(ctx context.Context, cfg *Config, logger *slog.Logger, spanner observability.Spanner, meter metric.Meter, initialiserServiceFactory InitialiserServiceFactory, gameLiftSdk sdk.GameLiftSdk) (*gamelift, error) {
 
-	init, err := initialiserServiceFactory.GetService(ctx, cfg.Anywhere, gameLiftSdk, logger)
+	init, err := initialiserServiceFactory.GetService(ctx, cfg.Anywhere, gameLiftSdk, logger, meter)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to create Amazon GameLift initialiser")
 	}
@@ -310,45 +560,76 @@ func New(ctx context.Context, cfg *Config, logger *slog.Logger, spanner observab
 		spanner: spanner,
 	}
 
+	g.logCollector, err = logcollector.New(cfg.LogCollection, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid game server log collection")
+	}
+
+	g.players, err = players.New(cfg.PlayerTracking, cfg.GamePort, gameLiftSdk, logger, meter)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid player tracking")
+	}
+
+	gameLiftSdk.SetOnError(g.glOnError)
+
+	if len(cfg.FleetRoleCredentials.RoleArn) != 0 {
+		g.credentials, err = credentials.New(cfg.FleetRoleCredentials, gameLiftSdk, logger)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create fleet role credentials endpoint")
+		}
+	}
+
 	return g, nil
 }
 
-func copyGameServerLogs(logger *slog.Logger, gameServerLogDir string, logDir string) error {
-	if gameServerLogDir == "" {
-		logger.Info("gameServerLogDir empty, not copying game server log")
-		return nil
-	}
-	// Check if source directory exists
-	if _, err := os.Stat(gameServerLogDir); os.IsNotExist(err) {
-		logger.Info("gameServerLogDir does not exist, skipping copy", "dir", gameServerLogDir)
-		return nil
+// appendLogPaths adds the configured log paths, expanding globs. Globs are expanded when the process becomes
+// ready, so they only match what exists by then, list a directory to upload files created later.
+func (gameLift *gamelift) appendLogPaths(ctx context.Context, logPaths []string) []string {
+	add := func(paths ...string) {
+		for _, p := range paths {
+			if !slices.Contains(logPaths, p) {
+				logPaths = append(logPaths, p)
+			}
+		}
 	}
 
-	var cpString string
-	var cmd *exec.Cmd
-	switch runtime.GOOS {
-	case "windows":
-		cpString = fmt.Sprintf("robocopy %v %v /E /NFL /NDL /NJH /NJS /NC /NS", gameServerLogDir, logDir)
-		cmd = exec.Command("cmd", "/c", cpString)
-	default:
-		cpString = fmt.Sprintf("cp -Rf %v %v", gameServerLogDir, logDir)
-		cmd = exec.Command("sh", "-c", cpString)
+	for _, logPath := range gameLift.cfg.LogPaths {
+		if !strings.ContainsAny(logPath, "*?[") {
+			add(logPath)
+			continue
+		}
+
+		matches, err := filepath.Glob(logPath)
+		if err != nil {
+			gameLift.logger.WarnContext(ctx, "invalid log path glob", "glob", logPath, "err", err)
+			continue
+		}
+		if len(matches) == 0 {
+			gameLift.logger.WarnContext(ctx, "log path glob matched nothing", "glob", logPath)
+		}
+		add(matches...)
 	}
 
-	var stderr bytes.Buffer
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = &stderr
+	gameLift.logger.DebugContext(ctx, "configured log paths", "paths", logPaths)
+	return logPaths
+}
 
-	err := cmd.Run()
-	if err == nil {
-		return nil
+// collectGameServerLogs copies the game server logs into the run log directory, so they are uploaded with it.
+// Failures are logged and recorded on the span, they never hold up the shutdown.
+func (gameLift *gamelift) collectGameServerLogs(ctx context.Context) {
+	if len(gameLift.gameServerLogDir) == 0 {
+		gameLift.logger.InfoContext(ctx, "game server log directory not set, not collecting game server logs")
+		return
 	}
-	stderrStr := strings.TrimSpace(stderr.String())
-	// If on Windows, allow robocopy warnings (exit codes < 8)
-	if runtime.GOOS == "windows" {
-		if exitError, ok := err.(*exec.ExitError); ok && exitError.ExitCode() < 8 {
-			return nil
-		}
+
+	ctx, span, _ := gameLift.spanner.NewSpan(ctx, "collect game server logs", map[string]string{
+		"dir": gameLift.gameServerLogDir,
+	})
+	defer span.End()
+
+	if _, err := gameLift.logCollector.Collect(ctx, gameLift.gameServerLogDir, gameLift.logDir); err != nil {
+		gameLift.logger.ErrorContext(ctx, "failed to collect game server logs", "dir", gameLift.gameServerLogDir, "err", err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 	}
-	return fmt.Errorf("error copying source '%v' into destination '%v', error: %v, stderr: %v", gameServerLogDir, logDir, err, stderrStr)
 }
@@ -0,0 +1,162 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package gamelift
+
+import (
+	"bytes"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/internal/mocks"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/internal/testing/sdkserver"
+	config2 "github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/constants"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting/gamelift/initialiser"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting/gamelift/sdk"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/types/events"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/message"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/metric/noop"
+	"golang.org/x/net/context"
+)
+
+// TestGamelift_Integration_Anywhere drives the wrapper through the real server SDK against a local
+// stand-in for the service SDK websocket endpoint. The SDK keeps global state, so the whole
+// lifecycle is exercised in a single test.
+func TestGamelift_Integration_Anywhere(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping server SDK integration test in short mode")
+	}
+
+	//arrange
+	t.Setenv("HEALTHCHECK_INTERVAL", "200ms")
+	t.Setenv("HEALTHCHECK_MAX_JITTER", "10ms")
+	t.Setenv("HEALTHCHECK_TIMEOUT", "100ms")
+
+	sdkServer := sdkserver.New()
+	defer sdkServer.Close()
+
+	logDir := t.TempDir()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*20)
+	defer cancel()
+	ctx = context.WithValue(ctx, constants.ContextKeyRunLogDir, logDir)
+
+	logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	}))
+
+	fleetId := "fleet-00000000-0000-0000-0000-000000000000"
+	cfg := &Config{
+		GamePort: 37016,
+		Anywhere: config2.Anywhere{
+			Host: config2.AnywhereHostConfig{
+				HostName:           "integration-compute",
+				ServiceSdkEndpoint: sdkServer.URL(),
+				AuthToken:          "integration-token",
+				LocationArn:        "arn:aws:gamelift:us-west-2:123456789012:location/custom-integration",
+				FleetArn:           "arn:aws:gamelift:us-west-2:123456789012:fleet/" + fleetId,
+			},
+		},
+		LogDirectory: logDir,
+	}
+
+	gl, err := New(ctx, cfg, logger, &mocks.SpannerMock{}, noop.NewMeterProvider().Meter("test"), &initialiser.InitialiserServiceFactory{}, sdk.NewSdk(ctx, logger, &mocks.SpannerMock{}, config2.SdkRetry{}))
+	assert.Nil(t, err)
+
+	hostingStarts := make(chan *events.HostingStart, 1)
+	hostingTerminates := make(chan *events.HostingTerminate, 1)
+	gl.SetOnHealthCheck(func(ctx context.Context) events.GameStatus {
+		return events.GameStatusRunning
+	})
+	gl.SetOnHostingStart(func(ctx context.Context, h *events.HostingStart, end <-chan error) error {
+		hostingStarts <- h
+		return nil
+	})
+	gl.SetOnHostingTerminate(func(ctx context.Context, h *events.HostingTerminate) error {
+		hostingTerminates <- h
+		return nil
+	})
+
+	//act - connect and report ready
+	_, err = gl.Init(ctx, &hosting.InitArgs{RunId: uuid.New()})
+	assert.Nil(t, err)
+
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- gl.Run(ctx)
+	}()
+
+	//assert
+	assert.Nil(t, sdkServer.WaitFor(time.Second*5, func() bool {
+		return len(sdkServer.ProcessReadyRequests()) == 1
+	}))
+	connections := sdkServer.Connections()
+	assert.Len(t, connections, 1)
+	assert.Equal(t, "integration-compute", connections[0].ComputeId)
+	assert.Equal(t, fleetId, connections[0].FleetId)
+	assert.Equal(t, "integration-token", connections[0].AuthToken)
+	assert.Equal(t, 37016, sdkServer.ProcessReadyRequests()[0].Port)
+	assert.Equal(t, []string{logDir}, sdkServer.ProcessReadyRequests()[0].LogPaths)
+
+	assert.Nil(t, sdkServer.WaitFor(time.Second*5, func() bool {
+		return len(sdkServer.HealthReports()) >= 2
+	}))
+	for _, healthy := range sdkServer.HealthReports() {
+		assert.True(t, healthy)
+	}
+
+	//act - start a game session
+	gameSessionId := sdkserver.NewGameSessionId(fleetId)
+	assert.Nil(t, sdkServer.CreateGameSession(sdkserver.GameSession{
+		GameSessionId:             gameSessionId,
+		Name:                      "integration-session",
+		IpAddress:                 "127.0.0.1",
+		Port:                      37016,
+		GameProperties:            map[string]string{"mode": "ranked"},
+		MaximumPlayerSessionCount: 4,
+	}))
+
+	//assert
+	select {
+	case h := <-hostingStarts:
+		assert.Equal(t, gameSessionId, h.GameSessionId)
+		assert.Equal(t, fleetId, h.FleetId)
+		assert.Equal(t, "integration-session", h.GameSessionName)
+		assert.Equal(t, `{"mode":"ranked"}`, h.GameProperties)
+		assert.Equal(t, 4, h.MaximumPlayerSessionCount)
+		assert.Equal(t, filepath.Join(logDir, "session_"+filepath.Base(gameSessionId)), h.LogDirectory)
+	case <-time.After(time.Second * 5):
+		assert.Fail(t, "hosting start not received")
+	}
+	assert.Nil(t, sdkServer.WaitFor(time.Second*5, func() bool {
+		return len(sdkServer.ActivatedGameSessions()) == 1
+	}))
+	assert.Equal(t, []string{gameSessionId}, sdkServer.ActivatedGameSessions())
+
+	//act - terminate the process
+	assert.Nil(t, sdkServer.TerminateProcess(time.Now().Add(time.Minute)))
+
+	//assert
+	select {
+	case h := <-hostingTerminates:
+		assert.Equal(t, events.HostingTerminateReasonHostingShutdown, h.Reason)
+	case <-time.After(time.Second * 5):
+		assert.Fail(t, "hosting terminate not received")
+	}
+
+	//act - shut down
+	err = gl.Close(ctx)
+	cancel()
+
+	//assert
+	assert.Nil(t, err)
+	assert.Nil(t, <-runErr)
+	assert.Len(t, sdkServer.Requests(message.TerminateServerProcess), 1)
+}
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"log/slog"
 	"os"
+	"path/filepath"
 	"runtime"
 	"testing"
 	"time"
@@ -20,11 +21,14 @@ import (
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/constants"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting/gamelift/initialiser"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/logcollector"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/observability"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/types/events"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/result"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/metric/noop"
 	"golang.org/x/net/context"
 )
 
@@ -56,7 +60,7 @@ func createGameLiftMockHelper(config *Config) GameLiftMockHelper {
 		GetServiceResponse: &initialiserServiceMock,
 		GetServiceError:    nil,
 	}
-	gamelift, _ := New(ctx, config, logger, &spannerMock, &initialiserServiceFactoryMock, &gameLiftSdkMock)
+	gamelift, _ := New(ctx, config, logger, &spannerMock, noop.NewMeterProvider().Meter("test"), &initialiserServiceFactoryMock, &gameLiftSdkMock)
 	return GameLiftMockHelper{
 		logger:                        logger,
 		logBuffer:                     &logBuffer,
@@ -146,6 +150,28 @@ func TestGamelift_Run_HappyPath(t *testing.T) {
 	assert.Equal(t, internal.SemVer(), os.Getenv(constants.EnvironmentKeySDKToolVersion))
 }
 
+func TestGamelift_AppendLogPaths_ExpandsGlobs(t *testing.T) {
+	//arrange
+	logDir := t.TempDir()
+	savedLogs := filepath.Join(t.TempDir(), "Saved", "Logs")
+	assert.NoError(t, os.MkdirAll(savedLogs, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(savedLogs, "a.log"), nil, 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(savedLogs, "b.log"), nil, 0644))
+	config := Config{
+		GamePort:     100,
+		LogDirectory: logDir,
+		LogPaths:     []string{savedLogs, filepath.Join(savedLogs, "*.log"), logDir, filepath.Join(logDir, "*.missing")},
+	}
+	gameLiftMockHelper := createGameLiftMockHelper(&config)
+
+	//act
+	logPaths := gameLiftMockHelper.gamelift.appendLogPaths(gameLiftMockHelper.ctx, []string{logDir})
+
+	//assert
+	assert.Equal(t, []string{logDir, savedLogs, filepath.Join(savedLogs, "a.log"), filepath.Join(savedLogs, "b.log")}, logPaths)
+	assert.Contains(t, gameLiftMockHelper.logBuffer.String(), "log path glob matched nothing")
+}
+
 func TestGamelift_Run_HappyPath_Call_HealthCheck(t *testing.T) {
 	//arrange
 
@@ -203,6 +229,65 @@ func TestGamelift_Run_HappyPath_Call_ProcessTerminate(t *testing.T) {
 	assert.Equal(t, events.HostingTerminateReasonHostingShutdown, hostingTerminate.Reason)
 }
 
+func TestGamelift_Run_ProcessTerminate_TerminationTime(t *testing.T) {
+	//arrange
+	config := Config{
+		GamePort:               100,
+		Anywhere:               config2.Anywhere{},
+		LogDirectory:           os.TempDir(),
+		GameServerLogDirectory: os.TempDir(),
+	}
+	gameLiftMockHelper := createGameLiftMockHelper(&config)
+	gameLiftMockHelper.gameLiftSdk.TerminationTime = 1767225600
+	var hostingTerminate *events.HostingTerminate
+	gameLiftMockHelper.gamelift.SetOnHostingTerminate(func(ctx context.Context, h *events.HostingTerminate) error {
+		hostingTerminate = h
+		return nil
+	})
+
+	//act
+	err := gameLiftMockHelper.gamelift.Run(gameLiftMockHelper.ctx)
+	assert.Nil(t, err)
+
+	//invoke callback
+	gameLiftMockHelper.gameLiftSdk.ProcessParameters.OnProcessTerminate()
+
+	//assert
+	assert.Equal(t, events.HostingTerminateReasonHostingShutdown, hostingTerminate.Reason)
+	assert.True(t, time.Unix(1767225600, 0).Equal(hostingTerminate.TerminationTime))
+}
+
+func TestGamelift_Run_ProcessTerminate_AfterFailedHealthCheck(t *testing.T) {
+	//arrange
+
+	config := Config{
+		GamePort:               100,
+		Anywhere:               config2.Anywhere{},
+		LogDirectory:           os.TempDir(),
+		GameServerLogDirectory: os.TempDir(),
+	}
+	gameLiftMockHelper := createGameLiftMockHelper(&config)
+	gameLiftMockHelper.gamelift.SetOnHealthCheck(func(ctx context.Context) events.GameStatus {
+		return events.GameStatusErrored
+	})
+	var hostingTerminate *events.HostingTerminate
+	gameLiftMockHelper.gamelift.SetOnHostingTerminate(func(ctx context.Context, h *events.HostingTerminate) error {
+		hostingTerminate = h
+		return nil
+	})
+
+	//act
+	err := gameLiftMockHelper.gamelift.Run(gameLiftMockHelper.ctx)
+	assert.Nil(t, err)
+
+	//invoke callbacks
+	assert.False(t, gameLiftMockHelper.gameLiftSdk.ProcessParameters.OnHealthCheck())
+	gameLiftMockHelper.gameLiftSdk.ProcessParameters.OnProcessTerminate()
+
+	//assert
+	assert.Equal(t, events.HostingTerminateReasonHealthCheckFailed, hostingTerminate.Reason)
+}
+
 func TestGamelift_Run_HappyPath_Call_StartGameSession(t *testing.T) {
 	//arrange
 
@@ -345,3 +430,440 @@ func TestGamelift_OnStartGameSession_ContainerPort(t *testing.T) {
 		})
 	}
 }
+
+func TestGamelift_Close_ClosesInitialiser(t *testing.T) {
+	//arrange
+	config := Config{
+		GamePort:     100,
+		Anywhere:     config2.Anywhere{},
+		LogDirectory: os.TempDir(),
+	}
+	gameLiftMockHelper := createGameLiftMockHelper(&config)
+
+	//act
+	err := gameLiftMockHelper.gamelift.Close(gameLiftMockHelper.ctx)
+
+	//assert
+	assert.Nil(t, err)
+	assert.True(t, gameLiftMockHelper.gameLiftSdk.ProcessEndingCalled)
+	assert.True(t, gameLiftMockHelper.gameLiftSdk.DestroyCalled)
+	assert.True(t, gameLiftMockHelper.initialiserService.CloseCalled)
+}
+
+func TestGamelift_Close_StopsInitialiserFirst(t *testing.T) {
+	//arrange
+	config := Config{
+		GamePort:     100,
+		Anywhere:     config2.Anywhere{},
+		LogDirectory: os.TempDir(),
+	}
+	gameLiftMockHelper := createGameLiftMockHelper(&config)
+	processEndingBeforeStop := true
+	gameLiftMockHelper.initialiserService.OnStop = func() {
+		processEndingBeforeStop = gameLiftMockHelper.gameLiftSdk.ProcessEndingCalled || gameLiftMockHelper.gameLiftSdk.DestroyCalled
+	}
+
+	//act
+	err := gameLiftMockHelper.gamelift.Close(gameLiftMockHelper.ctx)
+
+	//assert
+	assert.Nil(t, err)
+	assert.True(t, gameLiftMockHelper.initialiserService.StopCalled)
+	assert.False(t, processEndingBeforeStop)
+}
+
+func TestGamelift_Close_InitialiserFailed(t *testing.T) {
+	//arrange
+	config := Config{
+		GamePort:     100,
+		Anywhere:     config2.Anywhere{},
+		LogDirectory: os.TempDir(),
+	}
+	gameLiftMockHelper := createGameLiftMockHelper(&config)
+	gameLiftMockHelper.initialiserService.CloseError = errors.New("Unit Test")
+
+	//act
+	err := gameLiftMockHelper.gamelift.Close(gameLiftMockHelper.ctx)
+
+	//assert
+	assert.ErrorContains(t, err, "Unit Test")
+	assert.True(t, gameLiftMockHelper.gameLiftSdk.DestroyCalled)
+	assert.Contains(t, gameLiftMockHelper.logBuffer.String(), "failed to close initialiser")
+}
+
+func TestGamelift_Close_CollectsGameServerLogs(t *testing.T) {
+	//arrange
+	gameServerLogDir := filepath.Join(t.TempDir(), "game")
+	assert.NoError(t, os.MkdirAll(gameServerLogDir, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(gameServerLogDir, "server.log"), []byte("0123456789"), 0644))
+	config := Config{
+		GamePort:               100,
+		Anywhere:               config2.Anywhere{},
+		LogDirectory:           t.TempDir(),
+		GameServerLogDirectory: gameServerLogDir,
+		LogCollection:          config2.LogCollection{MaxFileSize: 4},
+	}
+	gameLiftMockHelper := createGameLiftMockHelper(&config)
+	_, _ = gameLiftMockHelper.gamelift.Init(gameLiftMockHelper.ctx, &hosting.InitArgs{})
+
+	//act
+	err := gameLiftMockHelper.gamelift.Close(gameLiftMockHelper.ctx)
+
+	//assert
+	assert.Nil(t, err)
+	b, err := os.ReadFile(filepath.Join(config.LogDirectory, "game", "server.log"))
+	assert.NoError(t, err)
+	assert.Equal(t, "6789", string(b))
+	assert.FileExists(t, filepath.Join(config.LogDirectory, logcollector.ManifestFileName))
+}
+
+func TestGamelift_Close_LogCollectionFailureDoesNotBlock(t *testing.T) {
+	//arrange
+	gameServerLogFile := filepath.Join(t.TempDir(), "game.log")
+	assert.NoError(t, os.WriteFile(gameServerLogFile, []byte("not a directory"), 0644))
+	config := Config{
+		GamePort:               100,
+		Anywhere:               config2.Anywhere{},
+		LogDirectory:           t.TempDir(),
+		GameServerLogDirectory: gameServerLogFile,
+	}
+	gameLiftMockHelper := createGameLiftMockHelper(&config)
+	_, _ = gameLiftMockHelper.gamelift.Init(gameLiftMockHelper.ctx, &hosting.InitArgs{})
+
+	//act
+	err := gameLiftMockHelper.gamelift.Close(gameLiftMockHelper.ctx)
+
+	//assert
+	assert.Nil(t, err)
+	assert.True(t, gameLiftMockHelper.gameLiftSdk.DestroyCalled)
+	assert.Contains(t, gameLiftMockHelper.logBuffer.String(), "failed to collect game server logs")
+}
+
+func TestGamelift_OnStartGameSession_WaitForReady(t *testing.T) {
+	//arrange
+	config := Config{
+		GamePort:     100,
+		Anywhere:     config2.Anywhere{},
+		LogDirectory: os.TempDir(),
+		WaitForReady: true,
+	}
+	gameLiftMockHelper := createGameLiftMockHelper(&config)
+	gameLiftMockHelper.gamelift.ctx = gameLiftMockHelper.ctx
+	gameLiftMockHelper.gamelift.SetOnHostingStart(func(ctx context.Context, h *events.HostingStart, end <-chan error) error {
+		return nil
+	})
+
+	//act
+	gameLiftMockHelper.gamelift.glOnStartGameSession(model.GameSession{GameSessionID: "gameSessionId"})
+	activatedOnStart := gameLiftMockHelper.gameLiftSdk.ActivateGameSessionCalled
+	err := gameLiftMockHelper.gamelift.ActivateGameSession(gameLiftMockHelper.ctx)
+
+	//assert
+	assert.False(t, activatedOnStart)
+	assert.Nil(t, err)
+	assert.True(t, gameLiftMockHelper.gameLiftSdk.ActivateGameSessionCalled)
+}
+
+func TestGamelift_ActivateGameSession_OnlyOnce(t *testing.T) {
+	//arrange
+	config := Config{
+		GamePort:     100,
+		Anywhere:     config2.Anywhere{},
+		LogDirectory: os.TempDir(),
+	}
+	gameLiftMockHelper := createGameLiftMockHelper(&config)
+	assert.Nil(t, gameLiftMockHelper.gamelift.ActivateGameSession(gameLiftMockHelper.ctx))
+	gameLiftMockHelper.gameLiftSdk.ActivateGameSessionCalled = false
+
+	//act
+	err := gameLiftMockHelper.gamelift.ActivateGameSession(gameLiftMockHelper.ctx)
+
+	//assert
+	assert.Nil(t, err)
+	assert.False(t, gameLiftMockHelper.gameLiftSdk.ActivateGameSessionCalled)
+}
+
+func TestGamelift_PlayerSessions(t *testing.T) {
+	//arrange
+	config := Config{
+		GamePort:     100,
+		Anywhere:     config2.Anywhere{},
+		LogDirectory: os.TempDir(),
+	}
+	gameLiftMockHelper := createGameLiftMockHelper(&config)
+	gameLiftMockHelper.gameLiftSdk.RemovePlayerSessionError = errors.New("Unit Test")
+
+	//act
+	acceptErr := gameLiftMockHelper.gamelift.AcceptPlayerSession(gameLiftMockHelper.ctx, "psess-1")
+	removeErr := gameLiftMockHelper.gamelift.RemovePlayerSession(gameLiftMockHelper.ctx, "psess-1")
+
+	//assert
+	assert.Nil(t, acceptErr)
+	assert.ErrorContains(t, removeErr, "failed to remove player session 'psess-1'")
+	assert.Equal(t, []string{"psess-1"}, gameLiftMockHelper.gameLiftSdk.AcceptedPlayerSessions)
+	assert.Equal(t, []string{"psess-1"}, gameLiftMockHelper.gameLiftSdk.RemovedPlayerSessions)
+}
+
+func TestGamelift_PlayerSessions_EnforceCapacity(t *testing.T) {
+	//arrange
+	config := Config{
+		GamePort:       100,
+		Anywhere:       config2.Anywhere{},
+		LogDirectory:   os.TempDir(),
+		PlayerTracking: config2.PlayerTracking{EnforceCapacity: true},
+	}
+	gameLiftMockHelper := createGameLiftMockHelper(&config)
+	gameLiftMockHelper.gamelift.ctx = gameLiftMockHelper.ctx
+	gameLiftMockHelper.gamelift.SetOnHostingStart(func(ctx context.Context, h *events.HostingStart, end <-chan error) error {
+		return nil
+	})
+
+	//act
+	gameLiftMockHelper.gamelift.glOnStartGameSession(model.GameSession{GameSessionID: "gameSessionId", MaximumPlayerSessionCount: 1})
+	acceptErr := gameLiftMockHelper.gamelift.AcceptPlayerSession(gameLiftMockHelper.ctx, "psess-1")
+
+	//assert
+	assert.Nil(t, acceptErr)
+	assert.Equal(t, []model.PlayerSessionCreationPolicy{model.DenyAll}, gameLiftMockHelper.gameLiftSdk.PlayerSessionPolicies)
+	assert.Equal(t, 1, gameLiftMockHelper.gamelift.players.Status().Peak)
+}
+
+func TestGamelift_Drain_DeniesPlayerSessions(t *testing.T) {
+	//arrange
+	config := Config{
+		GamePort:     100,
+		Anywhere:     config2.Anywhere{},
+		LogDirectory: os.TempDir(),
+	}
+	gameLiftMockHelper := createGameLiftMockHelper(&config)
+	gameLiftMockHelper.gamelift.ctx = gameLiftMockHelper.ctx
+	gameLiftMockHelper.gamelift.SetOnHostingStart(func(ctx context.Context, h *events.HostingStart, end <-chan error) error {
+		return nil
+	})
+	gameLiftMockHelper.gamelift.glOnStartGameSession(model.GameSession{GameSessionID: "gameSessionId", MaximumPlayerSessionCount: 4})
+
+	//act
+	sessionRunning, err := gameLiftMockHelper.gamelift.Drain(gameLiftMockHelper.ctx)
+
+	//assert
+	assert.Nil(t, err)
+	assert.True(t, sessionRunning)
+	assert.Equal(t, []model.PlayerSessionCreationPolicy{model.DenyAll}, gameLiftMockHelper.gameLiftSdk.PlayerSessionPolicies)
+}
+
+func TestGamelift_Drain_NoGameSessionStarted(t *testing.T) {
+	//arrange
+	config := Config{
+		GamePort:     100,
+		Anywhere:     config2.Anywhere{},
+		LogDirectory: os.TempDir(),
+	}
+	gameLiftMockHelper := createGameLiftMockHelper(&config)
+	gameLiftMockHelper.gamelift.ctx = gameLiftMockHelper.ctx
+	started := false
+	gameLiftMockHelper.gamelift.SetOnHostingStart(func(ctx context.Context, h *events.HostingStart, end <-chan error) error {
+		started = true
+		return nil
+	})
+
+	//act
+	sessionRunning, err := gameLiftMockHelper.gamelift.Drain(gameLiftMockHelper.ctx)
+	gameLiftMockHelper.gamelift.glOnStartGameSession(model.GameSession{GameSessionID: "gameSessionId"})
+
+	//assert
+	assert.Nil(t, err)
+	assert.False(t, sessionRunning)
+	assert.False(t, started)
+	assert.Empty(t, gameLiftMockHelper.gameLiftSdk.PlayerSessionPolicies)
+}
+
+func TestGamelift_OnStartGameSession_FleetRoleCredentials(t *testing.T) {
+	//arrange
+	config := Config{
+		GamePort:     100,
+		Anywhere:     config2.Anywhere{},
+		LogDirectory: os.TempDir(),
+		FleetRoleCredentials: config2.FleetRoleCredentials{
+			RoleArn: "arn:aws:iam::123456789012:role/game-role",
+		},
+	}
+	gameLiftMockHelper := createGameLiftMockHelper(&config)
+	gameLiftMockHelper.gamelift.ctx = gameLiftMockHelper.ctx
+	var hostingStart *events.HostingStart
+	gameLiftMockHelper.gamelift.SetOnHostingStart(func(ctx context.Context, h *events.HostingStart, end <-chan error) error {
+		hostingStart = h
+		return nil
+	})
+	assert.NoError(t, gameLiftMockHelper.gamelift.credentials.Start(gameLiftMockHelper.ctx))
+
+	//act
+	gameLiftMockHelper.gamelift.glOnStartGameSession(model.GameSession{GameSessionID: "gameSessionId"})
+
+	//assert
+	assert.NotNil(t, hostingStart)
+	assert.Contains(t, hostingStart.EnvVars["AWS_CONTAINER_CREDENTIALS_FULL_URI"], "http://127.0.0.1:")
+	assert.NotEmpty(t, hostingStart.EnvVars["AWS_CONTAINER_AUTHORIZATION_TOKEN"])
+	assert.Nil(t, gameLiftMockHelper.gamelift.Close(gameLiftMockHelper.ctx))
+}
+
+func TestGamelift_OnStartGameSession_ComputeCertificate(t *testing.T) {
+	//arrange
+	config := Config{
+		GamePort:           100,
+		Anywhere:           config2.Anywhere{},
+		LogDirectory:       os.TempDir(),
+		ComputeCertificate: config2.ComputeCertificateRequired,
+	}
+	gameLiftMockHelper := createGameLiftMockHelper(&config)
+	gameLiftMockHelper.gamelift.ctx = gameLiftMockHelper.ctx
+	gameLiftMockHelper.gameLiftSdk.ComputeCertificate = result.GetComputeCertificateResult{
+		CertificatePath: "/local/gamelift/certificate.pem",
+		ComputeName:     "compute.example.com",
+	}
+	var hostingStart *events.HostingStart
+	gameLiftMockHelper.gamelift.SetOnHostingStart(func(ctx context.Context, h *events.HostingStart, end <-chan error) error {
+		hostingStart = h
+		return nil
+	})
+
+	//act
+	gameLiftMockHelper.gamelift.glOnStartGameSession(model.GameSession{GameSessionID: "gameSessionId"})
+	gameLiftMockHelper.gamelift.glOnStartGameSession(model.GameSession{GameSessionID: "gameSessionId2"})
+
+	//assert
+	assert.NotNil(t, hostingStart)
+	assert.Equal(t, "/local/gamelift/certificate.pem", hostingStart.CertificatePath)
+	assert.Equal(t, "compute.example.com", hostingStart.ComputeHostname)
+	assert.Equal(t, "/local/gamelift/certificate.pem", hostingStart.EnvVars[constants.EnvironmentKeyComputeCertificatePath])
+	assert.Equal(t, "compute.example.com", hostingStart.EnvVars[constants.EnvironmentKeyComputeHostname])
+	assert.Equal(t, 1, gameLiftMockHelper.gameLiftSdk.ComputeCertificateCalls)
+}
+
+func TestGamelift_OnStartGameSession_ComputeCertificateOptional_Missing(t *testing.T) {
+	//arrange
+	config := Config{
+		GamePort:           100,
+		Anywhere:           config2.Anywhere{},
+		LogDirectory:       os.TempDir(),
+		ComputeCertificate: config2.ComputeCertificateOptional,
+	}
+	gameLiftMockHelper := createGameLiftMockHelper(&config)
+	gameLiftMockHelper.gamelift.ctx = gameLiftMockHelper.ctx
+	var hostingStart *events.HostingStart
+	gameLiftMockHelper.gamelift.SetOnHostingStart(func(ctx context.Context, h *events.HostingStart, end <-chan error) error {
+		hostingStart = h
+		return nil
+	})
+
+	//act
+	gameLiftMockHelper.gamelift.glOnStartGameSession(model.GameSession{GameSessionID: "gameSessionId"})
+
+	//assert
+	assert.NotNil(t, hostingStart)
+	assert.Empty(t, hostingStart.CertificatePath)
+	assert.NotContains(t, hostingStart.EnvVars, constants.EnvironmentKeyComputeCertificatePath)
+	assert.Contains(t, gameLiftMockHelper.logBuffer.String(), "compute TLS certificate not available")
+}
+
+func TestGamelift_Run_ComputeCertificateRequired_Missing(t *testing.T) {
+	//arrange
+	config := Config{
+		GamePort:           100,
+		Anywhere:           config2.Anywhere{},
+		LogDirectory:       os.TempDir(),
+		ComputeCertificate: config2.ComputeCertificateRequired,
+	}
+	gameLiftMockHelper := createGameLiftMockHelper(&config)
+	gameLiftMockHelper.gameLiftSdk.ComputeCertificateError = errors.New("Unit Test")
+
+	//act
+	err := gameLiftMockHelper.gamelift.Run(gameLiftMockHelper.ctx)
+
+	//assert
+	assert.ErrorContains(t, err, "failed to get compute TLS certificate")
+	assert.True(t, gameLiftMockHelper.gameLiftSdk.ProcessReadyCalled)
+}
+
+func TestGamelift_ComputeCertificate_Disabled(t *testing.T) {
+	//arrange
+	config := Config{
+		GamePort:     100,
+		Anywhere:     config2.Anywhere{},
+		LogDirectory: os.TempDir(),
+	}
+	gameLiftMockHelper := createGameLiftMockHelper(&config)
+	gameLiftMockHelper.gamelift.ctx = gameLiftMockHelper.ctx
+
+	//act
+	certificate, err := gameLiftMockHelper.gamelift.computeCertificate(gameLiftMockHelper.ctx)
+
+	//assert
+	assert.Nil(t, err)
+	assert.Empty(t, certificate.CertificatePath)
+	assert.Equal(t, 0, gameLiftMockHelper.gameLiftSdk.ComputeCertificateCalls)
+}
+
+func TestGamelift_ComputeCertificate_RetriedAfterError(t *testing.T) {
+	//arrange
+	config := Config{
+		GamePort:           100,
+		Anywhere:           config2.Anywhere{},
+		LogDirectory:       os.TempDir(),
+		ComputeCertificate: config2.ComputeCertificateRequired,
+	}
+	gameLiftMockHelper := createGameLiftMockHelper(&config)
+	gameLiftMockHelper.gameLiftSdk.ComputeCertificateError = errors.New("Unit Test")
+	_, firstErr := gameLiftMockHelper.gamelift.computeCertificate(gameLiftMockHelper.ctx)
+	gameLiftMockHelper.gameLiftSdk.ComputeCertificateError = nil
+	gameLiftMockHelper.gameLiftSdk.ComputeCertificate = result.GetComputeCertificateResult{
+		CertificatePath: "/local/gamelift/certificate.pem",
+		ComputeName:     "compute.example.com",
+	}
+
+	//act
+	certificate, err := gameLiftMockHelper.gamelift.computeCertificate(gameLiftMockHelper.ctx)
+	_, _ = gameLiftMockHelper.gamelift.computeCertificate(gameLiftMockHelper.ctx)
+
+	//assert
+	assert.ErrorContains(t, firstErr, "Unit Test")
+	assert.Nil(t, err)
+	assert.Equal(t, "/local/gamelift/certificate.pem", certificate.CertificatePath)
+	assert.Equal(t, 2, gameLiftMockHelper.gameLiftSdk.ComputeCertificateCalls)
+}
+
+func TestGamelift_ComputeCertificate_NoPathKept(t *testing.T) {
+	//arrange
+	config := Config{
+		GamePort:           100,
+		Anywhere:           config2.Anywhere{},
+		LogDirectory:       os.TempDir(),
+		ComputeCertificate: config2.ComputeCertificateRequired,
+	}
+	gameLiftMockHelper := createGameLiftMockHelper(&config)
+
+	//act
+	_, firstErr := gameLiftMockHelper.gamelift.computeCertificate(gameLiftMockHelper.ctx)
+	_, err := gameLiftMockHelper.gamelift.computeCertificate(gameLiftMockHelper.ctx)
+
+	//assert
+	assert.ErrorContains(t, firstErr, "no certificate path returned")
+	assert.ErrorContains(t, err, "no certificate path returned")
+	assert.Equal(t, 1, gameLiftMockHelper.gameLiftSdk.ComputeCertificateCalls)
+}
+
+func TestGamelift_New_RegistersOnError(t *testing.T) {
+	//arrange
+	config := Config{
+		GamePort:     100,
+		Anywhere:     config2.Anywhere{},
+		LogDirectory: os.TempDir(),
+	}
+	gameLiftMockHelper := createGameLiftMockHelper(&config)
+	gameLiftMockHelper.gamelift.ctx = gameLiftMockHelper.ctx
+
+	//act
+	gameLiftMockHelper.gameLiftSdk.OnError("InitSDK", errors.New("Unit Test"))
+
+	//assert
+	assert.Contains(t, gameLiftMockHelper.logBuffer.String(), "call=InitSDK")
+}
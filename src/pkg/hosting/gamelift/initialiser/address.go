@@ -0,0 +1,167 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package initialiser
+
+import (
+	"net"
+	"net/netip"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting"
+	"github.com/pkg/errors"
+)
+
+// Well known public resolvers, only used to let the OS pick the source address of the default route.
+// Dialing UDP does not send any packet.
+const (
+	defaultRouteTargetIPv4 = "8.8.8.8:53"
+	defaultRouteTargetIPv6 = "[2001:4860:4860::8888]:53"
+)
+
+var (
+	interfaceAddrs   = listInterfaceAddrs
+	defaultRouteAddr = dialDefaultRouteAddr
+)
+
+// resolveAddresses finds the IPv4 and IPv6 addresses of the compute according to the discovery settings.
+//
+// Parameters:
+//   - host: Anywhere host configuration
+//
+// Returns:
+//   - hosting.IpAddresses: The addresses found, either may be nil
+//   - error: An error if no address could be found
+func resolveAddresses(host config.AnywhereHostConfig) (hosting.IpAddresses, error) {
+	discovery := host.AddressDiscovery
+
+	var prefix *netip.Prefix
+	if len(discovery.Cidr) != 0 {
+		p, err := netip.ParsePrefix(discovery.Cidr)
+		if err != nil {
+			return hosting.IpAddresses{}, errors.Wrapf(err, "invalid cidr '%s'", discovery.Cidr)
+		}
+		p = p.Masked()
+		prefix = &p
+	}
+
+	var candidates []netip.Addr
+	switch discovery.Source {
+	case "", config.AddressSourceStatic:
+		for _, s := range []string{host.IPv4Address, host.IPv6Address} {
+			if len(s) == 0 {
+				continue
+			}
+			addr, err := netip.ParseAddr(s)
+			if err != nil {
+				return hosting.IpAddresses{}, errors.Wrapf(err, "invalid ip address '%s'", s)
+			}
+			candidates = append(candidates, addr)
+		}
+	case config.AddressSourceInterface:
+		addrs, err := interfaceAddrs(discovery.Interface)
+		if err != nil {
+			return hosting.IpAddresses{}, err
+		}
+		candidates = addrs
+	case config.AddressSourceDefaultRoute:
+		for _, target := range []string{defaultRouteTargetIPv4, defaultRouteTargetIPv6} {
+			// a host without a route for one of the families is expected
+			if addr, err := defaultRouteAddr(target); err == nil {
+				candidates = append(candidates, addr)
+			}
+		}
+	default:
+		return hosting.IpAddresses{}, errors.Errorf("unknown address source '%s'", discovery.Source)
+	}
+
+	addresses := hosting.IpAddresses{}
+	for _, addr := range candidates {
+		addr = addr.Unmap()
+		if prefix != nil && !prefix.Contains(addr) {
+			continue
+		}
+		if discovery.Source != config.AddressSourceStatic && discovery.Source != "" && !addr.IsGlobalUnicast() {
+			continue
+		}
+		if addr.Is4() && addresses.IPv4 == nil {
+			addresses.IPv4 = &addr
+		} else if addr.Is6() && addresses.IPv6 == nil {
+			addresses.IPv6 = &addr
+		}
+	}
+
+	if addresses.IPv4 == nil && addresses.IPv6 == nil {
+		return addresses, errors.Errorf("no ip address found using source '%s'", discovery.Source)
+	}
+
+	return addresses, nil
+}
+
+// selectAddress picks the address to register for the compute, preferring the configured family.
+func selectAddress(addresses hosting.IpAddresses, family config.AddressFamily) (netip.Addr, error) {
+	preferred, fallback := addresses.IPv4, addresses.IPv6
+	if family == config.AddressFamilyIPv6 {
+		preferred, fallback = fallback, preferred
+	}
+
+	switch {
+	case preferred != nil:
+		return *preferred, nil
+	case fallback != nil:
+		return *fallback, nil
+	default:
+		return netip.Addr{}, errors.New("no ip address available")
+	}
+}
+
+func listInterfaceAddrs(name string) ([]netip.Addr, error) {
+	var interfaces []net.Interface
+	if len(name) != 0 {
+		i, err := net.InterfaceByName(name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to find network interface '%s'", name)
+		}
+		interfaces = append(interfaces, *i)
+	} else {
+		all, err := net.Interfaces()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list network interfaces")
+		}
+		interfaces = all
+	}
+
+	result := make([]netip.Addr, 0)
+	for _, i := range interfaces {
+		if i.Flags&net.FlagUp == 0 || i.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+
+		addrs, err := i.Addrs()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list addresses of network interface '%s'", i.Name)
+		}
+
+		for _, a := range addrs {
+			if ipNet, ok := a.(*net.IPNet); ok {
+				if addr, ok := netip.AddrFromSlice(ipNet.IP); ok {
+					result = append(result, addr)
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func dialDefaultRouteAddr(target string) (netip.Addr, error) {
+	conn, err := net.Dial("udp", target)
+	if err != nil {
+		return netip.Addr{}, errors.Wrapf(err, "no route to '%s'", target)
+	}
+	defer conn.Close()
+
+	return conn.LocalAddr().(*net.UDPAddr).AddrPort().Addr(), nil
+}
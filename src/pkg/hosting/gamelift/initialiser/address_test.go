@@ -0,0 +1,157 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package initialiser
+
+import (
+	"errors"
+	"net/netip"
+	"testing"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting"
+	"github.com/stretchr/testify/assert"
+)
+
+func stubAddressDiscovery(t *testing.T, interfaces []string, routes map[string]string) {
+	previousInterfaceAddrs, previousDefaultRouteAddr := interfaceAddrs, defaultRouteAddr
+	t.Cleanup(func() {
+		interfaceAddrs, defaultRouteAddr = previousInterfaceAddrs, previousDefaultRouteAddr
+	})
+
+	interfaceAddrs = func(name string) ([]netip.Addr, error) {
+		result := make([]netip.Addr, 0, len(interfaces))
+		for _, s := range interfaces {
+			result = append(result, netip.MustParseAddr(s))
+		}
+		return result, nil
+	}
+	defaultRouteAddr = func(target string) (netip.Addr, error) {
+		if s, ok := routes[target]; ok {
+			return netip.MustParseAddr(s), nil
+		}
+		return netip.Addr{}, errors.New("no route")
+	}
+}
+
+func Test_ResolveAddresses_Static(t *testing.T) {
+	//arrange
+	host := config.AnywhereHostConfig{IPv4Address: "192.168.1.1", IPv6Address: "2001:db8::1"}
+
+	//act
+	addresses, err := resolveAddresses(host)
+
+	//assert
+	assert.Nil(t, err)
+	assert.Equal(t, "192.168.1.1", addresses.IPv4.String())
+	assert.Equal(t, "2001:db8::1", addresses.IPv6.String())
+}
+
+func Test_ResolveAddresses_Static_Invalid(t *testing.T) {
+	//arrange
+	host := config.AnywhereHostConfig{IPv4Address: "not-an-address"}
+
+	//act
+	_, err := resolveAddresses(host)
+
+	//assert
+	assert.ErrorContains(t, err, "invalid ip address 'not-an-address'")
+}
+
+func Test_ResolveAddresses_Interface(t *testing.T) {
+	//arrange
+	stubAddressDiscovery(t, []string{"fe80::1", "10.0.0.5", "192.168.1.7", "2001:db8::7"}, nil)
+	host := config.AnywhereHostConfig{AddressDiscovery: config.AddressDiscovery{Source: config.AddressSourceInterface}}
+
+	//act
+	addresses, err := resolveAddresses(host)
+
+	//assert
+	assert.Nil(t, err)
+	assert.Equal(t, "10.0.0.5", addresses.IPv4.String())
+	assert.Equal(t, "2001:db8::7", addresses.IPv6.String())
+}
+
+func Test_ResolveAddresses_Interface_Cidr(t *testing.T) {
+	//arrange
+	stubAddressDiscovery(t, []string{"10.0.0.5", "192.168.1.7", "2001:db8::7"}, nil)
+	host := config.AnywhereHostConfig{AddressDiscovery: config.AddressDiscovery{
+		Source: config.AddressSourceInterface,
+		Cidr:   "192.168.0.0/16",
+	}}
+
+	//act
+	addresses, err := resolveAddresses(host)
+
+	//assert
+	assert.Nil(t, err)
+	assert.Equal(t, "192.168.1.7", addresses.IPv4.String())
+	assert.Nil(t, addresses.IPv6)
+}
+
+func Test_ResolveAddresses_Interface_NoMatch(t *testing.T) {
+	//arrange
+	stubAddressDiscovery(t, []string{"10.0.0.5"}, nil)
+	host := config.AnywhereHostConfig{AddressDiscovery: config.AddressDiscovery{
+		Source: config.AddressSourceInterface,
+		Cidr:   "192.168.0.0/16",
+	}}
+
+	//act
+	_, err := resolveAddresses(host)
+
+	//assert
+	assert.ErrorContains(t, err, "no ip address found using source 'interface'")
+}
+
+func Test_ResolveAddresses_DefaultRoute(t *testing.T) {
+	//arrange
+	stubAddressDiscovery(t, nil, map[string]string{defaultRouteTargetIPv4: "172.16.0.9"})
+	host := config.AnywhereHostConfig{AddressDiscovery: config.AddressDiscovery{Source: config.AddressSourceDefaultRoute}}
+
+	//act
+	addresses, err := resolveAddresses(host)
+
+	//assert
+	assert.Nil(t, err)
+	assert.Equal(t, "172.16.0.9", addresses.IPv4.String())
+	assert.Nil(t, addresses.IPv6)
+}
+
+func Test_SelectAddress(t *testing.T) {
+	ipv4 := netip.MustParseAddr("192.168.1.1")
+	ipv6 := netip.MustParseAddr("2001:db8::1")
+
+	tests := []struct {
+		name      string
+		addresses hosting.IpAddresses
+		family    config.AddressFamily
+		expected  string
+	}{
+		{name: "default prefers ipv4", addresses: hosting.IpAddresses{IPv4: &ipv4, IPv6: &ipv6}, expected: "192.168.1.1"},
+		{name: "ipv6 preferred", addresses: hosting.IpAddresses{IPv4: &ipv4, IPv6: &ipv6}, family: config.AddressFamilyIPv6, expected: "2001:db8::1"},
+		{name: "falls back to ipv6", addresses: hosting.IpAddresses{IPv6: &ipv6}, family: config.AddressFamilyIPv4, expected: "2001:db8::1"},
+		{name: "falls back to ipv4", addresses: hosting.IpAddresses{IPv4: &ipv4}, family: config.AddressFamilyIPv6, expected: "192.168.1.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			//act
+			addr, err := selectAddress(tt.addresses, tt.family)
+
+			//assert
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, addr.String())
+		})
+	}
+}
+
+func Test_SelectAddress_None(t *testing.T) {
+	//act
+	_, err := selectAddress(hosting.IpAddresses{}, "")
+
+	//assert
+	assert.ErrorContains(t, err, "no ip address available")
+}
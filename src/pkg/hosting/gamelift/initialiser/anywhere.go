@@ -8,18 +8,29 @@ package initialiser
 import (
 	"context"
 	"log/slog"
+	"net/netip"
 	"os"
-	"regexp"
-	"strings"
+	"sync"
+	"time"
 
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/constants"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting/gamelift/client"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting/gamelift/sdk"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/gamelift"
+	"github.com/aws/aws-sdk-go-v2/service/gamelift/types"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	deregisterAttempts   = 3
+	deregisterRetryDelay = time.Second
+	deregisterTimeout    = 30 * time.Second
 )
 
 type anywhere struct {
@@ -29,10 +40,24 @@ type anywhere struct {
 	authToken          string
 	fleetId            string
 	location           string
+	fleetCached        bool // set while the fleet and location come from the fleet cache
 	clientProvider     client.Provider
 	sdk                sdk.GameLiftSdk
 	cfg                *config.Anywhere
 	logger             *slog.Logger
+	retryDelay         time.Duration
+	tokens             *tokenManager
+	identity           *computeIdentity // nil when the compute name was provided
+	stop               chan struct{}
+	stopOnce           sync.Once
+	workers            sync.WaitGroup // the token manager and address watcher
+
+	mutex       sync.Mutex
+	glClient    client.GameLift
+	registered  bool   // set when the wrapper registered the compute itself
+	found       bool   // set when the compute was already registered under the hostname
+	ipAddress   string // the address the compute is registered with, empty when it is not registered
+	wssEndpoint string
 }
 
 // InitSdk initializes the GameLift SDK for Anywhere fleet usage.
@@ -59,42 +84,65 @@ func (anywhere *anywhere) InitSdk(ctx context.Context) error {
 			return errors.Wrap(err, "failed to get Amazon GameLift client provider")
 		}
 
+		address, err := anywhere.resolveAddress()
+		if err != nil {
+			return err
+		}
+
 		computes, err := glClient.ListCompute(ctx, &gamelift.ListComputeInput{
 			FleetId: &anywhere.fleetId,
 		})
+		if err != nil && anywhere.refreshFleet(ctx, err) {
+			computes, err = glClient.ListCompute(ctx, &gamelift.ListComputeInput{
+				FleetId: &anywhere.fleetId,
+			})
+		}
 
 		if err != nil {
 			return errors.Wrap(err, "failed to list compute")
 		}
 
-		for i := range computes.ComputeList {
-			if c := computes.ComputeList[i]; c.ComputeName != nil && *c.ComputeName == anywhere.hostname {
-				wssEndpoint = *c.GameLiftServiceSdkEndpoint
-				anywhere.logger.DebugContext(ctx, "found compute", "ComputeName", &anywhere.hostname, "serviceSdkEndpoint", wssEndpoint)
-				break
-			}
+		existing, err := anywhere.pickComputeName(ctx, computes.ComputeList, address)
+		if err != nil {
+			return err
 		}
 
-		if len(wssEndpoint) == 0 {
-			anywhere.logger.DebugContext(ctx, "registering compute", "ComputeName", &anywhere.hostname)
-			compute, err := glClient.RegisterCompute(ctx, &gamelift.RegisterComputeInput{
+		if existing != nil && !sameAddress(existing.IpAddress, address) && anywhere.identity.owns(anywhere.fleetId, anywhere.hostname) {
+			anywhere.logger.InfoContext(ctx, "compute registered by a previous run has another ip address, re-registering compute",
+				"computeName", anywhere.hostname, "previous", aws.ToString(existing.IpAddress), "current", address.String())
+			if _, err := glClient.DeregisterCompute(ctx, &gamelift.DeregisterComputeInput{
 				ComputeName: &anywhere.hostname,
 				FleetId:     &anywhere.fleetId,
-				IpAddress:   &anywhere.cfg.Host.IPv4Address,
-				Location:    &anywhere.location,
-			})
+			}); err != nil {
+				return errors.Wrap(err, "failed to deregister compute")
+			}
+			existing = nil
+		}
 
+		if existing != nil {
+			wssEndpoint = *existing.GameLiftServiceSdkEndpoint
+			anywhere.setCompute(glClient, false, aws.ToString(existing.IpAddress))
+			anywhere.logger.DebugContext(ctx, "found compute", "ComputeName", &anywhere.hostname, "serviceSdkEndpoint", wssEndpoint)
+		} else {
+			anywhere.logger.DebugContext(ctx, "registering compute", "ComputeName", &anywhere.hostname, "ipAddress", address.String())
+			wssEndpoint, err = anywhere.register(ctx, glClient, address.String())
+			if err != nil && anywhere.refreshFleet(ctx, err) {
+				wssEndpoint, err = anywhere.register(ctx, glClient, address.String())
+			}
 			if err != nil {
 				return errors.Wrap(err, "failed to register initialiser")
 			}
 
-			wssEndpoint = *compute.Compute.GameLiftServiceSdkEndpoint
+			anywhere.setCompute(glClient, true, address.String())
 		}
+
+		anywhere.rememberComputeName(ctx)
 	}
 
 	authToken := anywhere.authToken
 	if len(authToken) != 0 {
 		anywhere.logger.DebugContext(ctx, "using pre-set Anywhere configuration", "authToken", authToken)
+		anywhere.tokens.set(authToken, time.Time{})
 	} else {
 		anywhere.logger.DebugContext(ctx, "getting auth token")
 
@@ -105,73 +153,385 @@ func (anywhere *anywhere) InitSdk(ctx context.Context) error {
 			}
 		}
 
-		token, err := glClient.GetComputeAuthToken(ctx, &gamelift.GetComputeAuthTokenInput{
+		anywhere.tokens.glClient = glClient
+		if authToken, err = anywhere.tokens.fetch(ctx); err != nil {
+			return err
+		}
+	}
+
+	anywhere.setEndpoint(wssEndpoint)
+	anywhere.logger.DebugContext(ctx, "initialising sdk")
+	if err := anywhere.sdk.InitSDK(ctx, anywhere.serverParameters(authToken)); err != nil {
+		return errors.Wrap(err, "failed to init sdk for anywhere")
+	}
+
+	anywhere.sdk.SetOnConnectionLost(anywhere.tokens.connectionLost)
+	anywhere.workers.Add(1)
+	go func() {
+		defer anywhere.workers.Done()
+		anywhere.tokens.run(ctx)
+	}()
+
+	discovery := anywhere.cfg.Host.AddressDiscovery
+	if glClient != nil && discovery.CheckInterval > 0 &&
+		(discovery.Source == config.AddressSourceInterface || discovery.Source == config.AddressSourceDefaultRoute) {
+		anywhere.workers.Add(1)
+		go func() {
+			defer anywhere.workers.Done()
+			anywhere.watchAddress(ctx, discovery.CheckInterval)
+		}()
+	}
+
+	return nil
+}
+
+func (anywhere *anywhere) resolveAddress() (netip.Addr, error) {
+	addresses, err := resolveAddresses(anywhere.cfg.Host)
+	if err != nil {
+		return netip.Addr{}, errors.Wrap(err, "failed to find the compute ip address")
+	}
+
+	address, err := selectAddress(addresses, anywhere.cfg.Host.AddressDiscovery.Family)
+	if err != nil {
+		return netip.Addr{}, errors.Wrap(err, "failed to find the compute ip address")
+	}
+
+	return address, nil
+}
+
+// pickComputeName settles the compute name against the computes registered in the fleet, applying the
+// collision policy when the name is registered with another address. It returns the compute registered
+// under the settled name, nil if there is none.
+func (anywhere *anywhere) pickComputeName(ctx context.Context, computes []types.Compute, address netip.Addr) (*types.Compute, error) {
+	registered := make(map[string]types.Compute, len(computes))
+	for _, c := range computes {
+		if c.ComputeName != nil {
+			registered[*c.ComputeName] = c
+		}
+	}
+
+	policy := anywhere.cfg.Host.ComputeNameCollision
+	name := anywhere.hostname
+	for n := 1; ; n++ {
+		c, ok := registered[name]
+		if !ok {
+			break
+		}
+
+		if sameAddress(c.IpAddress, address) || anywhere.identity.owns(anywhere.fleetId, name) ||
+			policy == "" || policy == config.ComputeNameCollisionReuse {
+			anywhere.setName(name)
+			return &c, nil
+		}
+
+		if policy == config.ComputeNameCollisionFail {
+			return nil, errors.Errorf("compute name '%s' is already registered with ip address '%s'", name, aws.ToString(c.IpAddress))
+		}
+
+		if n > maxComputeNameSuffix {
+			return nil, errors.Errorf("no free compute name found for '%s' after %d attempts", anywhere.hostname, maxComputeNameSuffix)
+		}
+
+		anywhere.logger.InfoContext(ctx, "compute name is registered with another ip address, trying another name",
+			"computeName", name, "ipAddress", aws.ToString(c.IpAddress))
+		name = suffixComputeName(anywhere.hostname, n)
+	}
+
+	anywhere.setName(name)
+	return nil, nil
+}
+
+func (anywhere *anywhere) setName(name string) {
+	anywhere.hostname = name
+	anywhere.tokens.computeName = name
+}
+
+// rememberComputeName stores the compute name in the instance identity so a restart reuses the compute.
+func (anywhere *anywhere) rememberComputeName(ctx context.Context) {
+	if anywhere.identity == nil {
+		return
+	}
+
+	anywhere.identity.ComputeName = anywhere.hostname
+	if err := anywhere.identity.save(); err != nil {
+		anywhere.logger.WarnContext(ctx, "failed to save compute identity", "computeName", anywhere.hostname, "err", err)
+	}
+}
+
+// refreshFleet looks the fleet and location up again when a call failed because the cached ones no longer
+// exist, and reports whether the call should be retried with them.
+func (anywhere *anywhere) refreshFleet(ctx context.Context, err error) bool {
+	if !anywhere.fleetCached || !isStaleFleetError(err) {
+		return false
+	}
+	anywhere.fleetCached = false
+
+	anywhere.logger.WarnContext(ctx, "cached anywhere fleet is no longer valid, looking it up again",
+		"fleetId", anywhere.fleetId, "location", anywhere.location, "err", err)
+	fleetId, location, _, err := resolveFleet(ctx, anywhere.cfg, anywhere.clientProvider, anywhere.logger, true)
+	if err != nil {
+		anywhere.logger.ErrorContext(ctx, "failed to look up the anywhere fleet again", "err", err)
+		return false
+	}
+
+	anywhere.fleetId = fleetId
+	anywhere.location = location
+	anywhere.tokens.fleetId = fleetId
+	return true
+}
+
+func sameAddress(registered *string, address netip.Addr) bool {
+	addr, err := netip.ParseAddr(aws.ToString(registered))
+	return err == nil && addr.Unmap() == address.Unmap()
+}
+
+func (anywhere *anywhere) register(ctx context.Context, glClient client.GameLift, ipAddress string) (string, error) {
+	compute, err := glClient.RegisterCompute(ctx, &gamelift.RegisterComputeInput{
+		ComputeName: &anywhere.hostname,
+		FleetId:     &anywhere.fleetId,
+		IpAddress:   &ipAddress,
+		Location:    &anywhere.location,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return *compute.Compute.GameLiftServiceSdkEndpoint, nil
+}
+
+// watchAddress re-registers the compute whenever the discovered ip address changes.
+func (anywhere *anywhere) watchAddress(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-anywhere.stop:
+			return
+		case <-ticker.C:
+			anywhere.checkAddress(ctx)
+		}
+	}
+}
+
+func (anywhere *anywhere) checkAddress(ctx context.Context) {
+	addresses, err := resolveAddresses(anywhere.cfg.Host)
+	if err == nil {
+		var address netip.Addr
+		if address, err = selectAddress(addresses, anywhere.cfg.Host.AddressDiscovery.Family); err == nil {
+			anywhere.reregister(ctx, address.String())
+			return
+		}
+	}
+
+	anywhere.logger.WarnContext(ctx, "failed to check the compute ip address", "computeName", anywhere.hostname, "err", err)
+}
+
+func (anywhere *anywhere) reregister(ctx context.Context, ipAddress string) {
+	anywhere.mutex.Lock()
+	defer anywhere.mutex.Unlock()
+
+	if anywhere.glClient == nil || ipAddress == anywhere.ipAddress {
+		return
+	}
+
+	anywhere.logger.InfoContext(ctx, "compute ip address changed, re-registering compute", "computeName", anywhere.hostname, "previous", anywhere.ipAddress, "current", ipAddress)
+
+	// a previous attempt may have deregistered the compute already
+	if len(anywhere.ipAddress) != 0 {
+		if _, err := anywhere.glClient.DeregisterCompute(ctx, &gamelift.DeregisterComputeInput{
 			ComputeName: &anywhere.hostname,
 			FleetId:     &anywhere.fleetId,
-		})
-		if err != nil {
-			return errors.Wrap(err, "failed to get Amazon GameLift Anywhere token")
+		}); err != nil {
+			anywhere.logger.ErrorContext(ctx, "failed to deregister compute to change its ip address", "computeName", anywhere.hostname, "err", err)
+			return
 		}
-		authToken = *token.AuthToken
+		anywhere.ipAddress = ""
 	}
 
-	params := server.ServerParameters{
+	wssEndpoint, err := anywhere.register(ctx, anywhere.glClient, ipAddress)
+	if err != nil {
+		anywhere.logger.ErrorContext(ctx, "failed to re-register compute", "computeName", anywhere.hostname, "ipAddress", ipAddress, "err", err)
+		return
+	}
+
+	anywhere.ipAddress = ipAddress
+	anywhere.wssEndpoint = wssEndpoint
+	anywhere.registered = true
+	anywhere.found = false
+	anywhere.logger.InfoContext(ctx, "re-registered compute", "computeName", anywhere.hostname, "ipAddress", ipAddress)
+
+	// the auth token and connection belong to the previous registration
+	anywhere.tokens.connectionLost()
+}
+
+func (anywhere *anywhere) setEndpoint(wssEndpoint string) {
+	anywhere.mutex.Lock()
+	defer anywhere.mutex.Unlock()
+	anywhere.wssEndpoint = wssEndpoint
+}
+
+func (anywhere *anywhere) serverParameters(authToken string) server.ServerParameters {
+	anywhere.mutex.Lock()
+	defer anywhere.mutex.Unlock()
+
+	return server.ServerParameters{
 		HostID:       anywhere.hostname,
 		FleetID:      anywhere.fleetId,
 		AuthToken:    authToken,
 		ProcessID:    anywhere.processId,
-		WebSocketURL: wssEndpoint,
+		WebSocketURL: anywhere.wssEndpoint,
 	}
+}
 
-	anywhere.logger.DebugContext(ctx, "initialising sdk")
-	if err := anywhere.sdk.InitSDK(ctx, params); err != nil {
-		return errors.Wrap(err, "failed to init sdk for anywhere")
+func (anywhere *anywhere) reconnect(ctx context.Context, authToken string) error {
+	if err := anywhere.sdk.Reconnect(ctx, anywhere.serverParameters(authToken)); err != nil {
+		return errors.Wrap(err, "failed to reconnect sdk for anywhere")
 	}
-
 	return nil
 }
 
+// Stop ends the token manager and the address watcher and waits for them, so a reconnection or
+// re-registration in progress finishes before the SDK is torn down.
+func (anywhere *anywhere) Stop() {
+	anywhere.stopOnce.Do(func() {
+		close(anywhere.stop)
+	})
+	anywhere.tokens.close()
+	anywhere.workers.Wait()
+}
+
+func (anywhere *anywhere) Close(ctx context.Context) error {
+	anywhere.Stop()
+
+	anywhere.mutex.Lock()
+	defer anywhere.mutex.Unlock()
+
+	if anywhere.glClient == nil {
+		return nil
+	}
+
+	mode := anywhere.cfg.Host.DeregisterCompute
+	switch {
+	case mode == config.DeregisterComputeKeep:
+		anywhere.logger.InfoContext(ctx, "keeping compute registered", "computeName", anywhere.hostname, "fleetId", anywhere.fleetId, "mode", mode)
+		return nil
+	case anywhere.registered:
+	case anywhere.found && mode == config.DeregisterComputeAlways:
+	default:
+		anywhere.logger.InfoContext(ctx, "keeping compute registered, it was not registered by the wrapper", "computeName", anywhere.hostname, "fleetId", anywhere.fleetId)
+		return nil
+	}
+
+	// deregister even if the run context has already been cancelled by the shutdown
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), deregisterTimeout)
+	defer cancel()
+
+	var err error
+	delay := anywhere.retryDelay
+	for attempt := 1; attempt <= deregisterAttempts; attempt++ {
+		anywhere.logger.DebugContext(ctx, "deregistering compute", "computeName", anywhere.hostname, "fleetId", anywhere.fleetId, "attempt", attempt)
+		_, err = anywhere.glClient.DeregisterCompute(ctx, &gamelift.DeregisterComputeInput{
+			ComputeName: &anywhere.hostname,
+			FleetId:     &anywhere.fleetId,
+		})
+		if err == nil {
+			anywhere.logger.InfoContext(ctx, "deregistered compute", "computeName", anywhere.hostname, "fleetId", anywhere.fleetId, "attempts", attempt)
+			anywhere.glClient = nil
+			return nil
+		}
+
+		anywhere.logger.WarnContext(ctx, "failed to deregister compute", "computeName", anywhere.hostname, "fleetId", anywhere.fleetId, "attempt", attempt, "err", err)
+		if attempt == deregisterAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Wrapf(ctx.Err(), "failed to deregister compute '%s'", anywhere.hostname)
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+
+	anywhere.logger.ErrorContext(ctx, "giving up deregistering compute", "computeName", anywhere.hostname, "fleetId", anywhere.fleetId, "attempts", deregisterAttempts, "err", err)
+	return errors.Wrapf(err, "failed to deregister compute '%s' after %d attempts", anywhere.hostname, deregisterAttempts)
+}
+
+func (anywhere *anywhere) setCompute(glClient client.GameLift, registered bool, ipAddress string) {
+	anywhere.mutex.Lock()
+	defer anywhere.mutex.Unlock()
+
+	anywhere.glClient = glClient
+	anywhere.registered = registered
+	anywhere.found = !registered
+	anywhere.ipAddress = ipAddress
+}
+
 func getHostname() (string, error) {
 	hostname, err := os.Hostname()
 	if err != nil {
 		return "", errors.Wrap(err, "failed to get machine hostname...")
 	}
 
-	// Ensure that hostname complies with the string requirements for RegisterCompute
-	hostname = regexp.MustCompile("[^a-zA-Z0-9\\-]").ReplaceAllString(hostname, "")
-	if len(hostname) > 128 {
-		hostname = hostname[:128]
+	return sanitizeComputeName(hostname, maxComputeNameLength), nil
+}
+
+// getComputeName renders the compute name template, reusing the name stored in the instance identity.
+func getComputeName(ctx context.Context, cfg *config.Anywhere, fleetId string, logger *slog.Logger) (string, *computeIdentity, error) {
+	hostname, err := getHostname()
+	if err != nil {
+		return "", nil, err
 	}
 
-	return hostname, nil
-}
+	appDir, _ := ctx.Value(string(constants.ContextKeyAppDir)).(string)
+	identity, err := loadComputeIdentity(appDir, cfg.Host.InstanceIndex)
+	if err != nil {
+		logger.WarnContext(ctx, "failed to load compute identity, using a new one", "err", err)
+	}
 
-func newAnywhere(ctx context.Context, cfg *config.Anywhere, gl sdk.GameLiftSdk, logger *slog.Logger, clientProvider client.Provider) (Service, error) {
-	var err error
+	tmpl := cfg.Host.ComputeNameTemplate
+	if len(tmpl) == 0 {
+		tmpl = defaultComputeNameTemplate
+	}
+
+	name, err := identity.computeName(fleetId, tmpl, computeNameData{
+		Hostname:   hostname,
+		Port:       cfg.Host.GamePort,
+		Index:      cfg.Host.InstanceIndex,
+		InstanceId: identity.InstanceId,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err := identity.save(); err != nil {
+		logger.WarnContext(ctx, "failed to save compute identity", "err", err)
+	}
+
+	return name, identity, nil
+}
 
+func newAnywhere(ctx context.Context, cfg *config.Anywhere, gl sdk.GameLiftSdk, logger *slog.Logger, clientProvider client.Provider, meter metric.Meter) (Service, error) {
 	processId := common.GetEnvStringOrDefault(common.EnvironmentKeyProcessID, uuid.New().String())
 	serviceSdkEndpoint := common.GetEnvStringOrDefault(common.EnvironmentKeyWebsocketURL, cfg.Host.ServiceSdkEndpoint)
 	authToken := common.GetEnvStringOrDefault(common.EnvironmentKeyAuthToken, cfg.Host.AuthToken)
 	hostname := common.GetEnvStringOrDefault(common.EnvironmentKeyHostID, cfg.Host.HostName)
 
+	fleetId, location, fleetCached, err := resolveFleet(ctx, cfg, clientProvider, logger, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var identity *computeIdentity
 	if len(hostname) == 0 {
-		if hostname, err = getHostname(); err != nil {
+		if hostname, identity, err = getComputeName(ctx, cfg, fleetId, logger); err != nil {
 			return nil, err
 		}
 	}
 
-	fleetParts := strings.Split(cfg.Host.FleetArn, "/")
-	if len(fleetParts) != 2 {
-		return nil, errors.Errorf("invalid fleet arn '%s'", cfg.Host.FleetArn)
-	}
-	fleetId := fleetParts[1]
-
-	locationParts := strings.Split(cfg.Host.LocationArn, "/")
-	if len(locationParts) != 2 {
-		return nil, errors.Errorf("invalid location arn '%s'", cfg.Host.LocationArn)
-	}
-	location := locationParts[1]
-
 	a := &anywhere{
 		processId:          processId,
 		hostname:           hostname,
@@ -179,10 +539,18 @@ func newAnywhere(ctx context.Context, cfg *config.Anywhere, gl sdk.GameLiftSdk,
 		authToken:          authToken,
 		fleetId:            fleetId,
 		location:           location,
+		fleetCached:        fleetCached,
 		clientProvider:     clientProvider,
 		sdk:                gl,
 		cfg:                cfg,
 		logger:             logger,
+		retryDelay:         deregisterRetryDelay,
+		identity:           identity,
+		stop:               make(chan struct{}),
+	}
+
+	if a.tokens, err = newTokenManager(hostname, fleetId, nil, a.reconnect, logger, meter); err != nil {
+		return nil, err
 	}
 
 	return a, nil
@@ -10,16 +10,19 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/internal/mocks"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/constants"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting/gamelift/client"
 	"github.com/aws/aws-sdk-go-v2/service/gamelift"
 	"github.com/aws/aws-sdk-go-v2/service/gamelift/types"
 	"github.com/aws/smithy-go/middleware"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/metric/noop"
 )
 
 type AnywhereMockHelper struct {
@@ -27,6 +30,7 @@ type AnywhereMockHelper struct {
 	logBuffer      *bytes.Buffer
 	gameLiftSdk    *mocks.GameLiftSdkMock
 	ctx            context.Context
+	cancel         context.CancelFunc
 	config         *config.Anywhere
 	clientProvider *client.ClientProviderMock
 	clientGameLift *client.ClientGameLiftMock
@@ -46,19 +50,20 @@ const (
 
 func createAnywhereMockHelper(config *config.Anywhere) AnywhereMockHelper {
 	logBuffer := bytes.Buffer{}
-	ctx, _ := context.WithTimeout(context.Background(), time.Second*5)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	logger := slog.New(slog.NewTextHandler(&logBuffer, &slog.HandlerOptions{
 		Level: slog.LevelDebug,
 	}))
 	gameLiftSdkMock := mocks.GameLiftSdkMock{}
 	clientProvider := client.ClientProviderMock{}
 	clientGameLift := client.ClientGameLiftMock{}
-	anywhere, _ := newAnywhere(ctx, config, &gameLiftSdkMock, logger, &clientProvider)
+	anywhere, _ := newAnywhere(ctx, config, &gameLiftSdkMock, logger, &clientProvider, noop.NewMeterProvider().Meter("test"))
 	return AnywhereMockHelper{
 		logger:         logger,
 		logBuffer:      &logBuffer,
 		gameLiftSdk:    &gameLiftSdkMock,
 		ctx:            ctx,
+		cancel:         cancel,
 		config:         config,
 		clientProvider: &clientProvider,
 		clientGameLift: &clientGameLift,
@@ -138,6 +143,7 @@ func Test_Anywhere_InitSdk_HappyPath(t *testing.T) {
 	assert.Equal(t, gameLiftMockHelper.gameLiftSdk.ServerParameters.HostID, hostname)
 	assert.Equal(t, gameLiftMockHelper.gameLiftSdk.ServerParameters.WebSocketURL, gameLiftServiceSdkEndpoint)
 	assert.Equal(t, gameLiftMockHelper.gameLiftSdk.ServerParameters.AuthToken, getComputeAuthTokenOutputAuthToken)
+	assert.NotNil(t, gameLiftMockHelper.gameLiftSdk.OnConnectionLost)
 }
 
 func Test_Anywhere_InitSdk_HappyPath_ProvidedCompute(t *testing.T) {
@@ -223,6 +229,36 @@ func Test_Anywhere_InitSdk_HappyPath_ProvidedComputeAndAuthToken(t *testing.T) {
 	assert.Equal(t, gameLiftMockHelper.gameLiftSdk.ServerParameters.AuthToken, anyWhereConfig.Host.AuthToken)
 }
 
+func Test_Anywhere_Stop_ConnectionLost(t *testing.T) {
+	//arrange
+	anyWhereConfig := config.Anywhere{
+		Host: config.AnywhereHostConfig{
+			HostName:           "UnitTest",
+			ServiceSdkEndpoint: "endpoint",
+			AuthToken:          "authToken",
+			LocationArn:        locationArn,
+			FleetArn:           fleetArn,
+			IPv4Address:        IPv4Address,
+		},
+	}
+	gameLiftMockHelper := createAnywhereMockHelper(&anyWhereConfig)
+	a := gameLiftMockHelper.anywhere.(*anywhere)
+	a.tokens.retryDelay = time.Millisecond
+	gameLiftMockHelper.gameLiftSdk.ReconnectError = errors.New("Unit Test")
+	assert.NoError(t, a.InitSdk(gameLiftMockHelper.ctx))
+
+	//act
+	a.tokens.connectionLost()
+	a.Stop()
+	reconnects := len(gameLiftMockHelper.gameLiftSdk.ReconnectParameters)
+	a.tokens.connectionLost()
+	time.Sleep(20 * time.Millisecond)
+
+	//assert
+	assert.LessOrEqual(t, reconnects, 1)
+	assert.Len(t, gameLiftMockHelper.gameLiftSdk.ReconnectParameters, reconnects)
+}
+
 func Test_Anywhere_InitSdk_GetGameLift_Error(t *testing.T) {
 	//arrange
 	anyWhereConfig := config.Anywhere{
@@ -314,6 +350,42 @@ func Test_Anywhere_InitSdk_RegisterCompute_Error(t *testing.T) {
 	assert.Contains(t, *gameLiftMockHelper.clientGameLift.RegisterComputeInput.ComputeName, anyWhereConfig.Host.HostName)
 }
 
+func Test_Anywhere_InitSdk_StaleFleetCache(t *testing.T) {
+	//arrange
+	appDir := t.TempDir()
+	ctx := context.WithValue(context.Background(), string(constants.ContextKeyAppDir), appDir)
+	stale := &fleetCache{Region: "eu-west-2", Fleet: "my-fleet", Location: locationName,
+		FleetId: "fleet-00000000-0000-0000-0000-000000000000", path: filepath.Join(appDir, fleetCacheFile)}
+	assert.NoError(t, stale.save())
+	anyWhereConfig := config.Anywhere{
+		Host: config.AnywhereHostConfig{
+			HostName:    "UnitTest",
+			Fleet:       "my-fleet",
+			Location:    locationName,
+			IPv4Address: IPv4Address,
+		},
+	}
+	clientProvider := createFleetClientProvider()
+	clientProvider.GetGameLiftResponse.ListComputeResult = &gamelift.ListComputeOutput{}
+	clientProvider.GetGameLiftResponse.RegisterComputeError = &types.NotFoundException{}
+	logBuffer := bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(&logBuffer, nil))
+	anywhere, err := newAnywhere(ctx, &anyWhereConfig, &mocks.GameLiftSdkMock{}, logger, clientProvider, noop.NewMeterProvider().Meter("test"))
+	assert.NoError(t, err)
+
+	//act
+	err = anywhere.InitSdk(ctx)
+	cache, cacheErr := loadFleetCache(appDir)
+
+	//assert
+	assert.ErrorContains(t, err, "failed to register initialiser")
+	assert.NoError(t, cacheErr)
+	assert.Equal(t, fleetId, cache.FleetId)
+	assert.Equal(t, fleetId, *clientProvider.GetGameLiftResponse.RegisterComputeInput.FleetId)
+	assert.Equal(t, 1, clientProvider.GetGameLiftResponse.DescribeFleetAttributesCalls)
+	assert.Contains(t, logBuffer.String(), "cached anywhere fleet is no longer valid")
+}
+
 func Test_Anywhere_InitSdk_GetComputeAuthToken_Error(t *testing.T) {
 	//arrange
 	anyWhereConfig := config.Anywhere{
@@ -446,3 +518,382 @@ func Test_Anywhere_InitSdk_InitSDK_Error(t *testing.T) {
 	assert.True(t, gameLiftMockHelper.gameLiftSdk.InitSdkCalled)
 	assert.Equal(t, gameLiftServiceSdkEndpoint, gameLiftMockHelper.gameLiftSdk.ServerParameters.WebSocketURL)
 }
+
+func createRegisteringAnywhereMockHelper(mode config.DeregisterComputeMode, existingComputeName string) AnywhereMockHelper {
+	anyWhereConfig := config.Anywhere{
+		Config: config.AwsConfig{},
+		Host: config.AnywhereHostConfig{
+			HostName:          "UnitTest",
+			LocationArn:       locationArn,
+			FleetArn:          fleetArn,
+			IPv4Address:       IPv4Address,
+			DeregisterCompute: mode,
+		},
+	}
+	gameLiftMockHelper := createAnywhereMockHelper(&anyWhereConfig)
+	gameLiftMockHelper.anywhere.(*anywhere).retryDelay = time.Millisecond
+	gameLiftMockHelper.clientProvider.GetGameLiftResponse = gameLiftMockHelper.clientGameLift
+
+	gameLiftServiceSdkEndpoint := "GameLiftServiceSdkEndpoint"
+	computes := []types.Compute{}
+	if len(existingComputeName) != 0 {
+		computes = append(computes, types.Compute{ComputeName: &existingComputeName, GameLiftServiceSdkEndpoint: &gameLiftServiceSdkEndpoint})
+	}
+	gameLiftMockHelper.clientGameLift.ListComputeResult = &gamelift.ListComputeOutput{ComputeList: computes}
+	gameLiftMockHelper.clientGameLift.RegisterComputeResult = &gamelift.RegisterComputeOutput{
+		Compute: &types.Compute{GameLiftServiceSdkEndpoint: &gameLiftServiceSdkEndpoint},
+	}
+	authToken := "authToken"
+	gameLiftMockHelper.clientGameLift.GetComputeAuthTokenResult = &gamelift.GetComputeAuthTokenOutput{AuthToken: &authToken}
+
+	return gameLiftMockHelper
+}
+
+func Test_Anywhere_Close_DeregistersRegisteredCompute(t *testing.T) {
+	//arrange
+	gameLiftMockHelper := createRegisteringAnywhereMockHelper("", "")
+	err := gameLiftMockHelper.anywhere.InitSdk(gameLiftMockHelper.ctx)
+	assert.Nil(t, err)
+
+	//act
+	err = gameLiftMockHelper.anywhere.Close(gameLiftMockHelper.ctx)
+
+	//assert
+	assert.Nil(t, err)
+	assert.Equal(t, 1, gameLiftMockHelper.clientGameLift.DeregisterComputeCalls)
+	assert.Equal(t, "UnitTest", *gameLiftMockHelper.clientGameLift.DeregisterComputeInput.ComputeName)
+	assert.Equal(t, fleetId, *gameLiftMockHelper.clientGameLift.DeregisterComputeInput.FleetId)
+	assert.Contains(t, gameLiftMockHelper.logBuffer.String(), "deregistered compute")
+}
+
+func Test_Anywhere_Close_OnlyDeregistersOnce(t *testing.T) {
+	//arrange
+	gameLiftMockHelper := createRegisteringAnywhereMockHelper(config.DeregisterComputeAuto, "")
+	err := gameLiftMockHelper.anywhere.InitSdk(gameLiftMockHelper.ctx)
+	assert.Nil(t, err)
+
+	//act
+	err1 := gameLiftMockHelper.anywhere.Close(gameLiftMockHelper.ctx)
+	err2 := gameLiftMockHelper.anywhere.Close(gameLiftMockHelper.ctx)
+
+	//assert
+	assert.Nil(t, err1)
+	assert.Nil(t, err2)
+	assert.Equal(t, 1, gameLiftMockHelper.clientGameLift.DeregisterComputeCalls)
+}
+
+func Test_Anywhere_Close_Keep(t *testing.T) {
+	//arrange
+	gameLiftMockHelper := createRegisteringAnywhereMockHelper(config.DeregisterComputeKeep, "")
+	err := gameLiftMockHelper.anywhere.InitSdk(gameLiftMockHelper.ctx)
+	assert.Nil(t, err)
+
+	//act
+	err = gameLiftMockHelper.anywhere.Close(gameLiftMockHelper.ctx)
+
+	//assert
+	assert.Nil(t, err)
+	assert.Equal(t, 0, gameLiftMockHelper.clientGameLift.DeregisterComputeCalls)
+	assert.Contains(t, gameLiftMockHelper.logBuffer.String(), "keeping compute registered")
+}
+
+func Test_Anywhere_Close_ExistingCompute(t *testing.T) {
+	//arrange
+	gameLiftMockHelper := createRegisteringAnywhereMockHelper(config.DeregisterComputeAuto, "UnitTest")
+	err := gameLiftMockHelper.anywhere.InitSdk(gameLiftMockHelper.ctx)
+	assert.Nil(t, err)
+
+	//act
+	err = gameLiftMockHelper.anywhere.Close(gameLiftMockHelper.ctx)
+
+	//assert
+	assert.Nil(t, err)
+	assert.Nil(t, gameLiftMockHelper.clientGameLift.RegisterComputeInput)
+	assert.Equal(t, 0, gameLiftMockHelper.clientGameLift.DeregisterComputeCalls)
+	assert.Contains(t, gameLiftMockHelper.logBuffer.String(), "it was not registered by the wrapper")
+}
+
+func Test_Anywhere_Close_ExistingCompute_Always(t *testing.T) {
+	//arrange
+	gameLiftMockHelper := createRegisteringAnywhereMockHelper(config.DeregisterComputeAlways, "UnitTest")
+	err := gameLiftMockHelper.anywhere.InitSdk(gameLiftMockHelper.ctx)
+	assert.Nil(t, err)
+
+	//act
+	err = gameLiftMockHelper.anywhere.Close(gameLiftMockHelper.ctx)
+
+	//assert
+	assert.Nil(t, err)
+	assert.Equal(t, 1, gameLiftMockHelper.clientGameLift.DeregisterComputeCalls)
+}
+
+func Test_Anywhere_Close_ProvidedCompute(t *testing.T) {
+	//arrange
+	anyWhereConfig := config.Anywhere{
+		Config: config.AwsConfig{},
+		Host: config.AnywhereHostConfig{
+			HostName:           "UnitTest",
+			ServiceSdkEndpoint: "endpoint",
+			AuthToken:          "authToken",
+			LocationArn:        locationArn,
+			FleetArn:           fleetArn,
+			IPv4Address:        IPv4Address,
+			DeregisterCompute:  config.DeregisterComputeAlways,
+		},
+	}
+	gameLiftMockHelper := createAnywhereMockHelper(&anyWhereConfig)
+	err := gameLiftMockHelper.anywhere.InitSdk(gameLiftMockHelper.ctx)
+	assert.Nil(t, err)
+
+	//act
+	err = gameLiftMockHelper.anywhere.Close(gameLiftMockHelper.ctx)
+
+	//assert
+	assert.Nil(t, err)
+	assert.Equal(t, 0, gameLiftMockHelper.clientGameLift.DeregisterComputeCalls)
+}
+
+func Test_Anywhere_Close_DeregisterCompute_Error(t *testing.T) {
+	//arrange
+	gameLiftMockHelper := createRegisteringAnywhereMockHelper(config.DeregisterComputeAuto, "")
+	err := gameLiftMockHelper.anywhere.InitSdk(gameLiftMockHelper.ctx)
+	assert.Nil(t, err)
+	gameLiftMockHelper.clientGameLift.DeregisterComputeError = errors.New("Unit Test")
+
+	//act
+	err = gameLiftMockHelper.anywhere.Close(gameLiftMockHelper.ctx)
+
+	//assert
+	assert.ErrorContains(t, err, "failed to deregister compute 'UnitTest' after 3 attempts: Unit Test")
+	assert.Equal(t, deregisterAttempts, gameLiftMockHelper.clientGameLift.DeregisterComputeCalls)
+	assert.Contains(t, gameLiftMockHelper.logBuffer.String(), "giving up deregistering compute")
+}
+
+func Test_Anywhere_Close_CancelledContext(t *testing.T) {
+	//arrange
+	gameLiftMockHelper := createRegisteringAnywhereMockHelper(config.DeregisterComputeAuto, "")
+	err := gameLiftMockHelper.anywhere.InitSdk(gameLiftMockHelper.ctx)
+	assert.Nil(t, err)
+	gameLiftMockHelper.cancel()
+
+	//act
+	err = gameLiftMockHelper.anywhere.Close(gameLiftMockHelper.ctx)
+
+	//assert
+	assert.Nil(t, err)
+	assert.Equal(t, 1, gameLiftMockHelper.clientGameLift.DeregisterComputeCalls)
+}
+
+func Test_Anywhere_InitSdk_IPv6Address(t *testing.T) {
+	//arrange
+	gameLiftMockHelper := createRegisteringAnywhereMockHelper("", "")
+	gameLiftMockHelper.config.Host.IPv4Address = ""
+	gameLiftMockHelper.config.Host.IPv6Address = "2001:db8::1"
+
+	//act
+	err := gameLiftMockHelper.anywhere.InitSdk(gameLiftMockHelper.ctx)
+
+	//assert
+	assert.Nil(t, err)
+	assert.Equal(t, "2001:db8::1", *gameLiftMockHelper.clientGameLift.RegisterComputeInput.IpAddress)
+}
+
+func Test_Anywhere_InitSdk_PreferredFamily(t *testing.T) {
+	//arrange
+	gameLiftMockHelper := createRegisteringAnywhereMockHelper("", "")
+	gameLiftMockHelper.config.Host.IPv6Address = "2001:db8::1"
+	gameLiftMockHelper.config.Host.AddressDiscovery.Family = config.AddressFamilyIPv6
+
+	//act
+	err := gameLiftMockHelper.anywhere.InitSdk(gameLiftMockHelper.ctx)
+
+	//assert
+	assert.Nil(t, err)
+	assert.Equal(t, "2001:db8::1", *gameLiftMockHelper.clientGameLift.RegisterComputeInput.IpAddress)
+}
+
+func Test_Anywhere_InitSdk_NoAddress_Error(t *testing.T) {
+	//arrange
+	gameLiftMockHelper := createRegisteringAnywhereMockHelper("", "")
+	gameLiftMockHelper.config.Host.IPv4Address = ""
+
+	//act
+	err := gameLiftMockHelper.anywhere.InitSdk(gameLiftMockHelper.ctx)
+
+	//assert
+	assert.ErrorContains(t, err, "failed to find the compute ip address")
+	assert.Nil(t, gameLiftMockHelper.clientGameLift.RegisterComputeInput)
+}
+
+func Test_Anywhere_Reregister_AddressChanged(t *testing.T) {
+	//arrange
+	gameLiftMockHelper := createRegisteringAnywhereMockHelper("", "")
+	err := gameLiftMockHelper.anywhere.InitSdk(gameLiftMockHelper.ctx)
+	assert.Nil(t, err)
+	a := gameLiftMockHelper.anywhere.(*anywhere)
+	a.Stop()
+	newEndpoint := "NewGameLiftServiceSdkEndpoint"
+	gameLiftMockHelper.clientGameLift.RegisterComputeResult = &gamelift.RegisterComputeOutput{
+		Compute: &types.Compute{GameLiftServiceSdkEndpoint: &newEndpoint},
+	}
+
+	//act
+	a.reregister(gameLiftMockHelper.ctx, "192.168.1.2")
+
+	//assert
+	assert.Equal(t, 1, gameLiftMockHelper.clientGameLift.DeregisterComputeCalls)
+	assert.Equal(t, "192.168.1.2", *gameLiftMockHelper.clientGameLift.RegisterComputeInput.IpAddress)
+	assert.Equal(t, newEndpoint, a.serverParameters("token").WebSocketURL)
+	assert.Len(t, a.tokens.lost, 1)
+	assert.Contains(t, gameLiftMockHelper.logBuffer.String(), "re-registered compute")
+}
+
+func Test_Anywhere_Reregister_AddressUnchanged(t *testing.T) {
+	//arrange
+	gameLiftMockHelper := createRegisteringAnywhereMockHelper("", "")
+	err := gameLiftMockHelper.anywhere.InitSdk(gameLiftMockHelper.ctx)
+	assert.Nil(t, err)
+	a := gameLiftMockHelper.anywhere.(*anywhere)
+	a.Stop()
+
+	//act
+	a.reregister(gameLiftMockHelper.ctx, IPv4Address)
+
+	//assert
+	assert.Equal(t, 0, gameLiftMockHelper.clientGameLift.DeregisterComputeCalls)
+	assert.Empty(t, a.tokens.lost)
+}
+
+func Test_Anywhere_Reregister_RegisterCompute_Error(t *testing.T) {
+	//arrange
+	gameLiftMockHelper := createRegisteringAnywhereMockHelper("", "")
+	err := gameLiftMockHelper.anywhere.InitSdk(gameLiftMockHelper.ctx)
+	assert.Nil(t, err)
+	a := gameLiftMockHelper.anywhere.(*anywhere)
+	a.Stop()
+	gameLiftMockHelper.clientGameLift.RegisterComputeError = errors.New("Unit Test")
+	a.reregister(gameLiftMockHelper.ctx, "192.168.1.2")
+	gameLiftMockHelper.clientGameLift.RegisterComputeError = nil
+
+	//act
+	a.reregister(gameLiftMockHelper.ctx, "192.168.1.2")
+
+	//assert
+	assert.Equal(t, 1, gameLiftMockHelper.clientGameLift.DeregisterComputeCalls)
+	assert.Equal(t, "192.168.1.2", *gameLiftMockHelper.clientGameLift.RegisterComputeInput.IpAddress)
+	assert.Contains(t, gameLiftMockHelper.logBuffer.String(), "failed to re-register compute")
+	assert.Len(t, a.tokens.lost, 1)
+}
+
+func Test_Anywhere_InitSdk_ComputeNameCollision_Fail(t *testing.T) {
+	//arrange
+	gameLiftMockHelper := createRegisteringAnywhereMockHelper("", "UnitTest")
+	gameLiftMockHelper.config.Host.ComputeNameCollision = config.ComputeNameCollisionFail
+	otherAddress := "10.0.0.1"
+	gameLiftMockHelper.clientGameLift.ListComputeResult.ComputeList[0].IpAddress = &otherAddress
+
+	//act
+	err := gameLiftMockHelper.anywhere.InitSdk(gameLiftMockHelper.ctx)
+
+	//assert
+	assert.ErrorContains(t, err, "compute name 'UnitTest' is already registered with ip address '10.0.0.1'")
+	assert.Nil(t, gameLiftMockHelper.clientGameLift.RegisterComputeInput)
+}
+
+func Test_Anywhere_InitSdk_ComputeNameCollision_Suffix(t *testing.T) {
+	//arrange
+	gameLiftMockHelper := createRegisteringAnywhereMockHelper("", "UnitTest")
+	gameLiftMockHelper.config.Host.ComputeNameCollision = config.ComputeNameCollisionSuffix
+	otherAddress := "10.0.0.1"
+	suffixedName := "UnitTest-1"
+	endpoint := "GameLiftServiceSdkEndpoint"
+	gameLiftMockHelper.clientGameLift.ListComputeResult.ComputeList[0].IpAddress = &otherAddress
+	gameLiftMockHelper.clientGameLift.ListComputeResult.ComputeList = append(gameLiftMockHelper.clientGameLift.ListComputeResult.ComputeList,
+		types.Compute{ComputeName: &suffixedName, IpAddress: &otherAddress, GameLiftServiceSdkEndpoint: &endpoint})
+
+	//act
+	err := gameLiftMockHelper.anywhere.InitSdk(gameLiftMockHelper.ctx)
+
+	//assert
+	assert.Nil(t, err)
+	assert.Equal(t, "UnitTest-2", *gameLiftMockHelper.clientGameLift.RegisterComputeInput.ComputeName)
+	assert.Equal(t, "UnitTest-2", *gameLiftMockHelper.clientGameLift.GetComputeAuthTokenInput.ComputeName)
+	assert.Equal(t, "UnitTest-2", gameLiftMockHelper.gameLiftSdk.ServerParameters.HostID)
+}
+
+func Test_Anywhere_InitSdk_ComputeNameCollision_SameAddress(t *testing.T) {
+	//arrange
+	gameLiftMockHelper := createRegisteringAnywhereMockHelper("", "UnitTest")
+	gameLiftMockHelper.config.Host.ComputeNameCollision = config.ComputeNameCollisionFail
+	address := IPv4Address
+	gameLiftMockHelper.clientGameLift.ListComputeResult.ComputeList[0].IpAddress = &address
+
+	//act
+	err := gameLiftMockHelper.anywhere.InitSdk(gameLiftMockHelper.ctx)
+
+	//assert
+	assert.Nil(t, err)
+	assert.Nil(t, gameLiftMockHelper.clientGameLift.RegisterComputeInput)
+	assert.Equal(t, "UnitTest", gameLiftMockHelper.gameLiftSdk.ServerParameters.HostID)
+}
+
+func createTemplatedAnywhereMockHelper(t *testing.T, appDir string, existingComputes ...types.Compute) AnywhereMockHelper {
+	gameLiftMockHelper := createRegisteringAnywhereMockHelper("", "")
+	gameLiftMockHelper.config.Host.HostName = ""
+	gameLiftMockHelper.config.Host.ComputeNameTemplate = "{{.Hostname}}-{{.Port}}-{{.Index}}"
+	gameLiftMockHelper.config.Host.ComputeNameCollision = config.ComputeNameCollisionFail
+	gameLiftMockHelper.config.Host.GamePort = 37016
+	gameLiftMockHelper.clientGameLift.ListComputeResult.ComputeList = existingComputes
+
+	ctx := context.WithValue(gameLiftMockHelper.ctx, string(constants.ContextKeyAppDir), appDir)
+	a, err := newAnywhere(ctx, gameLiftMockHelper.config, gameLiftMockHelper.gameLiftSdk, gameLiftMockHelper.logger, gameLiftMockHelper.clientProvider, noop.NewMeterProvider().Meter("test"))
+	assert.Nil(t, err)
+	gameLiftMockHelper.anywhere = a
+	gameLiftMockHelper.ctx = ctx
+
+	return gameLiftMockHelper
+}
+
+func Test_Anywhere_InitSdk_ComputeNameTemplate(t *testing.T) {
+	//arrange
+	hostname, err := getHostname()
+	assert.Nil(t, err)
+	appDir := t.TempDir()
+	gameLiftMockHelper := createTemplatedAnywhereMockHelper(t, appDir)
+
+	//act
+	err = gameLiftMockHelper.anywhere.InitSdk(gameLiftMockHelper.ctx)
+
+	//assert
+	assert.Nil(t, err)
+	assert.Equal(t, hostname+"-37016-0", *gameLiftMockHelper.clientGameLift.RegisterComputeInput.ComputeName)
+	identity, err := loadComputeIdentity(appDir, 0)
+	assert.Nil(t, err)
+	assert.True(t, identity.owns(fleetId, hostname+"-37016-0"))
+}
+
+func Test_Anywhere_InitSdk_ComputeNameTemplate_ReusesOwnedCompute(t *testing.T) {
+	//arrange
+	appDir := t.TempDir()
+	identity, _ := loadComputeIdentity(appDir, 0)
+	identity.FleetId = fleetId
+	identity.Template = "{{.Hostname}}-{{.Port}}-{{.Index}}"
+	identity.ComputeName = "Previous-1"
+	assert.Nil(t, identity.save())
+
+	name := "Previous-1"
+	otherAddress := "10.0.0.1"
+	endpoint := "GameLiftServiceSdkEndpoint"
+	gameLiftMockHelper := createTemplatedAnywhereMockHelper(t, appDir,
+		types.Compute{ComputeName: &name, IpAddress: &otherAddress, GameLiftServiceSdkEndpoint: &endpoint})
+
+	//act
+	err := gameLiftMockHelper.anywhere.InitSdk(gameLiftMockHelper.ctx)
+
+	//assert
+	assert.Nil(t, err)
+	assert.Equal(t, 1, gameLiftMockHelper.clientGameLift.DeregisterComputeCalls)
+	assert.Equal(t, "Previous-1", *gameLiftMockHelper.clientGameLift.RegisterComputeInput.ComputeName)
+	assert.Equal(t, IPv4Address, *gameLiftMockHelper.clientGameLift.RegisterComputeInput.IpAddress)
+	assert.Contains(t, gameLiftMockHelper.logBuffer.String(), "compute registered by a previous run has another ip address")
+}
@@ -0,0 +1,151 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package initialiser
+
+import (
+	"context"
+	"log/slog"
+	"os"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting/gamelift/sdk"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	parameterSourceEnvironment = "environment"
+	parameterSourceGenerated   = "generated"
+	parameterSourceUnset       = "unset"
+)
+
+// environmentParameter is a server SDK parameter read from the environment.
+type environmentParameter struct {
+	name   string
+	key    string
+	secret bool // the value is kept out of the logs
+}
+
+// environmentParameters are the parameters InitSDKFromEnvironment reads, in the order they are logged.
+var environmentParameters = []environmentParameter{
+	{name: "WebSocketURL", key: common.EnvironmentKeyWebsocketURL},
+	{name: "ProcessID", key: common.EnvironmentKeyProcessID},
+	{name: "HostID", key: common.EnvironmentKeyHostID},
+	{name: "FleetID", key: common.EnvironmentKeyFleetID},
+	{name: "ComputeType", key: common.EnvironmentKeyComputeType},
+	{name: "AuthToken", key: common.EnvironmentKeyAuthToken, secret: true},
+	{name: "AwsRegion", key: common.EnvironmentKeyAwsRegion},
+	{name: "AccessKey", key: common.EnvironmentKeyAccessKey, secret: true},
+	{name: "SecretKey", key: common.EnvironmentKeySecretKey, secret: true},
+	{name: "SessionToken", key: common.EnvironmentKeySessionToken, secret: true},
+}
+
+type environment struct {
+	sdk    sdk.GameLiftSdk
+	logger *slog.Logger
+}
+
+// InitSdk validates the server parameters provided by the environment, logs where each one came from and
+// initializes the GameLift SDK with them. A process id is generated when the environment does not provide one.
+func (environment *environment) InitSdk(ctx context.Context) error {
+	environment.logger.InfoContext(ctx, "using server SDK parameters from the environment")
+
+	values := make(map[string]string, len(environmentParameters))
+	for _, parameter := range environmentParameters {
+		values[parameter.key] = os.Getenv(parameter.key)
+	}
+
+	sources := make(map[string]string, len(environmentParameters))
+	if len(values[common.EnvironmentKeyProcessID]) == 0 {
+		processId := uuid.New().String()
+		if err := os.Setenv(common.EnvironmentKeyProcessID, processId); err != nil {
+			return errors.Wrapf(err, "unable to set %s environment variable", common.EnvironmentKeyProcessID)
+		}
+		values[common.EnvironmentKeyProcessID] = processId
+		sources[common.EnvironmentKeyProcessID] = parameterSourceGenerated
+	}
+
+	for _, parameter := range environmentParameters {
+		value := values[parameter.key]
+		source, ok := sources[parameter.key]
+		switch {
+		case ok:
+		case len(value) != 0:
+			source = parameterSourceEnvironment
+		default:
+			source = parameterSourceUnset
+		}
+		if parameter.secret && len(value) != 0 {
+			value = "<REDACTED>"
+		}
+		environment.logger.InfoContext(ctx, "server SDK parameter", "parameter", parameter.name, "source", source, "key", parameter.key, "value", value)
+	}
+
+	if err := validateEnvironmentParameters(values); err != nil {
+		return err
+	}
+
+	return environment.sdk.InitSDKFromEnvironment(ctx)
+}
+
+// Stop is a no-op, the SDK connection is not kept up by the wrapper when it is initialized from the environment.
+func (environment *environment) Stop() {
+}
+
+// Close is a no-op, the compute is owned by whatever provided the environment.
+func (environment *environment) Close(ctx context.Context) error {
+	return nil
+}
+
+// validateEnvironmentParameters checks the parameters the server SDK requires, so a missing one is reported
+// with its environment variable.
+func validateEnvironmentParameters(values map[string]string) error {
+	isContainer := values[common.EnvironmentKeyComputeType] == common.ComputeTypeContainer
+	isUsingAuthToken := len(values[common.EnvironmentKeyAuthToken]) != 0
+
+	required := []string{common.EnvironmentKeyWebsocketURL, common.EnvironmentKeyProcessID, common.EnvironmentKeyFleetID}
+	if !isContainer || isUsingAuthToken {
+		required = append(required, common.EnvironmentKeyHostID)
+	}
+	for _, key := range required {
+		if len(values[key]) == 0 {
+			return errors.Errorf("%s must be set to initialize the server SDK from the environment", key)
+		}
+	}
+
+	if isUsingAuthToken {
+		return nil
+	}
+	if len(values[common.EnvironmentKeyAwsRegion]) == 0 {
+		return errors.Errorf("%s or %s must be set to initialize the server SDK from the environment", common.EnvironmentKeyAuthToken, common.EnvironmentKeyAwsRegion)
+	}
+	if !isContainer && (len(values[common.EnvironmentKeyAccessKey]) == 0 || len(values[common.EnvironmentKeySecretKey]) == 0) {
+		return errors.Errorf("%s and %s must be set with %s", common.EnvironmentKeyAccessKey, common.EnvironmentKeySecretKey, common.EnvironmentKeyAwsRegion)
+	}
+	return nil
+}
+
+// useEnvironment reports whether the server SDK parameters are read from the environment. In auto mode, they are
+// when the environment provides the websocket URL, as the server SDK prefers the environment over the parameters
+// it is given anyway.
+func useEnvironment(mode config.SdkInitMode) bool {
+	switch mode {
+	case config.SdkInitEnvironment:
+		return true
+	case config.SdkInitConfig:
+		return false
+	default:
+		return len(os.Getenv(common.EnvironmentKeyWebsocketURL)) != 0
+	}
+}
+
+func newEnvironment(sdk sdk.GameLiftSdk, logger *slog.Logger) Service {
+	return &environment{
+		sdk:    sdk,
+		logger: logger,
+	}
+}
@@ -0,0 +1,162 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package initialiser
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/internal/mocks"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting/gamelift/sdk"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/metric/noop"
+	"golang.org/x/net/context"
+)
+
+type EnvironmentMockHelper struct {
+	logBuffer   *bytes.Buffer
+	gameLiftSdk *mocks.GameLiftSdkMock
+	ctx         context.Context
+	environment Service
+}
+
+func createEnvironmentMockHelper(t *testing.T) EnvironmentMockHelper {
+	logBuffer := bytes.Buffer{}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	t.Cleanup(cancel)
+	logger := slog.New(slog.NewTextHandler(&logBuffer, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	}))
+	gameLiftSdkMock := mocks.GameLiftSdkMock{}
+	return EnvironmentMockHelper{
+		logBuffer:   &logBuffer,
+		gameLiftSdk: &gameLiftSdkMock,
+		ctx:         ctx,
+		environment: newEnvironment(&gameLiftSdkMock, logger),
+	}
+}
+
+// setContainerEnvironment sets the parameters a managed container fleet provides, restoring them after the test.
+func setContainerEnvironment(t *testing.T) {
+	for _, parameter := range environmentParameters {
+		t.Setenv(parameter.key, "")
+	}
+	t.Setenv(common.EnvironmentKeyWebsocketURL, "wss://eu-west-1.api.amazongamelift.com")
+	t.Setenv(common.EnvironmentKeyFleetID, "containerfleet-1234")
+	t.Setenv(common.EnvironmentKeyComputeType, common.ComputeTypeContainer)
+	t.Setenv(common.EnvironmentKeyAwsRegion, "eu-west-1")
+}
+
+func Test_Environment_InitSdk_HappyPath(t *testing.T) {
+	//arrange
+	setContainerEnvironment(t)
+	t.Setenv(common.EnvironmentKeyProcessID, "process-1")
+	t.Setenv(common.EnvironmentKeySessionToken, "session-token-value")
+	helper := createEnvironmentMockHelper(t)
+
+	//act
+	err := helper.environment.InitSdk(helper.ctx)
+
+	//assert
+	assert.NoError(t, err)
+	assert.True(t, helper.gameLiftSdk.InitSDKFromEnvironmentCalled)
+	assert.False(t, helper.gameLiftSdk.InitSdkCalled)
+	assert.Contains(t, helper.logBuffer.String(), "parameter=ProcessID source=environment key=GAMELIFT_SDK_PROCESS_ID value=process-1")
+	assert.Contains(t, helper.logBuffer.String(), "parameter=SessionToken source=environment key=GAMELIFT_SESSION_TOKEN value=<REDACTED>")
+	assert.NotContains(t, helper.logBuffer.String(), "session-token-value")
+}
+
+func Test_Environment_InitSdk_GeneratesProcessId(t *testing.T) {
+	//arrange
+	setContainerEnvironment(t)
+	helper := createEnvironmentMockHelper(t)
+
+	//act
+	err := helper.environment.InitSdk(helper.ctx)
+
+	//assert
+	assert.NoError(t, err)
+	assert.NotEmpty(t, os.Getenv(common.EnvironmentKeyProcessID))
+	assert.Contains(t, helper.logBuffer.String(), "parameter=ProcessID source=generated")
+}
+
+func Test_Environment_InitSdk_MissingFleetId(t *testing.T) {
+	//arrange
+	setContainerEnvironment(t)
+	t.Setenv(common.EnvironmentKeyFleetID, "")
+	helper := createEnvironmentMockHelper(t)
+
+	//act
+	err := helper.environment.InitSdk(helper.ctx)
+
+	//assert
+	assert.ErrorContains(t, err, common.EnvironmentKeyFleetID)
+	assert.False(t, helper.gameLiftSdk.InitSDKFromEnvironmentCalled)
+}
+
+func Test_ValidateEnvironmentParameters_AnywhereRequiresHostId(t *testing.T) {
+	//arrange
+	values := map[string]string{
+		common.EnvironmentKeyWebsocketURL: "wss://eu-west-1.api.amazongamelift.com",
+		common.EnvironmentKeyProcessID:    "process-1",
+		common.EnvironmentKeyFleetID:      "fleet-1234",
+		common.EnvironmentKeyAuthToken:    "auth-token",
+	}
+
+	//act
+	err := validateEnvironmentParameters(values)
+
+	//assert
+	assert.ErrorContains(t, err, common.EnvironmentKeyHostID)
+}
+
+func Test_GetService_Environment(t *testing.T) {
+	tests := []struct {
+		name         string
+		mode         config.SdkInitMode
+		websocketUrl string
+		expected     bool
+	}{
+		{name: "auto with websocket url", mode: config.SdkInitAuto, websocketUrl: "wss://eu-west-1.api.amazongamelift.com", expected: true},
+		{name: "auto without websocket url", mode: config.SdkInitAuto, expected: false},
+		{name: "environment", mode: config.SdkInitEnvironment, expected: true},
+		{name: "config with websocket url", mode: config.SdkInitConfig, websocketUrl: "wss://eu-west-1.api.amazongamelift.com", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			//arrange
+			t.Setenv(common.EnvironmentKeyWebsocketURL, tt.websocketUrl)
+			initialiserMockHelper := createInitialiserMockHelper(&config.Anywhere{SdkInit: tt.mode})
+
+			environmentNewCalled := false
+			environmentNew = func(sdk sdk.GameLiftSdk, logger *slog.Logger) Service {
+				environmentNewCalled = true
+				return initialiserMockHelper.initialiserService
+			}
+			managedNew = func(sdk sdk.GameLiftSdk, logger *slog.Logger) Service {
+				return initialiserMockHelper.initialiserService
+			}
+			t.Cleanup(func() {
+				environmentNew = newEnvironment
+				managedNew = newManaged
+			})
+
+			//act
+			serviceResponse, err := initialiserMockHelper.initialiserServiceFactory.GetService(initialiserMockHelper.ctx, *initialiserMockHelper.config, initialiserMockHelper.gameLiftSdk, initialiserMockHelper.logger, noop.NewMeterProvider().Meter("test"))
+
+			//assert
+			assert.NoError(t, err)
+			assert.NotNil(t, serviceResponse)
+			assert.Equal(t, tt.expected, environmentNewCalled)
+		})
+	}
+}
@@ -0,0 +1,287 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package initialiser
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/constants"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting/gamelift/client"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/gamelift"
+	"github.com/aws/aws-sdk-go-v2/service/gamelift/types"
+	"github.com/pkg/errors"
+)
+
+const fleetCacheFile = "anywhere-fleet.json"
+
+var (
+	fleetIdPattern = regexp.MustCompile(`^fleet-[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+	aliasIdPattern = regexp.MustCompile(`^alias-[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+)
+
+// fleetCache is persisted in the app directory so a restarted wrapper does not look the fleet and location up again.
+type fleetCache struct {
+	Region   string `json:"region"`
+	Fleet    string `json:"fleet"`    // the fleet as configured
+	Location string `json:"location"` // the location as configured
+	FleetId  string `json:"fleetId"`
+
+	path string // empty when the cache is not persisted
+}
+
+// loadFleetCache reads the cached fleet from dir, the returned cache is empty when there is none.
+func loadFleetCache(dir string) (*fleetCache, error) {
+	cache := &fleetCache{}
+	if len(dir) == 0 {
+		return cache, nil
+	}
+
+	cache.path = filepath.Join(dir, fleetCacheFile)
+	b, err := os.ReadFile(cache.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cache, nil
+		}
+		return cache, errors.Wrapf(err, "failed to read fleet cache file '%s'", cache.path)
+	}
+
+	if err := json.Unmarshal(b, cache); err != nil {
+		*cache = fleetCache{path: cache.path}
+		return cache, errors.Wrapf(err, "invalid fleet cache file '%s'", cache.path)
+	}
+
+	return cache, nil
+}
+
+func (cache *fleetCache) save() error {
+	if len(cache.path) == 0 {
+		return nil
+	}
+
+	b, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to encode fleet cache")
+	}
+
+	if err := os.WriteFile(cache.path, b, 0644); err != nil {
+		return errors.Wrapf(err, "failed to write fleet cache file '%s'", cache.path)
+	}
+
+	return nil
+}
+
+// remove deletes the cache file, so a stale fleet is not read again if looking it up fails.
+func (cache *fleetCache) remove() error {
+	if len(cache.path) == 0 {
+		return nil
+	}
+
+	if err := os.Remove(cache.path); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "failed to remove fleet cache file '%s'", cache.path)
+	}
+
+	return nil
+}
+
+// matches reports whether the cache holds the fleet resolved for the configured fleet and location.
+func (cache *fleetCache) matches(region, fleet, location string) bool {
+	return len(cache.FleetId) != 0 && cache.Region == region && cache.Fleet == fleet && cache.Location == location
+}
+
+// idFromArn returns the resource id of an arn such as arn:aws:gamelift:us-west-2:123456789012:fleet/fleet-1234.
+func idFromArn(arn, kind string) (string, error) {
+	parts := strings.Split(arn, "/")
+	if len(parts) != 2 {
+		return "", errors.Errorf("invalid %s arn '%s'", kind, arn)
+	}
+	return parts[1], nil
+}
+
+// resolveFleet returns the id of the fleet and the name of the location the compute is registered in, and
+// whether they were read from the cache. ARNs and fleet ids are used as they are. Fleet names and aliases are
+// looked up, and a location name is checked against the locations of the fleet. Looked up fleets are cached
+// in the app directory, except for aliases, which are resolved on every start as they can be pointed at
+// another fleet. With refresh set, the cache is removed and the fleet is looked up again.
+func resolveFleet(ctx context.Context, cfg *config.Anywhere, clientProvider client.Provider, logger *slog.Logger, refresh bool) (string, string, bool, error) {
+	fleet, location := cfg.Host.Fleet, cfg.Host.Location
+
+	fleetId := fleet
+	if len(cfg.Host.FleetArn) != 0 {
+		var err error
+		if fleetId, err = idFromArn(cfg.Host.FleetArn, "fleet"); err != nil {
+			return "", "", false, err
+		}
+		fleet = cfg.Host.FleetArn
+	}
+
+	if len(cfg.Host.LocationArn) != 0 {
+		var err error
+		if location, err = idFromArn(cfg.Host.LocationArn, "location"); err != nil {
+			return "", "", false, err
+		}
+		if len(cfg.Host.FleetArn) != 0 || fleetIdPattern.MatchString(fleetId) {
+			return fleetId, location, false, nil
+		}
+	}
+
+	if clientProvider == nil {
+		return "", "", false, errors.New("no aws client to look up the anywhere fleet and location")
+	}
+
+	awsConfig, err := clientProvider.GetAwsConfig(ctx)
+	if err != nil {
+		return "", "", false, errors.Wrap(err, "failed to get aws config")
+	}
+
+	aliased := aliasIdPattern.MatchString(fleet)
+	appDir, _ := ctx.Value(string(constants.ContextKeyAppDir)).(string)
+	cache, err := loadFleetCache(appDir)
+	if err != nil {
+		logger.WarnContext(ctx, "failed to load fleet cache, looking the fleet up", "err", err)
+	}
+	if refresh {
+		if err := cache.remove(); err != nil {
+			logger.WarnContext(ctx, "failed to remove fleet cache", "err", err)
+		}
+	} else if !aliased && cache.matches(awsConfig.Region, fleet, location) {
+		logger.DebugContext(ctx, "using cached fleet", "fleet", fleet, "fleetId", cache.FleetId, "location", location)
+		return cache.FleetId, location, true, nil
+	}
+
+	glClient, err := clientProvider.GetGameLift(ctx)
+	if err != nil {
+		return "", "", false, errors.Wrap(err, "failed to get GameLift client")
+	}
+
+	switch {
+	case aliased:
+		if fleetId, err = resolveAlias(ctx, glClient, fleet); err != nil {
+			return "", "", false, err
+		}
+	case !fleetIdPattern.MatchString(fleetId):
+		if fleetId, aliased, err = findFleet(ctx, glClient, fleet); err != nil {
+			return "", "", false, err
+		}
+	}
+
+	if err := checkLocation(ctx, glClient, fleetId, location); err != nil {
+		return "", "", false, err
+	}
+
+	logger.InfoContext(ctx, "resolved anywhere fleet", "fleet", fleet, "fleetId", fleetId, "location", location)
+
+	if !aliased {
+		*cache = fleetCache{Region: awsConfig.Region, Fleet: fleet, Location: location, FleetId: fleetId, path: cache.path}
+		if err := cache.save(); err != nil {
+			logger.WarnContext(ctx, "failed to save fleet cache", "err", err)
+		}
+	}
+
+	return fleetId, location, false, nil
+}
+
+// findFleet returns the id of the Anywhere fleet named name or, when there is none, of the fleet of the alias
+// named name. aliased is set when the fleet was found through an alias.
+func findFleet(ctx context.Context, glClient client.GameLift, name string) (fleetId string, aliased bool, err error) {
+	var fleetIds []string
+	fleets := gamelift.NewDescribeFleetAttributesPaginator(glClient, &gamelift.DescribeFleetAttributesInput{})
+	for fleets.HasMorePages() {
+		page, err := fleets.NextPage(ctx)
+		if err != nil {
+			return "", false, errors.Wrap(err, "failed to describe fleets")
+		}
+		for _, attributes := range page.FleetAttributes {
+			if aws.ToString(attributes.Name) == name && attributes.ComputeType == types.ComputeTypeAnywhere {
+				fleetIds = append(fleetIds, aws.ToString(attributes.FleetId))
+			}
+		}
+	}
+
+	switch len(fleetIds) {
+	case 0:
+	case 1:
+		return fleetIds[0], false, nil
+	default:
+		return "", false, errors.Errorf("found several anywhere fleets named '%s' (%s), use the fleet id", name, strings.Join(fleetIds, ", "))
+	}
+
+	var aliasIds []string
+	aliases := gamelift.NewListAliasesPaginator(glClient, &gamelift.ListAliasesInput{Name: aws.String(name)})
+	for aliases.HasMorePages() {
+		page, err := aliases.NextPage(ctx)
+		if err != nil {
+			return "", false, errors.Wrap(err, "failed to list aliases")
+		}
+		for _, alias := range page.Aliases {
+			if aws.ToString(alias.Name) == name {
+				aliasIds = append(aliasIds, aws.ToString(alias.AliasId))
+			}
+		}
+	}
+
+	switch len(aliasIds) {
+	case 0:
+		return "", false, errors.Errorf("no anywhere fleet or alias named '%s' found", name)
+	case 1:
+		fleetId, err = resolveAlias(ctx, glClient, aliasIds[0])
+		return fleetId, true, err
+	default:
+		return "", false, errors.Errorf("found several aliases named '%s' (%s), use the alias id", name, strings.Join(aliasIds, ", "))
+	}
+}
+
+// resolveAlias returns the id of the fleet the alias points to.
+func resolveAlias(ctx context.Context, glClient client.GameLift, aliasId string) (string, error) {
+	resp, err := glClient.ResolveAlias(ctx, &gamelift.ResolveAliasInput{AliasId: aws.String(aliasId)})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to resolve alias '%s'", aliasId)
+	}
+	if len(aws.ToString(resp.FleetId)) == 0 {
+		return "", errors.Errorf("alias '%s' does not point to a fleet", aliasId)
+	}
+	return aws.ToString(resp.FleetId), nil
+}
+
+// checkLocation returns an error listing the locations of the fleet when location is not one of them.
+func checkLocation(ctx context.Context, glClient client.GameLift, fleetId, location string) error {
+	var locations []string
+	pages := gamelift.NewDescribeFleetLocationAttributesPaginator(glClient, &gamelift.DescribeFleetLocationAttributesInput{
+		FleetId: aws.String(fleetId),
+	})
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "failed to describe the locations of fleet '%s'", fleetId)
+		}
+		for _, attributes := range page.LocationAttributes {
+			if attributes.LocationState != nil {
+				locations = append(locations, aws.ToString(attributes.LocationState.Location))
+			}
+		}
+	}
+
+	if !slices.Contains(locations, location) {
+		return errors.Errorf("fleet '%s' has no location '%s', its locations are: %s", fleetId, location, strings.Join(locations, ", "))
+	}
+
+	return nil
+}
+
+// isStaleFleetError reports whether a call failed because the fleet or location it was given no longer exist.
+func isStaleFleetError(err error) bool {
+	var notFound *types.NotFoundException
+	var invalidRequest *types.InvalidRequestException
+	return errors.As(err, &notFound) || errors.As(err, &invalidRequest)
+}
@@ -0,0 +1,161 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package initialiser
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/constants"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting/gamelift/client"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/gamelift"
+	"github.com/aws/aws-sdk-go-v2/service/gamelift/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const locationName = "custom-gamelift"
+
+func createFleetClientProvider() *client.ClientProviderMock {
+	return &client.ClientProviderMock{
+		GetAwsConfigResponse: aws.Config{Region: "eu-west-2"},
+		GetGameLiftResponse: &client.ClientGameLiftMock{
+			DescribeFleetAttributesResult: &gamelift.DescribeFleetAttributesOutput{
+				FleetAttributes: []types.FleetAttributes{
+					{FleetId: aws.String("fleet-other"), Name: aws.String("my-fleet"), ComputeType: types.ComputeTypeEc2},
+					{FleetId: aws.String(fleetId), Name: aws.String("my-fleet"), ComputeType: types.ComputeTypeAnywhere},
+				},
+			},
+			DescribeFleetLocationAttributesResult: &gamelift.DescribeFleetLocationAttributesOutput{
+				LocationAttributes: []types.LocationAttributes{
+					{LocationState: &types.LocationState{Location: aws.String(locationName)}},
+				},
+			},
+			ListAliasesResult: &gamelift.ListAliasesOutput{},
+		},
+	}
+}
+
+func Test_ResolveFleet_Arns(t *testing.T) {
+	//arrange
+	cfg := &config.Anywhere{Host: config.AnywhereHostConfig{FleetArn: fleetArn, LocationArn: locationArn}}
+
+	//act
+	resolvedFleetId, location, _, err := resolveFleet(context.Background(), cfg, nil, slog.Default(), false)
+
+	//assert
+	assert.NoError(t, err)
+	assert.Equal(t, fleetId, resolvedFleetId)
+	assert.Equal(t, locationName, location)
+}
+
+func Test_ResolveFleet_Name_Cached(t *testing.T) {
+	//arrange
+	ctx := context.WithValue(context.Background(), string(constants.ContextKeyAppDir), t.TempDir())
+	cfg := &config.Anywhere{Host: config.AnywhereHostConfig{Fleet: "my-fleet", Location: locationName}}
+	clientProvider := createFleetClientProvider()
+
+	//act
+	resolvedFleetId, location, cached, err := resolveFleet(ctx, cfg, clientProvider, slog.Default(), false)
+	cachedFleetId, _, secondCached, cachedErr := resolveFleet(ctx, cfg, clientProvider, slog.Default(), false)
+
+	//assert
+	assert.NoError(t, err)
+	assert.NoError(t, cachedErr)
+	assert.Equal(t, fleetId, resolvedFleetId)
+	assert.Equal(t, fleetId, cachedFleetId)
+	assert.Equal(t, locationName, location)
+	assert.False(t, cached)
+	assert.True(t, secondCached)
+	assert.Equal(t, 1, clientProvider.GetGameLiftResponse.DescribeFleetAttributesCalls)
+	assert.Equal(t, fleetId, *clientProvider.GetGameLiftResponse.DescribeFleetLocationAttributesInput.FleetId)
+}
+
+func Test_ResolveFleet_Refresh(t *testing.T) {
+	//arrange
+	appDir := t.TempDir()
+	ctx := context.WithValue(context.Background(), string(constants.ContextKeyAppDir), appDir)
+	cfg := &config.Anywhere{Host: config.AnywhereHostConfig{Fleet: "my-fleet", Location: locationName}}
+	clientProvider := createFleetClientProvider()
+	stale := &fleetCache{Region: "eu-west-2", Fleet: "my-fleet", Location: locationName,
+		FleetId: "fleet-00000000-0000-0000-0000-000000000000", path: filepath.Join(appDir, fleetCacheFile)}
+	assert.NoError(t, stale.save())
+
+	//act
+	resolvedFleetId, _, cached, err := resolveFleet(ctx, cfg, clientProvider, slog.Default(), true)
+	cache, cacheErr := loadFleetCache(appDir)
+
+	//assert
+	assert.NoError(t, err)
+	assert.NoError(t, cacheErr)
+	assert.False(t, cached)
+	assert.Equal(t, fleetId, resolvedFleetId)
+	assert.Equal(t, fleetId, cache.FleetId)
+	assert.Equal(t, 1, clientProvider.GetGameLiftResponse.DescribeFleetAttributesCalls)
+}
+
+func Test_ResolveFleet_NameLikeId(t *testing.T) {
+	//arrange
+	cfg := &config.Anywhere{Host: config.AnywhereHostConfig{Fleet: "fleet-blue", Location: locationName}}
+	clientProvider := createFleetClientProvider()
+	clientProvider.GetGameLiftResponse.DescribeFleetAttributesResult.FleetAttributes[1].Name = aws.String("fleet-blue")
+
+	//act
+	resolvedFleetId, _, _, err := resolveFleet(context.Background(), cfg, clientProvider, slog.Default(), false)
+
+	//assert
+	assert.NoError(t, err)
+	assert.Equal(t, fleetId, resolvedFleetId)
+	assert.Equal(t, 1, clientProvider.GetGameLiftResponse.DescribeFleetAttributesCalls)
+}
+
+func Test_ResolveFleet_AliasName(t *testing.T) {
+	//arrange
+	ctx := context.WithValue(context.Background(), string(constants.ContextKeyAppDir), t.TempDir())
+	cfg := &config.Anywhere{Host: config.AnywhereHostConfig{Fleet: "live", LocationArn: locationArn}}
+	clientProvider := createFleetClientProvider()
+	clientProvider.GetGameLiftResponse.ListAliasesResult = &gamelift.ListAliasesOutput{
+		Aliases: []types.Alias{{AliasId: aws.String("alias-1234"), Name: aws.String("live")}},
+	}
+	clientProvider.GetGameLiftResponse.ResolveAliasResult = &gamelift.ResolveAliasOutput{FleetId: aws.String(fleetId)}
+
+	//act
+	resolvedFleetId, _, _, err := resolveFleet(ctx, cfg, clientProvider, slog.Default(), false)
+	_, _, cached, secondErr := resolveFleet(ctx, cfg, clientProvider, slog.Default(), false)
+
+	//assert
+	assert.NoError(t, err)
+	assert.NoError(t, secondErr)
+	assert.Equal(t, fleetId, resolvedFleetId)
+	assert.False(t, cached)
+	assert.Equal(t, "alias-1234", *clientProvider.GetGameLiftResponse.ResolveAliasInput.AliasId)
+	assert.Equal(t, 2, clientProvider.GetGameLiftResponse.DescribeFleetAttributesCalls)
+}
+
+func Test_ResolveFleet_NotFound(t *testing.T) {
+	//arrange
+	cfg := &config.Anywhere{Host: config.AnywhereHostConfig{Fleet: "missing", Location: locationName}}
+
+	//act
+	_, _, _, err := resolveFleet(context.Background(), cfg, createFleetClientProvider(), slog.Default(), false)
+
+	//assert
+	assert.ErrorContains(t, err, "no anywhere fleet or alias named 'missing' found")
+}
+
+func Test_ResolveFleet_UnknownLocation(t *testing.T) {
+	//arrange
+	cfg := &config.Anywhere{Host: config.AnywhereHostConfig{Fleet: fleetId, Location: "custom-other"}}
+
+	//act
+	_, _, _, err := resolveFleet(context.Background(), cfg, createFleetClientProvider(), slog.Default(), false)
+
+	//assert
+	assert.ErrorContains(t, err, "has no location 'custom-other', its locations are: custom-gamelift")
+}
@@ -0,0 +1,149 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package initialiser
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultComputeNameTemplate = "{{.Hostname}}"
+	computeIdentityFileFormat  = "compute-identity-%d.json"
+	maxComputeNameLength       = 128
+	maxComputeNameSuffix       = 99
+)
+
+var invalidComputeNameCharacters = regexp.MustCompile("[^a-zA-Z0-9\\-]")
+
+// computeNameData is the data available to the compute name template.
+type computeNameData struct {
+	Hostname   string
+	Port       int
+	Index      int
+	InstanceId string
+}
+
+// computeIdentity is persisted in the app directory so a restarted wrapper keeps using the compute it registered.
+type computeIdentity struct {
+	InstanceId  string `json:"instanceId"`
+	FleetId     string `json:"fleetId"`
+	Template    string `json:"template"`
+	ComputeName string `json:"computeName"` // empty until a compute was registered or found under the name
+
+	path string // empty when the identity is not persisted
+}
+
+// loadComputeIdentity reads the identity of the wrapper instance from dir, creating a new one if there is none.
+// The returned identity is always usable, an error only means the stored identity could not be read.
+func loadComputeIdentity(dir string, index int) (*computeIdentity, error) {
+	identity := &computeIdentity{}
+	if len(dir) != 0 {
+		identity.path = filepath.Join(dir, fmt.Sprintf(computeIdentityFileFormat, index))
+	}
+
+	var err error
+	if len(identity.path) != 0 {
+		var b []byte
+		if b, err = os.ReadFile(identity.path); err == nil {
+			if err = json.Unmarshal(b, identity); err != nil {
+				err = errors.Wrapf(err, "invalid compute identity file '%s'", identity.path)
+			}
+		} else if os.IsNotExist(err) {
+			err = nil
+		} else {
+			err = errors.Wrapf(err, "failed to read compute identity file '%s'", identity.path)
+		}
+	}
+
+	if _, parseErr := uuid.Parse(identity.InstanceId); parseErr != nil {
+		*identity = computeIdentity{InstanceId: uuid.New().String(), path: identity.path}
+	}
+
+	return identity, err
+}
+
+func (identity *computeIdentity) save() error {
+	if len(identity.path) == 0 {
+		return nil
+	}
+
+	b, err := json.MarshalIndent(identity, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to encode compute identity")
+	}
+
+	if err := os.WriteFile(identity.path, b, 0644); err != nil {
+		return errors.Wrapf(err, "failed to write compute identity file '%s'", identity.path)
+	}
+
+	return nil
+}
+
+// owns reports whether this wrapper instance registered the compute in a previous run.
+func (identity *computeIdentity) owns(fleetId, computeName string) bool {
+	return identity != nil && len(identity.ComputeName) != 0 &&
+		identity.FleetId == fleetId && identity.ComputeName == computeName
+}
+
+// computeName returns the name stored for the fleet and template, or renders the template.
+func (identity *computeIdentity) computeName(fleetId, tmpl string, data computeNameData) (string, error) {
+	if identity.FleetId == fleetId && identity.Template == tmpl && len(identity.ComputeName) != 0 {
+		return identity.ComputeName, nil
+	}
+
+	name, err := renderComputeName(tmpl, data)
+	if err != nil {
+		return "", err
+	}
+
+	identity.FleetId = fleetId
+	identity.Template = tmpl
+	identity.ComputeName = ""
+	return name, nil
+}
+
+func renderComputeName(tmpl string, data computeNameData) (string, error) {
+	t, err := template.New("compute-name").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid compute name template '%s'", tmpl)
+	}
+
+	var sb strings.Builder
+	if err := t.Execute(&sb, data); err != nil {
+		return "", errors.Wrapf(err, "failed to render compute name template '%s'", tmpl)
+	}
+
+	name := sanitizeComputeName(sb.String(), maxComputeNameLength)
+	if len(name) == 0 {
+		return "", errors.Errorf("compute name template '%s' rendered an empty name", tmpl)
+	}
+
+	return name, nil
+}
+
+// sanitizeComputeName ensures that the name complies with the string requirements for RegisterCompute.
+func sanitizeComputeName(name string, maxLength int) string {
+	name = invalidComputeNameCharacters.ReplaceAllString(name, "")
+	if len(name) > maxLength {
+		name = name[:maxLength]
+	}
+	return name
+}
+
+// suffixComputeName appends n to name, truncating the name to keep it within the allowed length.
+func suffixComputeName(name string, n int) string {
+	suffix := fmt.Sprintf("-%d", n)
+	return sanitizeComputeName(name, maxComputeNameLength-len(suffix)) + suffix
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package initialiser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RenderComputeName(t *testing.T) {
+	//act
+	name, err := renderComputeName("{{.Hostname}}-{{.Port}}-{{.Index}}", computeNameData{Hostname: "my.host", Port: 37016, Index: 2})
+
+	//assert
+	assert.Nil(t, err)
+	assert.Equal(t, "myhost-37016-2", name)
+}
+
+func Test_RenderComputeName_InvalidTemplate(t *testing.T) {
+	//act
+	_, err := renderComputeName("{{.Hostname", computeNameData{})
+
+	//assert
+	assert.ErrorContains(t, err, "invalid compute name template")
+}
+
+func Test_RenderComputeName_UnknownField(t *testing.T) {
+	//act
+	_, err := renderComputeName("{{.Unknown}}", computeNameData{})
+
+	//assert
+	assert.ErrorContains(t, err, "failed to render compute name template")
+}
+
+func Test_RenderComputeName_Empty(t *testing.T) {
+	//act
+	_, err := renderComputeName("...", computeNameData{})
+
+	//assert
+	assert.ErrorContains(t, err, "rendered an empty name")
+}
+
+func Test_SuffixComputeName_Truncates(t *testing.T) {
+	//act
+	name := suffixComputeName(strings.Repeat("a", maxComputeNameLength), 12)
+
+	//assert
+	assert.Len(t, name, maxComputeNameLength)
+	assert.True(t, strings.HasSuffix(name, "a-12"))
+}
+
+func Test_ComputeIdentity_SaveAndLoad(t *testing.T) {
+	//arrange
+	dir := t.TempDir()
+	identity, err := loadComputeIdentity(dir, 1)
+	assert.Nil(t, err)
+	identity.FleetId = fleetId
+	identity.Template = defaultComputeNameTemplate
+	identity.ComputeName = "UnitTest-1"
+
+	//act
+	err = identity.save()
+	loaded, loadErr := loadComputeIdentity(dir, 1)
+
+	//assert
+	assert.Nil(t, err)
+	assert.Nil(t, loadErr)
+	assert.FileExists(t, filepath.Join(dir, "compute-identity-1.json"))
+	assert.Equal(t, identity.InstanceId, loaded.InstanceId)
+	assert.True(t, loaded.owns(fleetId, "UnitTest-1"))
+	assert.False(t, loaded.owns("other-fleet", "UnitTest-1"))
+}
+
+func Test_ComputeIdentity_Load_Invalid(t *testing.T) {
+	//arrange
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "compute-identity-0.json"), []byte("{"), 0644))
+
+	//act
+	identity, err := loadComputeIdentity(dir, 0)
+
+	//assert
+	assert.ErrorContains(t, err, "invalid compute identity file")
+	assert.NotEmpty(t, identity.InstanceId)
+}
+
+func Test_ComputeIdentity_ComputeName(t *testing.T) {
+	//arrange
+	identity := &computeIdentity{InstanceId: "id", FleetId: fleetId, Template: defaultComputeNameTemplate, ComputeName: "UnitTest-1"}
+
+	//act
+	stored, storedErr := identity.computeName(fleetId, defaultComputeNameTemplate, computeNameData{Hostname: "UnitTest"})
+	rendered, renderedErr := identity.computeName(fleetId, "{{.Hostname}}-{{.InstanceId}}", computeNameData{Hostname: "UnitTest", InstanceId: "id"})
+
+	//assert
+	assert.Nil(t, storedErr)
+	assert.Equal(t, "UnitTest-1", stored)
+	assert.Nil(t, renderedErr)
+	assert.Equal(t, "UnitTest-id", rendered)
+	assert.Empty(t, identity.ComputeName)
+}
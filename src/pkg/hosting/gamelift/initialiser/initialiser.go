@@ -13,17 +13,23 @@ import (
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting/gamelift/client"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting/gamelift/sdk"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/metric"
 )
 
 // Service defines the interface for hosting option initialization and management operations.
 type Service interface {
 	// InitSdk initializes the GameLift SDK for the server environment.
 	InitSdk(ctx context.Context) error
+	// Stop ends the background work that keeps the SDK connected, so nothing reconnects it while the process ends.
+	Stop()
+	// Close releases anything set up for the server environment, such as a registered compute.
+	Close(ctx context.Context) error
 }
 
 var (
-	anywhereNew = newAnywhere
-	managedNew  = newManaged
+	anywhereNew    = newAnywhere
+	managedNew     = newManaged
+	environmentNew = newEnvironment
 )
 
 type InitialiserServiceFactory struct {
@@ -36,17 +42,27 @@ type InitialiserServiceFactory struct {
 //   - anywhere: GameLift Anywhere configuration
 //   - gameLiftSdk: GameLift SDK instance
 //   - logger: Logger for operations
+//   - meter: Meter for the token and connection metrics
 //
 // Returns:
 //   - Service: Configured hosting service
 //   - error: Any error encountered during service creation
-func (initialiserServiceFactory *InitialiserServiceFactory) GetService(ctx context.Context, anywhere config.Anywhere, gameLiftSdk sdk.GameLiftSdk, logger *slog.Logger) (Service, error) {
+func (initialiserServiceFactory *InitialiserServiceFactory) GetService(ctx context.Context, anywhere config.Anywhere, gameLiftSdk sdk.GameLiftSdk, logger *slog.Logger, meter metric.Meter) (Service, error) {
 
-	if len(anywhere.Host.FleetArn) != 0 {
+	if useEnvironment(anywhere.SdkInit) {
+		if isAnywhere(anywhere) {
+			logger.WarnContext(ctx, "the server SDK parameters are read from the environment, the Anywhere configuration is ignored", "sdkInit", anywhere.SdkInit)
+		}
+		return environmentNew(gameLiftSdk, logger), nil
+	}
+
+	if isAnywhere(anywhere) {
 		var clientProvider client.Provider
 		var err error
-		// Only initialize the clientProvider if either ServiceSdkEndpoint or AuthToken are undefined
-		if len(anywhere.Host.ServiceSdkEndpoint) == 0 || len(anywhere.Host.AuthToken) == 0 {
+		// Only initialize the clientProvider if either ServiceSdkEndpoint or AuthToken are undefined,
+		// or if the fleet or location are given by name and need to be looked up
+		if len(anywhere.Host.ServiceSdkEndpoint) == 0 || len(anywhere.Host.AuthToken) == 0 ||
+			len(anywhere.Host.Fleet) != 0 || len(anywhere.Host.Location) != 0 {
 			clientProvider, err = client.NewProvider(anywhere, logger)
 			if err != nil {
 				return nil, err
@@ -57,8 +73,13 @@ func (initialiserServiceFactory *InitialiserServiceFactory) GetService(ctx conte
 			}
 		}
 
-		return anywhereNew(ctx, &anywhere, gameLiftSdk, logger, clientProvider)
+		return anywhereNew(ctx, &anywhere, gameLiftSdk, logger, clientProvider, meter)
 	}
 
 	return managedNew(gameLiftSdk, logger), nil
 }
+
+// isAnywhere reports whether the configuration targets an Anywhere fleet, by arn or by name.
+func isAnywhere(anywhere config.Anywhere) bool {
+	return len(anywhere.Host.FleetArn) != 0 || len(anywhere.Host.Fleet) != 0
+}
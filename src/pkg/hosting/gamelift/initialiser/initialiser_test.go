@@ -16,6 +16,8 @@ import (
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting/gamelift/client"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting/gamelift/sdk"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
 	"golang.org/x/net/context"
 )
 
@@ -66,7 +68,7 @@ func Test_GetService_HappyPath_Managed(t *testing.T) {
 	}
 
 	//act
-	serviceResponse, err := initialiserMockHelper.initialiserServiceFactory.GetService(initialiserMockHelper.ctx, *initialiserMockHelper.config, initialiserMockHelper.gameLiftSdk, initialiserMockHelper.logger)
+	serviceResponse, err := initialiserMockHelper.initialiserServiceFactory.GetService(initialiserMockHelper.ctx, *initialiserMockHelper.config, initialiserMockHelper.gameLiftSdk, initialiserMockHelper.logger, noop.NewMeterProvider().Meter("test"))
 
 	//assert
 	assert.NotNil(t, serviceResponse)
@@ -95,14 +97,14 @@ func Test_GetService_HappyPath_Anywhere(t *testing.T) {
 	initialiserMockHelper := createInitialiserMockHelper(&anywhereConfig)
 
 	anywhereNewCalled := false
-	anywhereNew = func(ctx context.Context, cfg *config.Anywhere, gl sdk.GameLiftSdk, logger *slog.Logger, clientProvider client.Provider) (Service, error) {
+	anywhereNew = func(ctx context.Context, cfg *config.Anywhere, gl sdk.GameLiftSdk, logger *slog.Logger, clientProvider client.Provider, meter metric.Meter) (Service, error) {
 		anywhereNewCalled = true
 		assert.Same(t, initialiserMockHelper.gameLiftSdk, gl)
 		return initialiserMockHelper.initialiserService, nil
 	}
 
 	//act
-	serviceResponse, err := initialiserMockHelper.initialiserServiceFactory.GetService(initialiserMockHelper.ctx, *initialiserMockHelper.config, initialiserMockHelper.gameLiftSdk, initialiserMockHelper.logger)
+	serviceResponse, err := initialiserMockHelper.initialiserServiceFactory.GetService(initialiserMockHelper.ctx, *initialiserMockHelper.config, initialiserMockHelper.gameLiftSdk, initialiserMockHelper.logger, noop.NewMeterProvider().Meter("test"))
 
 	//assert
 	assert.NotNil(t, serviceResponse)
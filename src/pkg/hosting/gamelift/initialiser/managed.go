@@ -27,6 +27,15 @@ func (managed *managed) InitSdk(ctx context.Context) error {
 	return managed.sdk.InitSDK(ctx, params)
 }
 
+// Stop is a no-op, the SDK connection of a managed compute is not kept up by the wrapper.
+func (managed *managed) Stop() {
+}
+
+// Close is a no-op, managed computes are owned by Amazon GameLift.
+func (managed *managed) Close(ctx context.Context) error {
+	return nil
+}
+
 func newManaged(sdk sdk.GameLiftSdk, logger *slog.Logger) Service {
 	a := &managed{
 		sdk:    sdk,
@@ -10,12 +10,17 @@ import (
 
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting/gamelift/sdk"
+	"go.opentelemetry.io/otel/metric"
 	"golang.org/x/net/context"
 )
 
 type InitialiserServiceMock struct {
 	InitSdkError  error
 	InitSdkCalled bool
+	CloseError    error
+	CloseCalled   bool
+	StopCalled    bool
+	OnStop        func()
 }
 
 func (initialiserServiceMock *InitialiserServiceMock) InitSdk(ctx context.Context) error {
@@ -23,11 +28,23 @@ func (initialiserServiceMock *InitialiserServiceMock) InitSdk(ctx context.Contex
 	return initialiserServiceMock.InitSdkError
 }
 
+func (initialiserServiceMock *InitialiserServiceMock) Stop() {
+	initialiserServiceMock.StopCalled = true
+	if initialiserServiceMock.OnStop != nil {
+		initialiserServiceMock.OnStop()
+	}
+}
+
+func (initialiserServiceMock *InitialiserServiceMock) Close(ctx context.Context) error {
+	initialiserServiceMock.CloseCalled = true
+	return initialiserServiceMock.CloseError
+}
+
 type InitialiserServiceFactoryMock struct {
 	GetServiceResponse Service
 	GetServiceError    error
 }
 
-func (initialiserServiceFactoryMock *InitialiserServiceFactoryMock) GetService(ctx context.Context, anywhere config.Anywhere, gameLiftSdk sdk.GameLiftSdk, logger *slog.Logger) (Service, error) {
+func (initialiserServiceFactoryMock *InitialiserServiceFactoryMock) GetService(ctx context.Context, anywhere config.Anywhere, gameLiftSdk sdk.GameLiftSdk, logger *slog.Logger, meter metric.Meter) (Service, error) {
 	return initialiserServiceFactoryMock.GetServiceResponse, initialiserServiceFactoryMock.GetServiceError
 }
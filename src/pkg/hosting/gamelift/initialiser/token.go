@@ -0,0 +1,248 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package initialiser
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting/gamelift/client"
+	"github.com/aws/aws-sdk-go-v2/service/gamelift"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	tokenRefreshMargin = 5 * time.Minute
+	tokenRetryDelay    = 5 * time.Second
+	tokenMaxRetryDelay = time.Minute
+)
+
+var (
+	resultSuccess = attribute.String("result", "success")
+	resultFailure = attribute.String("result", "failure")
+)
+
+// tokenManager keeps a valid compute auth token for an Anywhere compute, refreshing it ahead of
+// its expiry, and re-establishes the SDK connection with a fresh token when the connection is lost.
+type tokenManager struct {
+	computeName string
+	fleetId     string
+	glClient    client.GameLift // nil when the token was provided, it is then never refreshed
+	reconnect   func(ctx context.Context, token string) error
+	logger      *slog.Logger
+	refreshes   metric.Int64Counter
+	reconnects  metric.Int64Counter
+
+	refreshMargin time.Duration
+	retryDelay    time.Duration
+	maxRetryDelay time.Duration
+	now           func() time.Time
+
+	lost     chan struct{}
+	stop     chan struct{}
+	stopOnce sync.Once
+
+	mutex     sync.Mutex
+	token     string
+	expiresAt time.Time
+	refreshAt time.Time
+}
+
+// fetch requests a new auth token and schedules its refresh.
+func (tokenManager *tokenManager) fetch(ctx context.Context) (string, error) {
+	if tokenManager.glClient == nil {
+		return tokenManager.current(), nil
+	}
+
+	output, err := tokenManager.glClient.GetComputeAuthToken(ctx, &gamelift.GetComputeAuthTokenInput{
+		ComputeName: &tokenManager.computeName,
+		FleetId:     &tokenManager.fleetId,
+	})
+	if err == nil && (output == nil || output.AuthToken == nil) {
+		err = errors.New("no auth token returned")
+	}
+	if err != nil {
+		tokenManager.refreshes.Add(ctx, 1, metric.WithAttributes(resultFailure))
+		return "", errors.Wrap(err, "failed to get Amazon GameLift Anywhere token")
+	}
+
+	var expiresAt time.Time
+	if output.ExpirationTimestamp != nil {
+		expiresAt = *output.ExpirationTimestamp
+	}
+	tokenManager.set(*output.AuthToken, expiresAt)
+	tokenManager.refreshes.Add(ctx, 1, metric.WithAttributes(resultSuccess))
+
+	return *output.AuthToken, nil
+}
+
+// set stores a token and schedules its refresh ahead of the expiry, or halfway through
+// its lifetime for tokens that live shorter than twice the refresh margin.
+func (tokenManager *tokenManager) set(token string, expiresAt time.Time) {
+	tokenManager.mutex.Lock()
+	defer tokenManager.mutex.Unlock()
+
+	tokenManager.token = token
+	tokenManager.expiresAt = expiresAt
+	tokenManager.refreshAt = time.Time{}
+	if !expiresAt.IsZero() {
+		now := tokenManager.now()
+		margin := min(tokenManager.refreshMargin, expiresAt.Sub(now)/2)
+		// a token returned already expired is not refreshed again straight away
+		tokenManager.refreshAt = expiresAt.Add(-margin)
+		if earliest := now.Add(tokenManager.retryDelay); tokenManager.refreshAt.Before(earliest) {
+			tokenManager.refreshAt = earliest
+		}
+	}
+}
+
+func (tokenManager *tokenManager) current() string {
+	tokenManager.mutex.Lock()
+	defer tokenManager.mutex.Unlock()
+	return tokenManager.token
+}
+
+// untilRefresh returns how long to wait before the next refresh, false if the token is never refreshed.
+func (tokenManager *tokenManager) untilRefresh() (time.Duration, bool) {
+	tokenManager.mutex.Lock()
+	defer tokenManager.mutex.Unlock()
+
+	if tokenManager.glClient == nil || tokenManager.refreshAt.IsZero() {
+		return 0, false
+	}
+	return max(tokenManager.refreshAt.Sub(tokenManager.now()), 0), true
+}
+
+// connectionLost requests the connection to be re-established, it never blocks.
+func (tokenManager *tokenManager) connectionLost() {
+	select {
+	case tokenManager.lost <- struct{}{}:
+	default:
+	}
+}
+
+// run refreshes the token and re-establishes lost connections until the context is done or the manager is closed.
+func (tokenManager *tokenManager) run(ctx context.Context) {
+	var retry <-chan time.Time
+	refreshDelay := tokenManager.retryDelay
+	reconnectDelay := tokenManager.retryDelay
+
+	for {
+		var refresh <-chan time.Time
+		if d, ok := tokenManager.untilRefresh(); ok {
+			refresh = time.After(d)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-tokenManager.stop:
+			return
+		case <-refresh:
+			if err := tokenManager.refresh(ctx); err != nil {
+				// push the refresh back so a failing call is not retried in a tight loop
+				tokenManager.postpone(refreshDelay)
+				refreshDelay = min(refreshDelay*2, tokenManager.maxRetryDelay)
+				continue
+			}
+			refreshDelay = tokenManager.retryDelay
+		case <-tokenManager.lost:
+			retry = nil
+			if err := tokenManager.reestablish(ctx); err != nil {
+				retry = time.After(reconnectDelay)
+				reconnectDelay = min(reconnectDelay*2, tokenManager.maxRetryDelay)
+				continue
+			}
+			reconnectDelay = tokenManager.retryDelay
+		case <-retry:
+			retry = nil
+			tokenManager.connectionLost()
+		}
+	}
+}
+
+func (tokenManager *tokenManager) refresh(ctx context.Context) error {
+	tokenManager.logger.DebugContext(ctx, "refreshing compute auth token", "computeName", tokenManager.computeName)
+	if _, err := tokenManager.fetch(ctx); err != nil {
+		tokenManager.logger.WarnContext(ctx, "failed to refresh compute auth token", "computeName", tokenManager.computeName, "err", err)
+		return err
+	}
+
+	tokenManager.mutex.Lock()
+	expiresAt := tokenManager.expiresAt
+	tokenManager.mutex.Unlock()
+
+	tokenManager.logger.InfoContext(ctx, "refreshed compute auth token", "computeName", tokenManager.computeName, "expiresAt", expiresAt)
+	return nil
+}
+
+func (tokenManager *tokenManager) postpone(d time.Duration) {
+	tokenManager.mutex.Lock()
+	defer tokenManager.mutex.Unlock()
+	tokenManager.refreshAt = tokenManager.now().Add(d)
+}
+
+// reestablish reconnects the SDK, with a new token unless the token was provided.
+func (tokenManager *tokenManager) reestablish(ctx context.Context) error {
+	tokenManager.logger.WarnContext(ctx, "lost connection to Amazon GameLift, reconnecting", "computeName", tokenManager.computeName)
+
+	token, err := tokenManager.fetch(ctx)
+	if err != nil {
+		tokenManager.logger.ErrorContext(ctx, "failed to get a compute auth token to reconnect", "computeName", tokenManager.computeName, "err", err)
+		tokenManager.reconnects.Add(ctx, 1, metric.WithAttributes(resultFailure))
+		return err
+	}
+
+	if err := tokenManager.reconnect(ctx, token); err != nil {
+		tokenManager.logger.ErrorContext(ctx, "failed to reconnect to Amazon GameLift", "computeName", tokenManager.computeName, "err", err)
+		tokenManager.reconnects.Add(ctx, 1, metric.WithAttributes(resultFailure))
+		return err
+	}
+
+	tokenManager.logger.InfoContext(ctx, "reconnected to Amazon GameLift", "computeName", tokenManager.computeName)
+	tokenManager.reconnects.Add(ctx, 1, metric.WithAttributes(resultSuccess))
+	return nil
+}
+
+func (tokenManager *tokenManager) close() {
+	tokenManager.stopOnce.Do(func() {
+		close(tokenManager.stop)
+	})
+}
+
+func newTokenManager(computeName, fleetId string, glClient client.GameLift, reconnect func(ctx context.Context, token string) error, logger *slog.Logger, meter metric.Meter) (*tokenManager, error) {
+	refreshes, err := meter.Int64Counter("gamelift.anywhere.token.refreshes",
+		metric.WithDescription("Number of compute auth token requests"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create token refresh counter")
+	}
+
+	reconnects, err := meter.Int64Counter("gamelift.anywhere.sdk.reconnects",
+		metric.WithDescription("Number of attempts to re-establish a lost SDK connection"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create sdk reconnect counter")
+	}
+
+	return &tokenManager{
+		computeName:   computeName,
+		fleetId:       fleetId,
+		glClient:      glClient,
+		reconnect:     reconnect,
+		logger:        logger,
+		refreshes:     refreshes,
+		reconnects:    reconnects,
+		refreshMargin: tokenRefreshMargin,
+		retryDelay:    tokenRetryDelay,
+		maxRetryDelay: tokenMaxRetryDelay,
+		now:           time.Now,
+		lost:          make(chan struct{}, 1),
+		stop:          make(chan struct{}),
+	}, nil
+}
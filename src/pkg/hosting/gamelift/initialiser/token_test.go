@@ -0,0 +1,287 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package initialiser
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting/gamelift/client"
+	"github.com/aws/aws-sdk-go-v2/service/gamelift"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// tokenClientMock hands out numbered tokens and is safe to use from the token manager goroutine.
+type tokenClientMock struct {
+	client.ClientGameLiftMock
+	mutex    sync.Mutex
+	calls    int
+	lifetime time.Duration
+	err      error
+}
+
+func (tokenClientMock *tokenClientMock) GetComputeAuthToken(ctx context.Context, params *gamelift.GetComputeAuthTokenInput, optFns ...func(*gamelift.Options)) (*gamelift.GetComputeAuthTokenOutput, error) {
+	tokenClientMock.mutex.Lock()
+	defer tokenClientMock.mutex.Unlock()
+
+	tokenClientMock.calls++
+	if tokenClientMock.err != nil {
+		return nil, tokenClientMock.err
+	}
+
+	token := fmt.Sprintf("token-%d", tokenClientMock.calls)
+	output := &gamelift.GetComputeAuthTokenOutput{AuthToken: &token}
+	if tokenClientMock.lifetime != 0 {
+		expiresAt := time.Now().Add(tokenClientMock.lifetime)
+		output.ExpirationTimestamp = &expiresAt
+	}
+	return output, nil
+}
+
+func (tokenClientMock *tokenClientMock) callCount() int {
+	tokenClientMock.mutex.Lock()
+	defer tokenClientMock.mutex.Unlock()
+	return tokenClientMock.calls
+}
+
+type syncBuffer struct {
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (syncBuffer *syncBuffer) Write(p []byte) (int, error) {
+	syncBuffer.mutex.Lock()
+	defer syncBuffer.mutex.Unlock()
+	return syncBuffer.buffer.Write(p)
+}
+
+func (syncBuffer *syncBuffer) String() string {
+	syncBuffer.mutex.Lock()
+	defer syncBuffer.mutex.Unlock()
+	return syncBuffer.buffer.String()
+}
+
+type TokenManagerMockHelper struct {
+	logBuffer    *syncBuffer
+	ctx          context.Context
+	cancel       context.CancelFunc
+	glClient     *tokenClientMock
+	tokenManager *tokenManager
+	reconnectErr chan error
+	reconnected  chan string
+}
+
+func createTokenManagerMockHelper(glClient *tokenClientMock) TokenManagerMockHelper {
+	logBuffer := &syncBuffer{}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	logger := slog.New(slog.NewTextHandler(logBuffer, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	}))
+
+	helper := TokenManagerMockHelper{
+		logBuffer:    logBuffer,
+		ctx:          ctx,
+		cancel:       cancel,
+		glClient:     glClient,
+		reconnectErr: make(chan error, 10),
+		reconnected:  make(chan string, 10),
+	}
+
+	reconnect := func(ctx context.Context, token string) error {
+		select {
+		case err := <-helper.reconnectErr:
+			return err
+		default:
+		}
+		helper.reconnected <- token
+		return nil
+	}
+
+	var gl client.GameLift
+	if glClient != nil {
+		gl = glClient
+	}
+	helper.tokenManager, _ = newTokenManager("UnitTest", fleetId, gl, reconnect, logger, noop.NewMeterProvider().Meter("test"))
+	helper.tokenManager.retryDelay = 10 * time.Millisecond
+	helper.tokenManager.maxRetryDelay = 50 * time.Millisecond
+
+	return helper
+}
+
+func Test_TokenManager_Fetch_RefreshAheadOfExpiry(t *testing.T) {
+	//arrange
+	helper := createTokenManagerMockHelper(&tokenClientMock{lifetime: time.Hour})
+	defer helper.cancel()
+	now := time.Now()
+	helper.tokenManager.now = func() time.Time { return now }
+
+	//act
+	token, err := helper.tokenManager.fetch(helper.ctx)
+	wait, ok := helper.tokenManager.untilRefresh()
+
+	//assert
+	assert.Nil(t, err)
+	assert.Equal(t, "token-1", token)
+	assert.True(t, ok)
+	assert.InDelta(t, (time.Hour - tokenRefreshMargin).Seconds(), wait.Seconds(), 1)
+}
+
+func Test_TokenManager_Fetch_ShortLivedToken(t *testing.T) {
+	//arrange
+	helper := createTokenManagerMockHelper(&tokenClientMock{lifetime: 4 * time.Minute})
+	defer helper.cancel()
+	now := time.Now()
+	helper.tokenManager.now = func() time.Time { return now }
+
+	//act
+	_, err := helper.tokenManager.fetch(helper.ctx)
+	wait, ok := helper.tokenManager.untilRefresh()
+
+	//assert
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.InDelta(t, (2 * time.Minute).Seconds(), wait.Seconds(), 1)
+}
+
+func Test_TokenManager_Fetch_NoExpiry(t *testing.T) {
+	//arrange
+	helper := createTokenManagerMockHelper(&tokenClientMock{})
+	defer helper.cancel()
+
+	//act
+	_, err := helper.tokenManager.fetch(helper.ctx)
+	_, ok := helper.tokenManager.untilRefresh()
+
+	//assert
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
+
+func Test_TokenManager_Fetch_Error(t *testing.T) {
+	//arrange
+	helper := createTokenManagerMockHelper(&tokenClientMock{err: errors.New("Unit Test")})
+	defer helper.cancel()
+
+	//act
+	token, err := helper.tokenManager.fetch(helper.ctx)
+
+	//assert
+	assert.Empty(t, token)
+	assert.ErrorContains(t, err, "failed to get Amazon GameLift Anywhere token: Unit Test")
+}
+
+func Test_TokenManager_Run_RefreshesToken(t *testing.T) {
+	//arrange
+	helper := createTokenManagerMockHelper(&tokenClientMock{lifetime: 100 * time.Millisecond})
+	defer helper.cancel()
+	helper.tokenManager.refreshMargin = 50 * time.Millisecond
+	_, err := helper.tokenManager.fetch(helper.ctx)
+	assert.Nil(t, err)
+
+	//act
+	go helper.tokenManager.run(helper.ctx)
+	defer helper.tokenManager.close()
+
+	//assert
+	assert.Eventually(t, func() bool { return helper.glClient.callCount() >= 3 }, time.Second, 5*time.Millisecond)
+	assert.Contains(t, helper.logBuffer.String(), "refreshed compute auth token")
+	assert.Empty(t, helper.reconnected)
+}
+
+func Test_TokenManager_Run_ConnectionLost(t *testing.T) {
+	//arrange
+	helper := createTokenManagerMockHelper(&tokenClientMock{})
+	defer helper.cancel()
+	_, err := helper.tokenManager.fetch(helper.ctx)
+	assert.Nil(t, err)
+	go helper.tokenManager.run(helper.ctx)
+	defer helper.tokenManager.close()
+
+	//act
+	helper.tokenManager.connectionLost()
+
+	//assert
+	select {
+	case token := <-helper.reconnected:
+		assert.Equal(t, "token-2", token)
+	case <-time.After(time.Second):
+		assert.Fail(t, "sdk was not reconnected")
+	}
+	assert.Eventually(t, func() bool {
+		return strings.Contains(helper.logBuffer.String(), "reconnected to Amazon GameLift")
+	}, time.Second, 5*time.Millisecond)
+}
+
+func Test_TokenManager_Run_ReconnectRetried(t *testing.T) {
+	//arrange
+	helper := createTokenManagerMockHelper(&tokenClientMock{})
+	defer helper.cancel()
+	helper.reconnectErr <- errors.New("Unit Test")
+	go helper.tokenManager.run(helper.ctx)
+	defer helper.tokenManager.close()
+
+	//act
+	helper.tokenManager.connectionLost()
+
+	//assert
+	select {
+	case token := <-helper.reconnected:
+		assert.Equal(t, "token-2", token)
+	case <-time.After(time.Second):
+		assert.Fail(t, "sdk reconnection was not retried")
+	}
+	assert.Contains(t, helper.logBuffer.String(), "failed to reconnect to Amazon GameLift")
+}
+
+func Test_TokenManager_Run_ProvidedToken(t *testing.T) {
+	//arrange
+	helper := createTokenManagerMockHelper(nil)
+	defer helper.cancel()
+	helper.tokenManager.set("provided", time.Time{})
+	go helper.tokenManager.run(helper.ctx)
+	defer helper.tokenManager.close()
+
+	//act
+	helper.tokenManager.connectionLost()
+
+	//assert
+	select {
+	case token := <-helper.reconnected:
+		assert.Equal(t, "provided", token)
+	case <-time.After(time.Second):
+		assert.Fail(t, "sdk was not reconnected")
+	}
+}
+
+func Test_TokenManager_Close_StopsRun(t *testing.T) {
+	//arrange
+	helper := createTokenManagerMockHelper(&tokenClientMock{})
+	defer helper.cancel()
+	done := make(chan struct{})
+	go func() {
+		helper.tokenManager.run(helper.ctx)
+		close(done)
+	}()
+
+	//act
+	helper.tokenManager.close()
+	helper.tokenManager.close()
+
+	//assert
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		assert.Fail(t, "token manager did not stop")
+	}
+}
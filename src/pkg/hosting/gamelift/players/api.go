@@ -0,0 +1,136 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package players
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/network"
+	"github.com/pkg/errors"
+)
+
+const playersPath = "/players"
+
+// Api lets the game server report players joining and leaving on a loopback HTTP API.
+//
+//	POST   /players/{playerSessionId}  accepts the player session
+//	DELETE /players/{playerSessionId}  removes the player session
+//	GET    /players                    returns the players of the game session
+type Api struct {
+	address string
+	logger  *slog.Logger
+
+	mutex    sync.Mutex
+	listener net.Listener
+	server   *http.Server
+}
+
+// Addr returns the address the API listens on, nil until it is started.
+func (api *Api) Addr() net.Addr {
+	api.mutex.Lock()
+	defer api.mutex.Unlock()
+
+	if api.listener == nil {
+		return nil
+	}
+	return api.listener.Addr()
+}
+
+// Start listens on the loopback address.
+//
+// Parameters:
+//   - ctx: Context for the requests
+//   - tracker: The tracker the players are reported to
+//
+// Returns:
+//   - error: Any error listening on the address
+func (api *Api) Start(ctx context.Context, tracker *Tracker) error {
+	listener, err := net.Listen("tcp", api.address)
+	if err != nil {
+		return errors.Wrapf(err, "failed to listen on '%s'", api.address)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+playersPath+"/{playerSessionId}", func(w http.ResponseWriter, r *http.Request) {
+		api.handle(w, tracker.Join(ctx, r.PathValue("playerSessionId")))
+	})
+	mux.HandleFunc("DELETE "+playersPath+"/{playerSessionId}", func(w http.ResponseWriter, r *http.Request) {
+		api.handle(w, tracker.Leave(ctx, r.PathValue("playerSessionId")))
+	})
+	mux.HandleFunc("GET "+playersPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(tracker.Status())
+	})
+
+	api.mutex.Lock()
+	api.listener = listener
+	api.server = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	api.mutex.Unlock()
+
+	api.logger.InfoContext(ctx, "player API listening", "address", listener.Addr().String())
+	go func() {
+		if err := api.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			api.logger.ErrorContext(ctx, "player API failed", "err", err)
+		}
+	}()
+
+	return nil
+}
+
+// Close stops the API.
+//
+// Parameters:
+//   - ctx: Context bounding the shutdown of the API
+//
+// Returns:
+//   - error: Any error shutting down the API
+func (api *Api) Close(ctx context.Context) error {
+	api.mutex.Lock()
+	server := api.server
+	api.mutex.Unlock()
+
+	if server == nil {
+		return nil
+	}
+	return server.Shutdown(ctx)
+}
+
+func (api *Api) handle(w http.ResponseWriter, err error) {
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// NewApi creates the player API.
+//
+// Parameters:
+//   - address: The loopback address to listen on
+//   - logger: Logger for the API
+//
+// Returns:
+//   - *Api: The API, not yet started
+//   - error: An error if the address is not a loopback address
+func NewApi(address string, logger *slog.Logger) (*Api, error) {
+	if err := network.ValidateLoopbackAddress(address); err != nil {
+		return nil, errors.Wrapf(err, "invalid player API address '%s'", address)
+	}
+
+	return &Api{
+		address: address,
+		logger:  logger,
+	}, nil
+}
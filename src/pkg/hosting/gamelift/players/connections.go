@@ -0,0 +1,150 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package players
+
+import (
+	"bufio"
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// tcpEstablished is the state of an established connection in /proc/net/tcp.
+const tcpEstablished = "01"
+
+// Connections counts the established TCP connections to the game port, for games that do not report their
+// player sessions. Connections carry no player session id, so they only count towards the players.
+type Connections struct {
+	port     int
+	interval time.Duration
+	logger   *slog.Logger
+	procDir  string
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// Start polls the connections every interval until the source is closed.
+//
+// Parameters:
+//   - ctx: Context bounding the polling
+//   - tracker: The tracker the connections are reported to
+//
+// Returns:
+//   - error: An error if the connections cannot be counted
+func (connections *Connections) Start(ctx context.Context, tracker *Tracker) error {
+	if _, err := connections.count(); err != nil {
+		return errors.Wrap(err, "failed to count game port connections")
+	}
+
+	go func() {
+		ticker := time.NewTicker(connections.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-connections.stop:
+				return
+			case <-ticker.C:
+				count, err := connections.count()
+				if err != nil {
+					connections.logger.WarnContext(ctx, "failed to count game port connections", "err", err)
+					continue
+				}
+				tracker.SetConnections(ctx, count)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Close stops polling.
+//
+// Parameters:
+//   - ctx: Unused
+//
+// Returns:
+//   - error: Always nil
+func (connections *Connections) Close(ctx context.Context) error {
+	connections.stopOnce.Do(func() {
+		close(connections.stop)
+	})
+	return nil
+}
+
+// count returns the established connections whose local port is the game port, over IPv4 and IPv6.
+func (connections *Connections) count() (int, error) {
+	total := 0
+	for _, name := range []string{"tcp", "tcp6"} {
+		n, err := countEstablished(filepath.Join(connections.procDir, "net", name), connections.port)
+		if err != nil {
+			if name == "tcp6" && os.IsNotExist(err) {
+				continue
+			}
+			return 0, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// countEstablished parses a /proc/net/tcp table, whose local address column reads address:port in hex.
+func countEstablished(path string, port int) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	count := 0
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // header
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || fields[3] != tcpEstablished {
+			continue
+		}
+
+		_, localPort, ok := strings.Cut(fields[1], ":")
+		if !ok {
+			continue
+		}
+		p, err := strconv.ParseInt(localPort, 16, 32)
+		if err == nil && int(p) == port {
+			count++
+		}
+	}
+	return count, scanner.Err()
+}
+
+// NewConnections creates a source counting the TCP connections to the game port.
+//
+// Parameters:
+//   - port: The game port
+//   - interval: Interval between counts
+//   - logger: Logger for the source
+//
+// Returns:
+//   - *Connections: The source, not yet started
+func NewConnections(port int, interval time.Duration, logger *slog.Logger) *Connections {
+	return &Connections{
+		port:     port,
+		interval: interval,
+		logger:   logger,
+		procDir:  "/proc",
+		stop:     make(chan struct{}),
+	}
+}
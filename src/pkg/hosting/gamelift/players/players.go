@@ -0,0 +1,334 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package players tracks the players connected to the game session and keeps Amazon GameLift up to date with them.
+package players
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"slices"
+	"sync"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/constants"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Sessions reports player sessions to Amazon GameLift, it is implemented by the Amazon GameLift SDK.
+type Sessions interface {
+	AcceptPlayerSession(ctx context.Context, playerSessionId string) error
+	RemovePlayerSession(ctx context.Context, playerSessionId string) error
+	UpdatePlayerSessionCreationPolicy(ctx context.Context, policy model.PlayerSessionCreationPolicy) error
+}
+
+// Source tells the tracker about players joining and leaving the game session.
+type Source interface {
+	// Start reports players to the tracker until the source is closed.
+	Start(ctx context.Context, tracker *Tracker) error
+	// Close stops the source.
+	Close(ctx context.Context) error
+}
+
+// Status describes the players of the game session.
+type Status struct {
+	Current        int      `json:"current"`        // the larger of the player sessions and the connections
+	Peak           int      `json:"peak"`           // the most players at once during the game session
+	Maximum        int      `json:"maximum"`        // the maximum number of player sessions of the game session, 0 when unknown
+	PlayerSessions []string `json:"playerSessions"` // the accepted player sessions
+	Connections    int      `json:"connections"`    // TCP connections to the game port, when they are polled
+	Accepting      bool     `json:"accepting"`      // false while new player sessions are denied because the game session is full or draining
+	Draining       bool     `json:"draining"`       // new player sessions are denied until the game session ends
+}
+
+// Tracker keeps the players of the current game session. Players are accepted and removed through the
+// Amazon GameLift SDK, and when capacity is enforced, new player sessions are denied while the game session
+// is full.
+type Tracker struct {
+	cfg      config.PlayerTracking
+	sessions Sessions
+	logger   *slog.Logger
+	sources  []Source
+
+	mutex          sync.Mutex // also serializes the SDK calls, so the policy follows the player count
+	playerSessions map[string]struct{}
+	connections    int
+	peak           int
+	maximum        int
+	denied         bool // set while the creation policy was set to deny all by the tracker
+	draining       bool // new player sessions stay denied, the wrapper exits after the game session
+}
+
+// Reset starts tracking a new game session.
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - maximum: The maximum number of player sessions of the game session, 0 when unknown
+func (tracker *Tracker) Reset(ctx context.Context, maximum int) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	tracker.playerSessions = make(map[string]struct{})
+	tracker.connections = 0
+	tracker.peak = 0
+	tracker.maximum = maximum
+	// a new game session accepts player sessions
+	tracker.denied = false
+
+	tracker.logger.DebugContext(ctx, "tracking players of the game session", "maximum", maximum)
+}
+
+// Join accepts a player session. A player session that was already accepted is not accepted again.
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - playerSessionId: Id of the player session
+//
+// Returns:
+//   - error: Any error returned by the Amazon GameLift SDK
+func (tracker *Tracker) Join(ctx context.Context, playerSessionId string) error {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	if _, ok := tracker.playerSessions[playerSessionId]; ok {
+		tracker.logger.DebugContext(ctx, "player session already accepted", "playerSessionId", playerSessionId)
+		return nil
+	}
+
+	if err := tracker.sessions.AcceptPlayerSession(ctx, playerSessionId); err != nil {
+		return errors.Wrapf(err, "failed to accept player session '%s'", playerSessionId)
+	}
+
+	tracker.playerSessions[playerSessionId] = struct{}{}
+	tracker.logger.InfoContext(ctx, "player joined", "playerSessionId", playerSessionId, "players", tracker.current(), "maximum", tracker.maximum)
+	tracker.update(ctx)
+	return nil
+}
+
+// Leave removes a player session.
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - playerSessionId: Id of the player session
+//
+// Returns:
+//   - error: Any error returned by the Amazon GameLift SDK
+func (tracker *Tracker) Leave(ctx context.Context, playerSessionId string) error {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	if err := tracker.sessions.RemovePlayerSession(ctx, playerSessionId); err != nil {
+		return errors.Wrapf(err, "failed to remove player session '%s'", playerSessionId)
+	}
+
+	delete(tracker.playerSessions, playerSessionId)
+	tracker.logger.InfoContext(ctx, "player left", "playerSessionId", playerSessionId, "players", tracker.current(), "maximum", tracker.maximum)
+	tracker.update(ctx)
+	return nil
+}
+
+// SetConnections records the number of connections to the game server, for games that do not report player
+// sessions.
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - connections: The number of connections
+func (tracker *Tracker) SetConnections(ctx context.Context, connections int) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	if connections == tracker.connections {
+		return
+	}
+
+	tracker.connections = connections
+	tracker.logger.DebugContext(ctx, "game server connections changed", "connections", connections)
+	tracker.update(ctx)
+}
+
+// Drain denies new player sessions for the rest of the game session, whether or not capacity is enforced.
+//
+// Parameters:
+//   - ctx: Context for the operation
+//
+// Returns:
+//   - error: Any error returned by the Amazon GameLift SDK
+func (tracker *Tracker) Drain(ctx context.Context) error {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	tracker.draining = true
+	if tracker.denied {
+		return nil
+	}
+
+	if err := tracker.sessions.UpdatePlayerSessionCreationPolicy(ctx, model.DenyAll); err != nil {
+		return errors.Wrap(err, "failed to deny new player sessions")
+	}
+	tracker.denied = true
+	tracker.logger.InfoContext(ctx, "draining, denying new player sessions", "players", tracker.current())
+	return nil
+}
+
+// Status returns the players of the game session.
+//
+// Returns:
+//   - Status: The players
+func (tracker *Tracker) Status() Status {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	playerSessions := make([]string, 0, len(tracker.playerSessions))
+	for playerSessionId := range tracker.playerSessions {
+		playerSessions = append(playerSessions, playerSessionId)
+	}
+	slices.Sort(playerSessions)
+
+	return Status{
+		Current:        tracker.current(),
+		Peak:           tracker.peak,
+		Maximum:        tracker.maximum,
+		PlayerSessions: playerSessions,
+		Connections:    tracker.connections,
+		Accepting:      !tracker.denied,
+		Draining:       tracker.draining,
+	}
+}
+
+// Env returns the environment variables pointing the game server to the player API. It is empty when the
+// API is not enabled or not yet started.
+//
+// Returns:
+//   - map[string]string: The environment variables
+func (tracker *Tracker) Env() map[string]string {
+	env := make(map[string]string)
+	for _, source := range tracker.sources {
+		if api, ok := source.(*Api); ok && api.Addr() != nil {
+			env[constants.EnvironmentKeyPlayerApiUrl] = "http://" + api.Addr().String() + playersPath
+		}
+	}
+	return env
+}
+
+// Start starts the configured player sources.
+//
+// Parameters:
+//   - ctx: Context bounding the sources
+//
+// Returns:
+//   - error: Any error starting a source
+func (tracker *Tracker) Start(ctx context.Context) error {
+	for _, source := range tracker.sources {
+		if err := source.Start(ctx, tracker); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close stops the player sources.
+//
+// Parameters:
+//   - ctx: Context bounding the shutdown of the sources
+//
+// Returns:
+//   - error: Any error stopping a source
+func (tracker *Tracker) Close(ctx context.Context) error {
+	var err error
+	for _, source := range tracker.sources {
+		if e := source.Close(ctx); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+func (tracker *Tracker) current() int {
+	return max(len(tracker.playerSessions), tracker.connections)
+}
+
+// update records the peak and, when capacity is enforced, denies new player sessions while the game session is
+// full and accepts them again once a player left. The tracker only lifts a deny it set itself, and never while
+// draining.
+func (tracker *Tracker) update(ctx context.Context) {
+	current := tracker.current()
+	tracker.peak = max(tracker.peak, current)
+
+	if !tracker.cfg.EnforceCapacity || tracker.maximum <= 0 {
+		return
+	}
+
+	switch {
+	case current >= tracker.maximum && !tracker.denied:
+		if err := tracker.sessions.UpdatePlayerSessionCreationPolicy(ctx, model.DenyAll); err != nil {
+			tracker.logger.WarnContext(ctx, "failed to deny new player sessions", "err", err)
+			return
+		}
+		tracker.denied = true
+		tracker.logger.InfoContext(ctx, "game session is full, denying new player sessions", "players", current, "maximum", tracker.maximum)
+	case current < tracker.maximum && tracker.denied && !tracker.draining:
+		if err := tracker.sessions.UpdatePlayerSessionCreationPolicy(ctx, model.AcceptAll); err != nil {
+			tracker.logger.WarnContext(ctx, "failed to accept new player sessions", "err", err)
+			return
+		}
+		tracker.denied = false
+		tracker.logger.InfoContext(ctx, "game session has room again, accepting new player sessions", "players", current, "maximum", tracker.maximum)
+	}
+}
+
+// New creates a player tracker.
+//
+// Parameters:
+//   - cfg: The player sources and whether capacity is enforced
+//   - gamePort: The game port, whose connections are counted when polling is enabled
+//   - sessions: Where player sessions are reported
+//   - logger: Logger for the tracker
+//   - meter: Meter for the player metrics
+//
+// Returns:
+//   - *Tracker: The tracker, its sources not yet started
+//   - error: Any error in the configuration
+func New(cfg config.PlayerTracking, gamePort int, sessions Sessions, logger *slog.Logger, meter metric.Meter) (*Tracker, error) {
+	tracker := &Tracker{
+		cfg:            cfg,
+		sessions:       sessions,
+		logger:         logger,
+		sources:        make([]Source, 0),
+		playerSessions: make(map[string]struct{}),
+	}
+
+	if len(cfg.ApiAddress) != 0 {
+		api, err := NewApi(cfg.ApiAddress, logger)
+		if err != nil {
+			return nil, err
+		}
+		tracker.sources = append(tracker.sources, api)
+	}
+
+	if cfg.ConnectionPollInterval > 0 {
+		if runtime.GOOS != "linux" {
+			return nil, errors.New("polling the game port connections is only supported on Linux")
+		}
+		tracker.sources = append(tracker.sources, NewConnections(gamePort, cfg.ConnectionPollInterval, logger))
+	}
+
+	_, err := meter.Int64ObservableGauge("gamelift.game_session.players",
+		metric.WithDescription("Number of players in the game session"),
+		metric.WithInt64Callback(func(ctx context.Context, observer metric.Int64Observer) error {
+			status := tracker.Status()
+			observer.Observe(int64(status.Current), metric.WithAttributes(attribute.String("kind", "current")))
+			observer.Observe(int64(status.Peak), metric.WithAttributes(attribute.String("kind", "peak")))
+			return nil
+		}))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create player gauge")
+	}
+
+	return tracker, nil
+}
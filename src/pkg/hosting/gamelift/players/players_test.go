@@ -0,0 +1,175 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package players
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/internal/mocks"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+func createTrackerHelper(t *testing.T, cfg config.PlayerTracking) (*Tracker, *mocks.GameLiftSdkMock) {
+	sdk := &mocks.GameLiftSdkMock{}
+	logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+	tracker, err := New(cfg, 7777, sdk, logger, noop.NewMeterProvider().Meter("test"))
+	assert.NoError(t, err)
+	return tracker, sdk
+}
+
+func TestTracker_JoinAndLeave(t *testing.T) {
+	//arrange
+	tracker, sdk := createTrackerHelper(t, config.PlayerTracking{})
+	ctx := context.Background()
+	tracker.Reset(ctx, 4)
+
+	//act
+	assert.NoError(t, tracker.Join(ctx, "psess-1"))
+	assert.NoError(t, tracker.Join(ctx, "psess-1"))
+	assert.NoError(t, tracker.Join(ctx, "psess-2"))
+	assert.NoError(t, tracker.Leave(ctx, "psess-1"))
+
+	//assert
+	status := tracker.Status()
+	assert.Equal(t, 1, status.Current)
+	assert.Equal(t, 2, status.Peak)
+	assert.Equal(t, 4, status.Maximum)
+	assert.Equal(t, []string{"psess-2"}, status.PlayerSessions)
+	assert.Equal(t, []string{"psess-1", "psess-2"}, sdk.AcceptedPlayerSessions)
+	assert.Equal(t, []string{"psess-1"}, sdk.RemovedPlayerSessions)
+	assert.Empty(t, sdk.PlayerSessionPolicies)
+}
+
+func TestTracker_JoinFailed(t *testing.T) {
+	//arrange
+	tracker, sdk := createTrackerHelper(t, config.PlayerTracking{})
+	sdk.AcceptPlayerSessionError = errors.New("Unit Test")
+	tracker.Reset(context.Background(), 4)
+
+	//act
+	err := tracker.Join(context.Background(), "psess-1")
+
+	//assert
+	assert.ErrorContains(t, err, "failed to accept player session 'psess-1'")
+	assert.Equal(t, 0, tracker.Status().Current)
+}
+
+func TestTracker_EnforcesCapacity(t *testing.T) {
+	//arrange
+	tracker, sdk := createTrackerHelper(t, config.PlayerTracking{EnforceCapacity: true})
+	ctx := context.Background()
+	tracker.Reset(ctx, 2)
+
+	//act
+	_ = tracker.Join(ctx, "psess-1")
+	_ = tracker.Join(ctx, "psess-2")
+	full := tracker.Status()
+	_ = tracker.Leave(ctx, "psess-1")
+
+	//assert
+	assert.False(t, full.Accepting)
+	assert.True(t, tracker.Status().Accepting)
+	assert.Equal(t, []model.PlayerSessionCreationPolicy{model.DenyAll, model.AcceptAll}, sdk.PlayerSessionPolicies)
+}
+
+func TestTracker_ConnectionsCountTowardsCapacity(t *testing.T) {
+	//arrange
+	tracker, sdk := createTrackerHelper(t, config.PlayerTracking{EnforceCapacity: true})
+	ctx := context.Background()
+	tracker.Reset(ctx, 2)
+
+	//act
+	tracker.SetConnections(ctx, 3)
+
+	//assert
+	assert.Equal(t, 3, tracker.Status().Current)
+	assert.Equal(t, []model.PlayerSessionCreationPolicy{model.DenyAll}, sdk.PlayerSessionPolicies)
+}
+
+func TestTracker_DrainKeepsDenying(t *testing.T) {
+	//arrange
+	tracker, sdk := createTrackerHelper(t, config.PlayerTracking{EnforceCapacity: true})
+	ctx := context.Background()
+	tracker.Reset(ctx, 2)
+	_ = tracker.Join(ctx, "psess-1")
+	_ = tracker.Join(ctx, "psess-2")
+
+	//act
+	assert.NoError(t, tracker.Drain(ctx))
+	_ = tracker.Leave(ctx, "psess-1")
+
+	//assert
+	status := tracker.Status()
+	assert.False(t, status.Accepting)
+	assert.True(t, status.Draining)
+	assert.Equal(t, []model.PlayerSessionCreationPolicy{model.DenyAll}, sdk.PlayerSessionPolicies)
+}
+
+func TestApi_ReportsPlayers(t *testing.T) {
+	//arrange
+	tracker, sdk := createTrackerHelper(t, config.PlayerTracking{ApiAddress: "127.0.0.1:0"})
+	ctx := context.Background()
+	tracker.Reset(ctx, 4)
+	assert.NoError(t, tracker.Start(ctx))
+	defer tracker.Close(ctx)
+	url := tracker.Env()["GAMELIFT_PLAYER_API_URL"]
+
+	//act
+	joined, err := http.Post(url+"/psess-1", "", nil)
+	assert.NoError(t, err)
+	joined.Body.Close()
+	status, err := http.Get(url)
+	assert.NoError(t, err)
+	defer status.Body.Close()
+
+	//assert
+	assert.Equal(t, http.StatusNoContent, joined.StatusCode)
+	var players Status
+	assert.NoError(t, json.NewDecoder(status.Body).Decode(&players))
+	assert.Equal(t, 1, players.Current)
+	assert.Equal(t, []string{"psess-1"}, sdk.AcceptedPlayerSessions)
+}
+
+func TestNewApi_RejectsNonLoopbackAddress(t *testing.T) {
+	//act
+	_, err := NewApi("0.0.0.0:37090", slog.Default())
+
+	//assert
+	assert.ErrorContains(t, err, "loopback")
+}
+
+func TestConnections_CountsEstablishedOnGamePort(t *testing.T) {
+	//arrange
+	procDir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(procDir, "net"), 0755))
+	table := "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n" +
+		"   0: 00000000:1E61 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1 1 0000000000000000 100 0 0 10 0\n" +
+		"   1: 0100007F:1E61 0100007F:D431 01 00000000:00000000 00:00000000 00000000     0        0 2 1 0000000000000000 20 4 30 10 -1\n" +
+		"   2: 0100007F:1E61 0100007F:D432 01 00000000:00000000 00:00000000 00000000     0        0 3 1 0000000000000000 20 4 30 10 -1\n" +
+		"   3: 0100007F:D433 0100007F:1E61 01 00000000:00000000 00:00000000 00000000     0        0 4 1 0000000000000000 20 4 30 10 -1\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(procDir, "net", "tcp"), []byte(table), 0644))
+	connections := NewConnections(7777, time.Second, slog.Default())
+	connections.procDir = procDir
+
+	//act
+	count, err := connections.count()
+
+	//assert
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
@@ -15,29 +15,44 @@ import (
 )
 
 type logAdaptor struct {
-	ctx    context.Context
-	logger *slog.Logger
+	ctx     context.Context
+	logger  *slog.Logger
+	observe func(msg string)
 }
 
 func (logAdaptor logAdaptor) Debugf(s string, a ...any) {
 	msg := fmt.Sprintf(s, a...)
 	logAdaptor.logger.DebugContext(logAdaptor.ctx, msg)
+	logAdaptor.notify(msg)
 }
 
 func (logAdaptor logAdaptor) Warnf(s string, a ...any) {
 	msg := fmt.Sprintf(s, a...)
 	logAdaptor.logger.WarnContext(logAdaptor.ctx, msg)
+	logAdaptor.notify(msg)
 }
 
 func (logAdaptor logAdaptor) Errorf(s string, a ...any) {
 	msg := fmt.Sprintf(s, a...)
 	logAdaptor.logger.ErrorContext(logAdaptor.ctx, msg)
+	logAdaptor.notify(msg)
+}
+
+func (logAdaptor logAdaptor) notify(msg string) {
+	if logAdaptor.observe != nil {
+		logAdaptor.observe(msg)
+	}
 }
 
 func NewLogAdaptor(ctx context.Context, logger *slog.Logger) log.ILogger {
+	return newLogAdaptor(ctx, logger, nil)
+}
+
+func newLogAdaptor(ctx context.Context, logger *slog.Logger, observe func(msg string)) log.ILogger {
 	a := &logAdaptor{
-		logger: logger,
-		ctx:    context.WithValue(ctx, string(constants.ContextKeySource), "GameLiftServerSDK"),
+		logger:  logger,
+		ctx:     context.WithValue(ctx, string(constants.ContextKeySource), "GameLiftServerSDK"),
+		observe: observe,
 	}
 
 	return a
@@ -0,0 +1,191 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package sdk
+
+import (
+	"context"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/codes"
+)
+
+const (
+	DefaultRetryAttempts       = 5
+	DefaultRetryInitialBackoff = time.Second
+	DefaultRetryMaxBackoff     = 30 * time.Second
+	DefaultRetryJitter         = 0.2
+	DefaultCallTimeout         = time.Minute
+)
+
+// ErrCallTimeout is returned for an attempt that did not complete within the call timeout.
+var ErrCallTimeout = errors.New("call timed out")
+
+// retryableErrors are the SDK errors caused by the connection to Amazon GameLift or by the service itself.
+var retryableErrors = map[common.GameLiftErrorType]bool{
+	common.LocalConnectionFailed:                true,
+	common.NetworkNotInitialized:                true,
+	common.ProcessNotReady:                      true,
+	common.ProcessReadyFailed:                   true,
+	common.ServiceCallFailed:                    true,
+	common.TooManyRequestsException:             true,
+	common.InternalServiceException:             true,
+	common.WebsocketConnectFailure:              true,
+	common.WebsocketRetriableSendMessageFailure: true,
+	common.WebsocketSendMessageFailure:          true,
+	common.WebsocketClosingError:                true,
+	common.UnknownException:                     true,
+}
+
+// IsRetryable reports whether a failed SDK call may succeed when attempted again. Invalid parameters,
+// rejected credentials and calls made in the wrong state are fatal, as are cancelled contexts.
+//
+// Parameters:
+//   - err: The error returned by the call
+//
+// Returns:
+//   - bool: True if the call can be attempted again
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, ErrCallTimeout) {
+		return true
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var gameLiftError *common.GameLiftError
+	if errors.As(err, &gameLiftError) {
+		return retryableErrors[gameLiftError.ErrorType]
+	}
+
+	// errors from outside the SDK, such as fetching container credentials, come from the network
+	return true
+}
+
+// withRetryDefaults fills the unset fields of a retry policy with the defaults.
+func withRetryDefaults(policy config.SdkRetry) config.SdkRetry {
+	if policy.Attempts <= 0 {
+		policy.Attempts = DefaultRetryAttempts
+	}
+	if policy.InitialBackoff <= 0 {
+		policy.InitialBackoff = DefaultRetryInitialBackoff
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = DefaultRetryMaxBackoff
+	}
+	if policy.Jitter <= 0 {
+		policy.Jitter = DefaultRetryJitter
+	} else if policy.Jitter > 1 {
+		policy.Jitter = 1
+	}
+	if policy.CallTimeout <= 0 {
+		policy.CallTimeout = DefaultCallTimeout
+	}
+	return policy
+}
+
+// backoff returns how long to wait after the given failed attempt, starting at 1.
+func backoff(policy config.SdkRetry, attempt int) time.Duration {
+	wait := policy.InitialBackoff
+	for i := 1; i < attempt && wait < policy.MaxBackoff; i++ {
+		wait *= 2
+	}
+	wait = min(wait, policy.MaxBackoff)
+
+	if policy.Jitter > 0 {
+		// spread the wait over [1-jitter, 1+jitter] so hosts failing together do not retry together
+		wait = time.Duration(float64(wait) * (1 - policy.Jitter + 2*policy.Jitter*rand.Float64()))
+	}
+	return wait
+}
+
+// retry calls the SDK until it succeeds, fails with an error that is not retryable, or runs out of attempts.
+// Each attempt gets its own span. An attempt exceeding the call timeout is abandoned, but as the SDK cannot
+// run two calls at once, the next attempt only starts once it has returned, and its result is kept if it
+// succeeded. reset is called before every attempt but the first to undo what the failed attempt left behind.
+func (sdk *Sdk) retry(ctx context.Context, name string, reset func(), call func() error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 && reset != nil {
+			reset()
+		}
+
+		err = sdk.attempt(ctx, name, attempt, call)
+		if err == nil {
+			return nil
+		}
+
+		retryable := IsRetryable(err)
+		if !retryable || attempt >= sdk.retryPolicy.Attempts {
+			sdk.logger.ErrorContext(ctx, "Amazon GameLift SDK call failed", "call", name, "attempt", attempt, "retryable", retryable, "err", err)
+			if f := sdk.onError.Load(); f != nil && *f != nil {
+				(*f)(name, err)
+			}
+			if retryable {
+				return errors.Wrapf(err, "%s failed after %d attempts", name, attempt)
+			}
+			return errors.Wrapf(err, "%s failed", name)
+		}
+
+		wait := backoff(sdk.retryPolicy, attempt)
+		sdk.logger.WarnContext(ctx, "Amazon GameLift SDK call failed, retrying", "call", name, "attempt", attempt, "retryIn", wait, "err", err)
+
+		select {
+		case <-ctx.Done():
+			return errors.Wrapf(ctx.Err(), "%s cancelled after %d attempts, last error: %v", name, attempt, err)
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (sdk *Sdk) attempt(ctx context.Context, name string, attempt int, call func() error) error {
+	ctx, span, _ := sdk.spanner.NewSpan(ctx, "Amazon GameLift "+name, map[string]string{
+		"attempt": strconv.Itoa(attempt),
+	})
+	defer span.End()
+
+	result := make(chan error, 1)
+	go func() {
+		result <- call()
+	}()
+
+	timer := time.NewTimer(sdk.retryPolicy.CallTimeout)
+	defer timer.Stop()
+
+	var err error
+	select {
+	case err = <-result:
+	case <-timer.C:
+		err = errors.Wrapf(ErrCallTimeout, "%s did not complete within %s", name, sdk.retryPolicy.CallTimeout)
+		span.AddEvent("timeout")
+
+		select {
+		case late := <-result:
+			if late == nil {
+				err = nil
+			}
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
@@ -0,0 +1,170 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/internal/mocks"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func createRetrySdkHelper(policy config.SdkRetry) (*Sdk, *bytes.Buffer) {
+	logBuffer := &bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(logBuffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	return &Sdk{
+		logger:      logger,
+		spanner:     &mocks.SpannerMock{},
+		retryPolicy: withRetryDefaults(policy),
+	}, logBuffer
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"connection failed", common.NewGameLiftError(common.LocalConnectionFailed, "", ""), true},
+		{"throttled", common.NewGameLiftError(common.TooManyRequestsException, "", ""), true},
+		{"process not ready", common.NewGameLiftError(common.ProcessNotReady, "", ""), true},
+		{"validation", common.NewGameLiftError(common.ValidationException, "", ""), false},
+		{"unauthorized", common.NewGameLiftError(common.UnauthorizedException, "", ""), false},
+		{"already initialized", common.NewGameLiftError(common.AlreadyInitialized, "", ""), false},
+		{"timeout", ErrCallTimeout, true},
+		{"cancelled", context.Canceled, false},
+		{"other", errors.New("connection reset"), true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, IsRetryable(test.err))
+		})
+	}
+}
+
+func TestBackoff_DoublesUpToMax(t *testing.T) {
+	//arrange
+	policy := withRetryDefaults(config.SdkRetry{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second})
+	policy.Jitter = 0
+
+	//act
+	waits := []time.Duration{backoff(policy, 1), backoff(policy, 2), backoff(policy, 3), backoff(policy, 4)}
+
+	//assert
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second}, waits)
+}
+
+func TestBackoff_Jitter(t *testing.T) {
+	//arrange
+	policy := withRetryDefaults(config.SdkRetry{InitialBackoff: time.Second, Jitter: 0.5})
+
+	for i := 0; i < 100; i++ {
+		//act
+		wait := backoff(policy, 1)
+
+		//assert
+		assert.GreaterOrEqual(t, wait, 500*time.Millisecond)
+		assert.LessOrEqual(t, wait, 1500*time.Millisecond)
+	}
+}
+
+func TestRetry_SucceedsAfterTransientFailures(t *testing.T) {
+	//arrange
+	sdk, _ := createRetrySdkHelper(config.SdkRetry{Attempts: 3, InitialBackoff: time.Millisecond})
+	calls, resets := 0, 0
+
+	//act
+	err := sdk.retry(context.Background(), "InitSDK", func() { resets++ }, func() error {
+		calls++
+		if calls < 3 {
+			return common.NewGameLiftError(common.LocalConnectionFailed, "", "")
+		}
+		return nil
+	})
+
+	//assert
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, 2, resets)
+}
+
+func TestRetry_FatalErrorIsNotRetried(t *testing.T) {
+	//arrange
+	sdk, _ := createRetrySdkHelper(config.SdkRetry{Attempts: 3, InitialBackoff: time.Millisecond})
+	var failedCall string
+	sdk.SetOnError(func(call string, err error) {
+		failedCall = call
+	})
+	calls := 0
+
+	//act
+	err := sdk.retry(context.Background(), "InitSDK", nil, func() error {
+		calls++
+		return common.NewGameLiftError(common.ValidationException, "", "")
+	})
+
+	//assert
+	assert.ErrorContains(t, err, "InitSDK failed")
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, "InitSDK", failedCall)
+}
+
+func TestRetry_GivesUpAfterAttempts(t *testing.T) {
+	//arrange
+	sdk, logBuffer := createRetrySdkHelper(config.SdkRetry{Attempts: 2, InitialBackoff: time.Millisecond})
+	calls := 0
+
+	//act
+	err := sdk.retry(context.Background(), "ProcessReady", nil, func() error {
+		calls++
+		return common.NewGameLiftError(common.ProcessNotReady, "", "")
+	})
+
+	//assert
+	assert.ErrorContains(t, err, "ProcessReady failed after 2 attempts")
+	assert.Equal(t, 2, calls)
+	assert.Contains(t, logBuffer.String(), "Amazon GameLift SDK call failed, retrying")
+}
+
+func TestRetry_CallTimeout(t *testing.T) {
+	//arrange
+	sdk, _ := createRetrySdkHelper(config.SdkRetry{Attempts: 2, InitialBackoff: time.Millisecond, CallTimeout: 10 * time.Millisecond})
+	calls := 0
+
+	//act
+	err := sdk.retry(context.Background(), "InitSDK", nil, func() error {
+		calls++
+		time.Sleep(30 * time.Millisecond)
+		return errors.New("connection reset")
+	})
+
+	//assert
+	assert.ErrorIs(t, err, ErrCallTimeout)
+	assert.Equal(t, 2, calls)
+}
+
+func TestRetry_ContextCancelled(t *testing.T) {
+	//arrange
+	sdk, _ := createRetrySdkHelper(config.SdkRetry{Attempts: 5, InitialBackoff: time.Hour})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	//act
+	err := sdk.retry(ctx, "InitSDK", nil, func() error {
+		return common.NewGameLiftError(common.LocalConnectionFailed, "", "")
+	})
+
+	//assert
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -8,8 +8,17 @@ package sdk
 import (
 	"context"
 	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
 
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/observability"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/request"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/result"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server"
+	"github.com/pkg/errors"
 )
 
 // GameLiftSdk defines the interface for interacting with server SDK for Amazon GameLift Servers.
@@ -30,15 +39,85 @@ type GameLiftSdk interface {
 	// activated a game session and is now ready to receive player connections.
 	ActivateGameSession(ctx context.Context) error
 
+	// AcceptPlayerSession notifies Amazon GameLift that a player with the player session id has connected.
+	AcceptPlayerSession(ctx context.Context, playerSessionId string) error
+
+	// RemovePlayerSession notifies Amazon GameLift that a player with the player session id has disconnected.
+	RemovePlayerSession(ctx context.Context, playerSessionId string) error
+
+	// UpdatePlayerSessionCreationPolicy sets whether the game session accepts new player sessions.
+	UpdatePlayerSessionCreationPolicy(ctx context.Context, policy model.PlayerSessionCreationPolicy) error
+
+	// GetTerminationTime retrieves the time the process is shut down, in Unix seconds, once OnProcessTerminate was called.
+	GetTerminationTime(ctx context.Context) (int64, error)
+
+	// GetComputeCertificate retrieves the path of the compute's TLS certificate and the hostname it is issued for.
+	GetComputeCertificate(ctx context.Context) (result.GetComputeCertificateResult, error)
+
+	// GetFleetRoleCredentials retrieves the credentials of the fleet's IAM service role.
+	GetFleetRoleCredentials(ctx context.Context, req request.GetFleetRoleCredentialsRequest) (result.GetFleetRoleCredentialsResult, error)
+
 	// Destroy frees the server SDK for Amazon GameLift Servers from memory.
 	Destroy(ctx context.Context) error
+
+	// Reconnect re-initializes the SDK connection with new server parameters and, if the process
+	// was ready, calls ProcessReady again with the parameters and callbacks it was last given.
+	// It fails once ProcessEnding or Destroy was called.
+	Reconnect(ctx context.Context, params server.ServerParameters) error
+
+	// SetOnError registers a callback invoked when a retried call fails for good.
+	SetOnError(f func(call string, err error))
+
+	// SetOnConnectionLost registers a callback invoked when the SDK gives up restoring its
+	// connection to Amazon GameLift while the process is ready.
+	SetOnConnectionLost(f func())
+}
+
+// connectionLostMessages are the SDK log records written when a dropped websocket could not be restored.
+// The SDK retries with the credentials it first connected with, so an expired auth token ends up here.
+// They are the prefixes of the records of handleNetworkInterrupt in server/internal/transport/websocket.go
+// of the server SDK v5.4.0, check them there when upgrading the SDK as reconnecting stops working if they change.
+var connectionLostMessages = []string{
+	"Reconnect failed",
+	// written instead once the SDK has been destroyed, as it no longer reconnects on its own
+	"Preventing auto-reconnect attempt",
 }
 
+// setLogger is replaced in tests to get hold of the SDK log adaptor.
+var setLogger = server.SetLoggerInterface
+
+var errClosed = errors.New("sdk is closed, the process is ending")
+
 type Sdk struct {
-	logger *slog.Logger
+	logger      *slog.Logger
+	spanner     observability.Spanner
+	retryPolicy config.SdkRetry
+
+	mutex             sync.Mutex
+	processParameters *server.ProcessParameters
+	closed            bool // set by ProcessEnding and Destroy, a closed SDK is never reconnected
+	ready             atomic.Bool
+	onConnectionLost  atomic.Pointer[func()]
+	onError           atomic.Pointer[func(call string, err error)]
 }
 
 func (sdk *Sdk) InitSDK(ctx context.Context, params server.ServerParameters) error {
+	sdk.mutex.Lock()
+	defer sdk.mutex.Unlock()
+
+	return sdk.retry(ctx, "InitSDK", sdk.destroyFailedInit, func() error {
+		return sdk.initSDK(ctx, params)
+	})
+}
+
+// destroyFailedInit frees the SDK before InitSDK is attempted again, as a failed InitSDK still leaves it initialized.
+func (sdk *Sdk) destroyFailedInit() {
+	if err := server.Destroy(); err != nil {
+		sdk.logger.Debug("failed to destroy sdk after a failed initialization", "err", err)
+	}
+}
+
+func (sdk *Sdk) initSDK(ctx context.Context, params server.ServerParameters) error {
 	redactedParams := params
 	redactedParams.AuthToken = "<REDACTED>"
 	redactedParams.AccessKey = "<REDACTED>"
@@ -50,35 +129,160 @@ func (sdk *Sdk) InitSDK(ctx context.Context, params server.ServerParameters) err
 
 func (sdk *Sdk) InitSDKFromEnvironment(ctx context.Context) error {
 	sdk.logger.DebugContext(ctx, "InitSDKFromEnvironment called")
-	return server.InitSDKFromEnvironment()
+	return sdk.retry(ctx, "InitSDKFromEnvironment", sdk.destroyFailedInit, server.InitSDKFromEnvironment)
 }
 
 func (sdk *Sdk) ProcessReady(ctx context.Context, params server.ProcessParameters) error {
+	sdk.mutex.Lock()
+	defer sdk.mutex.Unlock()
+
+	return sdk.retry(ctx, "ProcessReady", nil, func() error {
+		return sdk.processReady(ctx, params)
+	})
+}
+
+// processReady reports the process ready and keeps its parameters for a reconnection. The mutex must be held.
+func (sdk *Sdk) processReady(ctx context.Context, params server.ProcessParameters) error {
 	sdk.logger.DebugContext(ctx, "ProcessReady called", "port", params.Port, "logParams", params.LogParameters)
-	return server.ProcessReady(params)
+	if err := server.ProcessReady(params); err != nil {
+		return err
+	}
+
+	sdk.processParameters = &params
+	sdk.ready.Store(true)
+	return nil
 }
 
 func (sdk *Sdk) ProcessEnding(ctx context.Context) error {
 	sdk.logger.DebugContext(ctx, "ProcessEnding called")
+	sdk.close()
 	return server.ProcessEnding()
 }
 
+// close stops the SDK from being reconnected, waiting for a reconnection in progress to finish.
+func (sdk *Sdk) close() {
+	sdk.ready.Store(false)
+
+	sdk.mutex.Lock()
+	defer sdk.mutex.Unlock()
+	sdk.closed = true
+	sdk.processParameters = nil
+}
+
 func (sdk *Sdk) ActivateGameSession(ctx context.Context) error {
 	sdk.logger.DebugContext(ctx, "ActivateGameSession called")
 	return server.ActivateGameSession()
 }
 
+func (sdk *Sdk) AcceptPlayerSession(ctx context.Context, playerSessionId string) error {
+	sdk.logger.DebugContext(ctx, "AcceptPlayerSession called", "playerSessionId", playerSessionId)
+	return server.AcceptPlayerSession(playerSessionId)
+}
+
+func (sdk *Sdk) RemovePlayerSession(ctx context.Context, playerSessionId string) error {
+	sdk.logger.DebugContext(ctx, "RemovePlayerSession called", "playerSessionId", playerSessionId)
+	return server.RemovePlayerSession(playerSessionId)
+}
+
+func (sdk *Sdk) UpdatePlayerSessionCreationPolicy(ctx context.Context, policy model.PlayerSessionCreationPolicy) error {
+	sdk.logger.DebugContext(ctx, "UpdatePlayerSessionCreationPolicy called", "policy", policy.String())
+	return server.UpdatePlayerSessionCreationPolicy(policy)
+}
+
+func (sdk *Sdk) GetTerminationTime(ctx context.Context) (int64, error) {
+	sdk.logger.DebugContext(ctx, "GetTerminationTime called")
+	return server.GetTerminationTime()
+}
+
+func (sdk *Sdk) GetComputeCertificate(ctx context.Context) (result.GetComputeCertificateResult, error) {
+	sdk.logger.DebugContext(ctx, "GetComputeCertificate called")
+	return server.GetComputeCertificate()
+}
+
+func (sdk *Sdk) GetFleetRoleCredentials(ctx context.Context, req request.GetFleetRoleCredentialsRequest) (result.GetFleetRoleCredentialsResult, error) {
+	sdk.logger.DebugContext(ctx, "GetFleetRoleCredentials called", "roleArn", req.RoleArn)
+	return server.GetFleetRoleCredentials(req)
+}
+
 func (sdk *Sdk) Destroy(ctx context.Context) error {
 	sdk.logger.DebugContext(ctx, "Destroy called")
+	sdk.close()
 	return server.Destroy()
 }
 
-func NewSdk(ctx context.Context, logger *slog.Logger) *Sdk {
+func (sdk *Sdk) Reconnect(ctx context.Context, params server.ServerParameters) error {
+	sdk.mutex.Lock()
+	defer sdk.mutex.Unlock()
+
+	sdk.logger.DebugContext(ctx, "Reconnect called")
+	if sdk.closed {
+		return errClosed
+	}
+
+	processParameters := sdk.processParameters
+	sdk.ready.Store(false)
+
+	// the previous connection is already broken, so a failure to close it cleanly is not fatal
+	if err := server.Destroy(); err != nil {
+		sdk.logger.WarnContext(ctx, "failed to destroy sdk before reconnecting", "err", err)
+	}
+
+	if err := sdk.initSDK(ctx, params); err != nil {
+		return err
+	}
+
+	if processParameters == nil {
+		return nil
+	}
+
+	return sdk.processReady(ctx, *processParameters)
+}
+
+func (sdk *Sdk) SetOnError(f func(call string, err error)) {
+	sdk.onError.Store(&f)
+}
+
+func (sdk *Sdk) SetOnConnectionLost(f func()) {
+	sdk.onConnectionLost.Store(&f)
+}
+
+func (sdk *Sdk) observe(msg string) {
+	if !sdk.ready.Load() {
+		return
+	}
+
+	f := sdk.onConnectionLost.Load()
+	if f == nil || *f == nil {
+		return
+	}
+
+	for _, m := range connectionLostMessages {
+		if strings.HasPrefix(msg, m) {
+			(*f)()
+			return
+		}
+	}
+}
+
+// NewSdk creates the server SDK wrapper. InitSDK and ProcessReady are retried according to the retry policy,
+// its unset fields take the defaults.
+//
+// Parameters:
+//   - ctx: Context for the SDK logger
+//   - logger: Logger the SDK logs are written to
+//   - spanner: Tracing provider for the call attempts
+//   - retryPolicy: How failed InitSDK and ProcessReady calls are retried
+//
+// Returns:
+//   - *Sdk: The server SDK wrapper
+func NewSdk(ctx context.Context, logger *slog.Logger, spanner observability.Spanner, retryPolicy config.SdkRetry) *Sdk {
 	s := &Sdk{
-		logger: logger,
+		logger:      logger,
+		spanner:     spanner,
+		retryPolicy: withRetryDefaults(retryPolicy),
 	}
 
-	server.SetLoggerInterface(NewLogAdaptor(ctx, logger))
+	setLogger(newLogAdaptor(ctx, logger, s.observe))
 
 	return s
 }
@@ -0,0 +1,104 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package sdk
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/internal/mocks"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/log"
+	"github.com/stretchr/testify/assert"
+)
+
+// newObservedSdk creates an Sdk through NewSdk and returns the log adaptor it hands to the server SDK.
+func newObservedSdk(t *testing.T) (*Sdk, log.ILogger) {
+	var adaptor log.ILogger
+	setLogger = func(l log.ILogger) { adaptor = l }
+	t.Cleanup(func() { setLogger = server.SetLoggerInterface })
+
+	s := NewSdk(context.Background(), slog.New(slog.DiscardHandler), &mocks.SpannerMock{}, config.SdkRetry{})
+	return s, adaptor
+}
+
+func TestSdk_ConnectionLost_WhileReady(t *testing.T) {
+	// the records written by handleNetworkInterrupt in server/internal/transport/websocket.go of the SDK v5.4.0
+	tests := []struct {
+		name string
+		log  func(adaptor log.ILogger)
+	}{
+		{"reconnect failed", func(adaptor log.ILogger) {
+			adaptor.Errorf("Reconnect failed: %s", errors.New("websocket: bad handshake"))
+		}},
+		{"auto-reconnect prevented", func(adaptor log.ILogger) {
+			adaptor.Debugf("Preventing auto-reconnect attempt due to explicit previous call to PreventAutoReconnect()")
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			//arrange
+			s, adaptor := newObservedSdk(t)
+			calls := 0
+			s.SetOnConnectionLost(func() { calls++ })
+			s.ready.Store(true)
+
+			//act
+			test.log(adaptor)
+
+			//assert
+			assert.Equal(t, 1, calls)
+		})
+	}
+}
+
+func TestSdk_ConnectionLost_NotReady(t *testing.T) {
+	//arrange
+	s, adaptor := newObservedSdk(t)
+	calls := 0
+	s.SetOnConnectionLost(func() { calls++ })
+
+	//act
+	adaptor.Errorf("Reconnect failed: %s", errors.New("websocket: bad handshake"))
+
+	//assert
+	assert.Equal(t, 0, calls)
+}
+
+func TestSdk_ConnectionLost_OtherMessage(t *testing.T) {
+	//arrange
+	s, adaptor := newObservedSdk(t)
+	calls := 0
+	s.SetOnConnectionLost(func() { calls++ })
+	s.ready.Store(true)
+
+	//act
+	adaptor.Warnf("Detected network interruption %s! Reconnecting...", errors.New("EOF"))
+
+	//assert
+	assert.Equal(t, 0, calls)
+}
+
+func TestSdk_Reconnect_AfterClose(t *testing.T) {
+	//arrange
+	s, _ := newObservedSdk(t)
+	s.processParameters = &server.ProcessParameters{Port: 7777}
+	s.ready.Store(true)
+	// what ProcessEnding and Destroy do before calling the server SDK, which panics when it is not initialized
+	s.close()
+
+	//act
+	err := s.Reconnect(context.Background(), server.ServerParameters{})
+
+	//assert
+	assert.ErrorIs(t, err, errClosed)
+	assert.False(t, s.ready.Load())
+	assert.Nil(t, s.processParameters)
+}
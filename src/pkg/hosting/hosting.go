@@ -8,6 +8,8 @@ package hosting
 import (
 	"context"
 	"net/netip"
+	"path/filepath"
+	"strings"
 
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/types/events"
 	"github.com/google/uuid"
@@ -41,3 +43,48 @@ type Service interface {
 	SetOnHealthCheck(f func(ctx context.Context) events.GameStatus)
 	Close(ctx context.Context) error
 }
+
+// SessionActions is implemented by hosting options that let the game server drive its game session.
+type SessionActions interface {
+	// ActivateGameSession reports the game session as ready for players. Repeated calls have no effect.
+	ActivateGameSession(ctx context.Context) error
+	// AcceptPlayerSession validates a player connecting to the game session.
+	AcceptPlayerSession(ctx context.Context, playerSessionId string) error
+	// RemovePlayerSession reports that a player left the game session.
+	RemovePlayerSession(ctx context.Context, playerSessionId string) error
+}
+
+// Drainable is implemented by hosting options that can be drained for maintenance.
+type Drainable interface {
+	// Drain stops taking new game sessions and denies new player sessions of the current one. It reports whether
+	// a game session is running, the wrapper exits once it ends.
+	Drain(ctx context.Context) (bool, error)
+}
+
+// SessionLogDirectory returns the directory the logs of a game session are written to, under the run log directory.
+// The game session id can be a full arn, only its last part is used.
+//
+// Parameters:
+//   - runLogDir: The log directory of the wrapper run
+//   - gameSessionId: The id of the game session
+//
+// Returns:
+//   - string: The session log directory, empty when runLogDir is empty
+func SessionLogDirectory(runLogDir string, gameSessionId string) string {
+	if len(runLogDir) == 0 {
+		return ""
+	}
+
+	id := gameSessionId[strings.LastIndex(gameSessionId, "/")+1:]
+	id = strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.' {
+			return r
+		}
+		return '_'
+	}, id)
+	if len(strings.Trim(id, ".")) == 0 {
+		id = "unknown"
+	}
+
+	return filepath.Join(runLogDir, "session_"+id)
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package local
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+)
+
+func (local *local) newApiHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /game-sessions", func(w http.ResponseWriter, r *http.Request) {
+		gameSession := &GameSession{}
+		if err := json.NewDecoder(r.Body).Decode(gameSession); err != nil && !errors.Is(err, io.EOF) {
+			http.Error(w, "invalid game session: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		hse, err := local.startGameSession(gameSession)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+
+		writeJSON(w, http.StatusAccepted, gameSessionResponse{GameSessionId: hse.GameSessionId})
+	})
+
+	mux.HandleFunc("POST /terminate", func(w http.ResponseWriter, r *http.Request) {
+		var notice time.Duration
+		if value := r.URL.Query().Get("notice"); len(value) != 0 {
+			var err error
+			if notice, err = time.ParseDuration(value); err != nil || notice < 0 {
+				http.Error(w, "invalid notice: "+value, http.StatusBadRequest)
+				return
+			}
+		}
+
+		local.terminate(notice)
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	mux.HandleFunc("GET /results", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, local.recorder.list())
+	})
+
+	return mux
+}
+
+type gameSessionResponse struct {
+	GameSessionId string `json:"gameSessionId"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
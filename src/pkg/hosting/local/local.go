@@ -0,0 +1,454 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/constants"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/network"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/observability"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/types/events"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// DefaultHealthCheckInterval matches the cadence at which the Amazon GameLift server SDK reports process health.
+const DefaultHealthCheckInterval = 60 * time.Second
+
+const (
+	localFleetId   = "fleet-local"
+	localIpAddress = "127.0.0.1"
+)
+
+// Config contains the settings of the local hosting provider.
+type Config struct {
+	GamePort            int
+	ScenarioFile        string        // Optional YAML scenario replayed when the provider runs
+	ApiAddress          string        // Optional loopback address of the HTTP API used to drive the provider
+	HealthCheckInterval time.Duration // Interval between health checks, defaults to DefaultHealthCheckInterval
+	ResultsFile         string        // Where the recorded results are written, defaults to the run log directory
+}
+
+type local struct {
+	logger   *slog.Logger
+	spanner  observability.Spanner
+	ctx      context.Context
+	cfg      *Config
+	ec       chan error
+	logDir   string
+	scenario *Scenario
+	recorder *recorder
+	server   *http.Server
+
+	mutex          sync.Mutex
+	sessionStarted bool
+	terminated     bool
+	draining       bool
+
+	onHealthCheck      func(ctx context.Context) events.GameStatus
+	onHostingStart     func(ctx context.Context, h *events.HostingStart, end <-chan error) error
+	onHostingTerminate func(ctx context.Context, h *events.HostingTerminate) error
+}
+
+// Init prepares the local hosting provider for a run.
+//
+// Parameters:
+//   - ctx: Context for the initialization process
+//   - args: Initialization arguments
+//
+// Returns:
+//   - *hosting.InitMeta: Metadata about the initialized hosting option
+//   - error: Any error that occurred during initialization
+func (local *local) Init(ctx context.Context, args *hosting.InitArgs) (*hosting.InitMeta, error) {
+	local.ctx = ctx
+
+	ctx, span, _ := local.spanner.NewSpan(ctx, "local hosting init", nil)
+	defer span.End()
+
+	if logDir, ok := ctx.Value(constants.ContextKeyRunLogDir).(string); ok {
+		local.logDir = logDir
+	}
+
+	workingDirectory, err := os.Getwd()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get working directory")
+	}
+
+	local.logger.InfoContext(ctx, "local hosting initialized",
+		"scenario", local.cfg.ScenarioFile,
+		"api", local.cfg.ApiAddress,
+		"healthCheckInterval", local.cfg.HealthCheckInterval)
+
+	return &hosting.InitMeta{
+		InstanceWorkingDirectory: workingDirectory,
+	}, nil
+}
+
+// Run drives the hosting lifecycle: it performs health checks, replays the scenario
+// and serves the loopback API until the context is done or an error occurs.
+//
+// Parameters:
+//   - ctx: Context for managing the hosting lifecycle
+//
+// Returns:
+//   - error: Any error that occurred while running
+func (local *local) Run(ctx context.Context) error {
+	local.ctx = ctx
+
+	ctx, span, _ := local.spanner.NewSpan(ctx, "local hosting run", nil)
+	defer span.End()
+
+	if len(local.cfg.ApiAddress) != 0 {
+		listener, err := net.Listen("tcp", local.cfg.ApiAddress)
+		if err != nil {
+			return errors.Wrapf(err, "failed to listen on '%s'", local.cfg.ApiAddress)
+		}
+
+		local.server = &http.Server{
+			Handler:           local.newApiHandler(),
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+
+		local.logger.InfoContext(ctx, "local hosting api listening", "address", listener.Addr().String())
+		go func() {
+			if err := local.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				local.fail(errors.Wrap(err, "local hosting api failed"))
+			}
+		}()
+	}
+
+	go local.healthCheckLoop(ctx)
+
+	if local.scenario != nil {
+		go local.runScenario(ctx)
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil
+	case err := <-local.ec:
+		local.logger.WarnContext(ctx, "error returned from local hosting", "err", err)
+		return err
+	}
+}
+
+// SetOnHostingStart registers a callback function that will be invoked when a hosting
+// start event occurs. The callback receives the hosting start event and an error channel.
+func (local *local) SetOnHostingStart(f func(ctx context.Context, h *events.HostingStart, end <-chan error) error) {
+	local.onHostingStart = f
+}
+
+// SetOnHostingTerminate registers a callback function that will be invoked when a hosting
+// termination event occurs.
+func (local *local) SetOnHostingTerminate(f func(ctx context.Context, h *events.HostingTerminate) error) {
+	local.onHostingTerminate = f
+}
+
+// SetOnHealthCheck registers a callback function that will be invoked to check the health
+// status of the game server. The callback should return the current game status.
+func (local *local) SetOnHealthCheck(f func(ctx context.Context) events.GameStatus) {
+	local.onHealthCheck = f
+}
+
+// Close stops the loopback API and writes the recorded results.
+func (local *local) Close(ctx context.Context) error {
+	local.logger.InfoContext(ctx, "cleaning up local hosting resources")
+
+	var err error
+	if local.server != nil {
+		if e := local.server.Shutdown(ctx); e != nil {
+			local.logger.ErrorContext(ctx, "failed to shut down local hosting api", "err", e)
+			err = e
+		}
+	}
+
+	local.recorder.add(Record{Kind: RecordKindProcessEnding})
+
+	resultsFile := local.cfg.ResultsFile
+	if len(resultsFile) == 0 && len(local.logDir) != 0 {
+		resultsFile = filepath.Join(local.logDir, ResultsFileName)
+	}
+
+	if len(resultsFile) == 0 {
+		local.logger.WarnContext(ctx, "no results file or log directory specified - local hosting results will not be saved")
+		return err
+	}
+
+	if e := local.recorder.write(resultsFile); e != nil {
+		local.logger.ErrorContext(ctx, "failed to write local hosting results", "err", e)
+		if err != nil {
+			err = errors.Wrapf(err, "%s", e.Error())
+		} else {
+			err = e
+		}
+	} else {
+		local.logger.InfoContext(ctx, "local hosting results written", "path", resultsFile)
+	}
+
+	return err
+}
+
+// ActivateGameSession records the activation of the game session.
+func (local *local) ActivateGameSession(ctx context.Context) error {
+	local.logger.DebugContext(ctx, "local hosting game session activated")
+	local.recorder.add(Record{Kind: RecordKindActivate})
+	return nil
+}
+
+// AcceptPlayerSession records a player connecting to the game session.
+func (local *local) AcceptPlayerSession(ctx context.Context, playerSessionId string) error {
+	local.logger.DebugContext(ctx, "local hosting player session accepted", "playerSessionId", playerSessionId)
+	local.recorder.add(Record{Kind: RecordKindAcceptPlayer, PlayerSessionId: playerSessionId})
+	return nil
+}
+
+// RemovePlayerSession records a player leaving the game session.
+func (local *local) RemovePlayerSession(ctx context.Context, playerSessionId string) error {
+	local.logger.DebugContext(ctx, "local hosting player session removed", "playerSessionId", playerSessionId)
+	local.recorder.add(Record{Kind: RecordKindRemovePlayer, PlayerSessionId: playerSessionId})
+	return nil
+}
+
+// Drain records the drain, and keeps a game session from being started afterwards.
+func (local *local) Drain(ctx context.Context) (bool, error) {
+	local.mutex.Lock()
+	local.draining = true
+	sessionStarted := local.sessionStarted
+	local.mutex.Unlock()
+
+	local.logger.DebugContext(ctx, "local hosting drained", "sessionStarted", sessionStarted)
+	local.recorder.add(Record{Kind: RecordKindDrain})
+	return sessionStarted, nil
+}
+
+func (local *local) healthCheckLoop(ctx context.Context) {
+	ticker := time.NewTicker(local.cfg.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			local.healthCheck()
+		}
+	}
+}
+
+func (local *local) healthCheck() {
+	if local.onHealthCheck == nil {
+		return
+	}
+
+	status := local.onHealthCheck(local.ctx)
+	healthy := status == events.GameStatusWaiting || status == events.GameStatusRunning
+
+	local.logger.DebugContext(local.ctx, "local hosting health check", "status", status, "healthy", healthy)
+	local.recorder.add(Record{Kind: RecordKindHealthCheck, Status: status, Healthy: &healthy})
+}
+
+func (local *local) runScenario(ctx context.Context) {
+	for i, step := range local.scenario.Steps {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(step.Delay):
+		}
+
+		local.logger.InfoContext(ctx, "running local scenario step", "step", i, "action", step.Action)
+
+		switch step.Action {
+		case ActionStartGameSession:
+			if _, err := local.startGameSession(step.GameSession); err != nil {
+				local.fail(errors.Wrapf(err, "scenario step %d failed", i))
+				return
+			}
+		case ActionTerminate:
+			local.terminate(step.Notice)
+		}
+	}
+
+	local.logger.InfoContext(ctx, "local scenario completed")
+}
+
+// startGameSession sends a hosting start event for the game session. Only one game session
+// can be started per run, as with a single Amazon GameLift server process.
+func (local *local) startGameSession(gameSession *GameSession) (*events.HostingStart, error) {
+	local.mutex.Lock()
+	if local.sessionStarted {
+		local.mutex.Unlock()
+		return nil, errors.New("a game session has already been started")
+	}
+	if local.draining {
+		local.mutex.Unlock()
+		return nil, errors.New("the wrapper is draining")
+	}
+	local.sessionStarted = true
+	local.mutex.Unlock()
+
+	hse, err := local.newHostingStart(gameSession)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		local.logger.DebugContext(local.ctx, "calling onHostingStart", "event", hse)
+		err := local.onHostingStart(local.ctx, hse, nil)
+
+		record := Record{Kind: RecordKindHostingStart, GameSessionId: hse.GameSessionId}
+		if err != nil {
+			record.Error = err.Error()
+			local.fail(err)
+		}
+		local.recorder.add(record)
+	}()
+
+	return hse, nil
+}
+
+// terminate sends a hosting terminate event, as Amazon GameLift does when the process is shut down.
+// A non-zero notice sets the termination time that long from now.
+func (local *local) terminate(notice time.Duration) {
+	local.mutex.Lock()
+	if local.terminated {
+		local.mutex.Unlock()
+		local.logger.DebugContext(local.ctx, "local hosting already terminated")
+		return
+	}
+	local.terminated = true
+	local.mutex.Unlock()
+
+	go func() {
+		terminate := &events.HostingTerminate{
+			Reason: events.HostingTerminateReasonHostingShutdown,
+		}
+		if notice > 0 {
+			terminate.TerminationTime = time.Now().Add(notice)
+		}
+		err := local.onHostingTerminate(local.ctx, terminate)
+
+		record := Record{Kind: RecordKindHostingTerminate}
+		if err != nil {
+			record.Error = err.Error()
+			local.fail(err)
+		}
+		local.recorder.add(record)
+	}()
+}
+
+func (local *local) newHostingStart(gameSession *GameSession) (*events.HostingStart, error) {
+	if gameSession == nil {
+		gameSession = &GameSession{}
+	}
+
+	gameProperties := ""
+	if gameSession.GameProperties != nil {
+		b, err := json.Marshal(gameSession.GameProperties)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse game properties: %w", err)
+		}
+		gameProperties = string(b)
+	}
+
+	gameSessionId := valueOrDefault(gameSession.GameSessionId, fmt.Sprintf("gsess-%s", uuid.New()))
+	hse := &events.HostingStart{
+		CliArgs:                   make([]config.CliArg, 0),
+		DNSName:                   gameSession.DnsName,
+		FleetId:                   valueOrDefault(gameSession.FleetId, localFleetId),
+		GamePort:                  local.cfg.GamePort,
+		GameProperties:            gameProperties,
+		GameSessionData:           gameSession.GameSessionData,
+		GameSessionId:             gameSessionId,
+		GameSessionName:           gameSession.Name,
+		IpAddress:                 valueOrDefault(gameSession.IpAddress, localIpAddress),
+		LogDirectory:              hosting.SessionLogDirectory(local.logDir, gameSessionId),
+		MatchmakerData:            gameSession.MatchmakerData,
+		MaximumPlayerSessionCount: gameSession.MaximumPlayerSessionCount,
+		Provider:                  config.ProviderLocal,
+	}
+
+	return hse, nil
+}
+
+// fail reports an error to Run without blocking once Run has returned.
+func (local *local) fail(err error) {
+	select {
+	case local.ec <- err:
+	default:
+		local.logger.ErrorContext(local.ctx, "local hosting error not delivered", "err", err)
+	}
+}
+
+func valueOrDefault(value string, defaultValue string) string {
+	if len(value) == 0 {
+		return defaultValue
+	}
+	return value
+}
+
+// New creates the local hosting provider.
+//
+// Parameters:
+//   - ctx: Context for creating the provider
+//   - cfg: Local hosting configuration
+//   - logger: Logger for hosting operations
+//   - spanner: Tracing provider
+//
+// Returns:
+//   - *local: The local hosting provider
+//   - error: Any error validating the configuration or loading the scenario
+func New(ctx context.Context, cfg *Config, logger *slog.Logger, spanner observability.Spanner) (*local, error) {
+	if cfg.GamePort <= 0 || cfg.GamePort >= 65535 {
+		return nil, errors.Errorf("game port needs to be a valid port: '%d'", cfg.GamePort)
+	}
+
+	if len(cfg.ScenarioFile) == 0 && len(cfg.ApiAddress) == 0 {
+		return nil, errors.New("local hosting requires a scenario file or an api address")
+	}
+
+	if len(cfg.ApiAddress) != 0 {
+		if err := network.ValidateLoopbackAddress(cfg.ApiAddress); err != nil {
+			return nil, errors.Wrapf(err, "invalid local hosting api address '%s'", cfg.ApiAddress)
+		}
+	}
+
+	if cfg.HealthCheckInterval <= 0 {
+		cfg.HealthCheckInterval = DefaultHealthCheckInterval
+	}
+
+	var scenario *Scenario
+	if len(cfg.ScenarioFile) != 0 {
+		s, err := LoadScenario(cfg.ScenarioFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load local hosting scenario")
+		}
+		scenario = s
+	}
+
+	l := &local{
+		cfg:      cfg,
+		ctx:      ctx,
+		ec:       make(chan error, 1),
+		logger:   logger,
+		spanner:  spanner,
+		scenario: scenario,
+		recorder: newRecorder(),
+	}
+
+	return l, nil
+}
@@ -0,0 +1,218 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package local
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/internal/mocks"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/constants"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/hosting"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/types/events"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+type LocalMockHelper struct {
+	logBuffer *bytes.Buffer
+	ctx       context.Context
+	cancel    context.CancelFunc
+	local     *local
+	logDir    string
+
+	mutex      sync.Mutex
+	starts     []*events.HostingStart
+	terminates []*events.HostingTerminate
+	started    chan struct{}
+	terminated chan struct{}
+}
+
+func createLocalMockHelper(t *testing.T, cfg *Config) *LocalMockHelper {
+	logBuffer := bytes.Buffer{}
+	logDir := t.TempDir()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	ctx = context.WithValue(ctx, constants.ContextKeyRunLogDir, logDir)
+	logger := slog.New(slog.NewTextHandler(&logBuffer, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	}))
+
+	l, err := New(ctx, cfg, logger, &mocks.SpannerMock{})
+	assert.Nil(t, err)
+
+	helper := &LocalMockHelper{
+		logBuffer:  &logBuffer,
+		ctx:        ctx,
+		cancel:     cancel,
+		local:      l,
+		logDir:     logDir,
+		started:    make(chan struct{}, 1),
+		terminated: make(chan struct{}, 1),
+	}
+
+	l.SetOnHealthCheck(func(ctx context.Context) events.GameStatus {
+		return events.GameStatusRunning
+	})
+	l.SetOnHostingStart(func(ctx context.Context, h *events.HostingStart, end <-chan error) error {
+		helper.mutex.Lock()
+		helper.starts = append(helper.starts, h)
+		helper.mutex.Unlock()
+		helper.started <- struct{}{}
+		return nil
+	})
+	l.SetOnHostingTerminate(func(ctx context.Context, h *events.HostingTerminate) error {
+		helper.mutex.Lock()
+		helper.terminates = append(helper.terminates, h)
+		helper.mutex.Unlock()
+		helper.terminated <- struct{}{}
+		return nil
+	})
+
+	_, err = l.Init(ctx, &hosting.InitArgs{})
+	assert.Nil(t, err)
+
+	return helper
+}
+
+func waitFor(t *testing.T, c <-chan struct{}) {
+	select {
+	case <-c:
+	case <-time.After(time.Second * 3):
+		assert.Fail(t, "timed out waiting for hosting event")
+	}
+}
+
+func TestNew_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *Config
+	}{
+		{"invalid port", &Config{GamePort: 0, ApiAddress: "127.0.0.1:0"}},
+		{"no scenario or api", &Config{GamePort: 100}},
+		{"non-loopback api", &Config{GamePort: 100, ApiAddress: "0.0.0.0:8080"}},
+		{"missing scenario", &Config{GamePort: 100, ScenarioFile: filepath.Join(t.TempDir(), "missing.yaml")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(context.Background(), tt.cfg, slog.Default(), &mocks.SpannerMock{})
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+func TestLocal_Run_Scenario(t *testing.T) {
+	//arrange
+	scenarioFile := filepath.Join(t.TempDir(), "scenario.yaml")
+	scenario := "steps:\n  - action: start-game-session\n    gameSession:\n      gameSessionId: gsess-1\n      gameProperties:\n        mode: ranked\n  - action: terminate\n    delay: 50ms\n    notice: 2m\n"
+	assert.Nil(t, os.WriteFile(scenarioFile, []byte(scenario), 0644))
+
+	helper := createLocalMockHelper(t, &Config{
+		GamePort:            100,
+		ScenarioFile:        scenarioFile,
+		HealthCheckInterval: 10 * time.Millisecond,
+	})
+	defer helper.cancel()
+
+	//act
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- helper.local.Run(helper.ctx)
+	}()
+	waitFor(t, helper.started)
+	waitFor(t, helper.terminated)
+	time.Sleep(30 * time.Millisecond)
+	helper.cancel()
+	assert.Nil(t, <-runErr)
+	err := helper.local.Close(context.Background())
+
+	//assert
+	assert.Nil(t, err)
+	assert.Len(t, helper.starts, 1)
+	assert.Equal(t, "gsess-1", helper.starts[0].GameSessionId)
+	assert.Equal(t, 100, helper.starts[0].GamePort)
+	assert.Equal(t, `{"mode":"ranked"}`, helper.starts[0].GameProperties)
+	assert.Equal(t, filepath.Join(helper.logDir, "session_gsess-1"), helper.starts[0].LogDirectory)
+	assert.Equal(t, config.ProviderLocal, helper.starts[0].Provider)
+	assert.Len(t, helper.terminates, 1)
+	assert.Equal(t, events.HostingTerminateReasonHostingShutdown, helper.terminates[0].Reason)
+	assert.WithinDuration(t, time.Now().Add(2*time.Minute), helper.terminates[0].TerminationTime, 10*time.Second)
+
+	b, err := os.ReadFile(filepath.Join(helper.logDir, ResultsFileName))
+	assert.Nil(t, err)
+	records := make([]Record, 0)
+	assert.Nil(t, json.Unmarshal(b, &records))
+	kinds := make(map[RecordKind]int)
+	for _, record := range records {
+		kinds[record.Kind]++
+	}
+	assert.Equal(t, 1, kinds[RecordKindHostingStart])
+	assert.Equal(t, 1, kinds[RecordKindHostingTerminate])
+	assert.Equal(t, 1, kinds[RecordKindProcessEnding])
+	assert.Greater(t, kinds[RecordKindHealthCheck], 0)
+}
+
+func TestLocal_Api(t *testing.T) {
+	//arrange
+	helper := createLocalMockHelper(t, &Config{
+		GamePort:   100,
+		ApiAddress: "127.0.0.1:0",
+	})
+	defer helper.cancel()
+	helper.local.ctx = helper.ctx
+	server := httptest.NewServer(helper.local.newApiHandler())
+	defer server.Close()
+
+	//act
+	startResponse, err := http.Post(server.URL+"/game-sessions", "application/json", strings.NewReader(`{"name":"my-session"}`))
+	assert.Nil(t, err)
+	waitFor(t, helper.started)
+	secondStartResponse, err := http.Post(server.URL+"/game-sessions", "application/json", nil)
+	assert.Nil(t, err)
+	terminateResponse, err := http.Post(server.URL+"/terminate", "application/json", nil)
+	assert.Nil(t, err)
+	waitFor(t, helper.terminated)
+
+	//assert
+	assert.Equal(t, http.StatusAccepted, startResponse.StatusCode)
+	started := gameSessionResponse{}
+	assert.Nil(t, json.NewDecoder(startResponse.Body).Decode(&started))
+	assert.True(t, strings.HasPrefix(started.GameSessionId, "gsess-"))
+	assert.Equal(t, "my-session", helper.starts[0].GameSessionName)
+	assert.Equal(t, started.GameSessionId, helper.starts[0].GameSessionId)
+	assert.Equal(t, http.StatusConflict, secondStartResponse.StatusCode)
+	assert.Equal(t, http.StatusAccepted, terminateResponse.StatusCode)
+}
+
+func TestLocal_Drain(t *testing.T) {
+	//arrange
+	helper := createLocalMockHelper(t, &Config{
+		GamePort:   100,
+		ApiAddress: "127.0.0.1:0",
+	})
+	defer helper.cancel()
+	helper.local.ctx = helper.ctx
+
+	//act
+	sessionRunning, err := helper.local.Drain(helper.ctx)
+	_, startErr := helper.local.startGameSession(nil)
+
+	//assert
+	assert.Nil(t, err)
+	assert.False(t, sessionRunning)
+	assert.ErrorContains(t, startErr, "draining")
+	assert.Equal(t, RecordKindDrain, helper.local.recorder.list()[0].Kind)
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package local
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/types/events"
+	"github.com/pkg/errors"
+)
+
+// ResultsFileName is the name of the results file written to the run log directory
+// when no results file is configured.
+const ResultsFileName = "local-hosting-results.json"
+
+// RecordKind identifies the hosting interaction a record describes.
+type RecordKind string
+
+const (
+	RecordKindHealthCheck      RecordKind = "health-check"
+	RecordKindHostingStart     RecordKind = "hosting-start"
+	RecordKindHostingTerminate RecordKind = "hosting-terminate"
+	RecordKindProcessEnding    RecordKind = "process-ending"
+	RecordKindActivate         RecordKind = "activate-game-session"
+	RecordKindAcceptPlayer     RecordKind = "accept-player-session"
+	RecordKindRemovePlayer     RecordKind = "remove-player-session"
+	RecordKindDrain            RecordKind = "drain"
+)
+
+// Record is a single hosting interaction observed by the local provider.
+type Record struct {
+	At              time.Time         `json:"at"`
+	Kind            RecordKind        `json:"kind"`
+	GameSessionId   string            `json:"gameSessionId,omitempty"`
+	PlayerSessionId string            `json:"playerSessionId,omitempty"`
+	Status          events.GameStatus `json:"status,omitempty"`
+	Healthy         *bool             `json:"healthy,omitempty"`
+	Error           string            `json:"error,omitempty"`
+}
+
+type recorder struct {
+	mutex   sync.Mutex
+	records []Record
+	now     func() time.Time
+}
+
+func (recorder *recorder) add(record Record) {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	record.At = recorder.now()
+	recorder.records = append(recorder.records, record)
+}
+
+func (recorder *recorder) list() []Record {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	records := make([]Record, len(recorder.records))
+	copy(records, recorder.records)
+	return records
+}
+
+func (recorder *recorder) write(path string) error {
+	b, err := json.MarshalIndent(recorder.list(), "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal local hosting results")
+	}
+
+	if err := os.WriteFile(path, b, 0644); err != nil {
+		return errors.Wrapf(err, "failed to write local hosting results to '%s'", path)
+	}
+
+	return nil
+}
+
+func newRecorder() *recorder {
+	return &recorder{
+		records: make([]Record, 0),
+		now:     time.Now,
+	}
+}
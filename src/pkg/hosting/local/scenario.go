@@ -0,0 +1,116 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package local
+
+import (
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Action is a hosting event that a scenario step triggers.
+type Action string
+
+const (
+	ActionStartGameSession Action = "start-game-session"
+	ActionTerminate        Action = "terminate"
+)
+
+// GameSession describes the game session passed to the game when a session is started.
+// Any field left empty is filled with a local default.
+type GameSession struct {
+	GameSessionId             string            `json:"gameSessionId" yaml:"gameSessionId"`
+	Name                      string            `json:"name" yaml:"name"`
+	FleetId                   string            `json:"fleetId" yaml:"fleetId"`
+	IpAddress                 string            `json:"ipAddress" yaml:"ipAddress"`
+	DnsName                   string            `json:"dnsName" yaml:"dnsName"`
+	GameProperties            map[string]string `json:"gameProperties" yaml:"gameProperties"`
+	GameSessionData           string            `json:"gameSessionData" yaml:"gameSessionData"`
+	MatchmakerData            string            `json:"matchmakerData" yaml:"matchmakerData"`
+	MaximumPlayerSessionCount int               `json:"maximumPlayerSessionCount" yaml:"maximumPlayerSessionCount"`
+}
+
+// Step is a single scripted hosting event, fired after waiting for Delay since the previous step.
+type Step struct {
+	Action      Action        `yaml:"action"`
+	Delay       time.Duration `yaml:"delay"`
+	GameSession *GameSession  `yaml:"gameSession"`
+	Notice      time.Duration `yaml:"notice"` // termination time after a terminate step, as on spot interruptions
+}
+
+// Scenario is an ordered list of hosting events replayed by the local provider.
+type Scenario struct {
+	Steps []Step `yaml:"steps"`
+}
+
+// LoadScenario reads and validates a YAML scenario file.
+//
+// Parameters:
+//   - path: Path of the scenario file
+//
+// Returns:
+//   - *Scenario: The parsed scenario
+//   - error: Any error reading, parsing or validating the scenario
+func LoadScenario(path string) (*Scenario, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read scenario file '%s'", path)
+	}
+
+	return ParseScenario(b)
+}
+
+// ParseScenario parses and validates a YAML scenario.
+//
+// Parameters:
+//   - b: The YAML document
+//
+// Returns:
+//   - *Scenario: The parsed scenario
+//   - error: Any error parsing or validating the scenario
+func ParseScenario(b []byte) (*Scenario, error) {
+	scenario := &Scenario{}
+	if err := yaml.Unmarshal(b, scenario); err != nil {
+		return nil, errors.Wrap(err, "failed to parse scenario")
+	}
+
+	if err := scenario.Validate(); err != nil {
+		return nil, err
+	}
+
+	return scenario, nil
+}
+
+// Validate checks that every step has a known action and a non-negative delay.
+func (scenario *Scenario) Validate() error {
+	for i, step := range scenario.Steps {
+		switch step.Action {
+		case ActionStartGameSession, ActionTerminate:
+		default:
+			return errors.Errorf("scenario step %d has unknown action '%s'", i, step.Action)
+		}
+
+		if step.Delay < 0 {
+			return errors.Errorf("scenario step %d has a negative delay", i)
+		}
+
+		if step.Notice < 0 {
+			return errors.Errorf("scenario step %d has a negative notice", i)
+		}
+
+		if step.Action != ActionTerminate && step.Notice != 0 {
+			return errors.Errorf("scenario step %d sets a notice on a '%s' action", i, step.Action)
+		}
+
+		if step.Action != ActionStartGameSession && step.GameSession != nil {
+			return errors.Errorf("scenario step %d sets a game session on a '%s' action", i, step.Action)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package local
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseScenario_HappyPath(t *testing.T) {
+	//arrange
+	doc := []byte(`
+steps:
+  - action: start-game-session
+    delay: 2s
+    gameSession:
+      gameSessionId: gsess-1
+      gameProperties:
+        mode: ranked
+      maximumPlayerSessionCount: 8
+  - action: terminate
+    delay: 1m
+`)
+
+	//act
+	scenario, err := ParseScenario(doc)
+
+	//assert
+	assert.Nil(t, err)
+	assert.Len(t, scenario.Steps, 2)
+	assert.Equal(t, ActionStartGameSession, scenario.Steps[0].Action)
+	assert.Equal(t, 2*time.Second, scenario.Steps[0].Delay)
+	assert.Equal(t, "gsess-1", scenario.Steps[0].GameSession.GameSessionId)
+	assert.Equal(t, "ranked", scenario.Steps[0].GameSession.GameProperties["mode"])
+	assert.Equal(t, 8, scenario.Steps[0].GameSession.MaximumPlayerSessionCount)
+	assert.Equal(t, ActionTerminate, scenario.Steps[1].Action)
+	assert.Equal(t, time.Minute, scenario.Steps[1].Delay)
+}
+
+func TestParseScenario_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+	}{
+		{"unknown action", "steps:\n  - action: explode\n"},
+		{"negative delay", "steps:\n  - action: terminate\n    delay: -1s\n"},
+		{"negative notice", "steps:\n  - action: terminate\n    notice: -1s\n"},
+		{"notice on start", "steps:\n  - action: start-game-session\n    notice: 1m\n"},
+		{"game session on terminate", "steps:\n  - action: terminate\n    gameSession:\n      name: x\n"},
+		{"not yaml", "steps: ["},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseScenario([]byte(tt.doc))
+			assert.NotNil(t, err)
+		})
+	}
+}
@@ -0,0 +1,393 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package logcollector
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/pkg/errors"
+)
+
+// ManifestFileName is the name of the manifest written to the run log directory.
+const ManifestFileName = "game-server-logs-manifest.json"
+
+const (
+	SkippedSymlink      = "symlink"
+	SkippedTotalSize    = "total size limit reached"
+	SkippedChanged      = "replaced during collection"
+	SkippedNotRegular   = "not a regular file"
+	compressedExtension = ".gz"
+)
+
+// File describes what was collected from a single game server log file.
+type File struct {
+	Path       string `json:"path"`      // relative to the game server log directory, with forward slashes
+	Size       int64  `json:"size"`      // size of the file when it was collected
+	Collected  int64  `json:"collected"` // bytes collected, before compression
+	Truncated  bool   `json:"truncated,omitempty"`
+	Compressed bool   `json:"compressed,omitempty"`
+	Skipped    string `json:"skipped,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Manifest lists the files collected from the game server log directory.
+type Manifest struct {
+	Source      string    `json:"source"`
+	Destination string    `json:"destination"`
+	CollectedAt time.Time `json:"collectedAt"`
+	Collected   int64     `json:"collected"` // bytes collected from all files, before compression
+	Files       []File    `json:"files"`
+}
+
+// Collector copies game server logs into the run log directory, so they are uploaded with it.
+type Collector struct {
+	cfg    config.LogCollection
+	logger *slog.Logger
+}
+
+type candidate struct {
+	rel  string
+	path string
+	info fs.FileInfo
+}
+
+// Collect copies the files of source matching the configuration into a directory of destination named after
+// source, and writes the manifest into destination. Symlinks are never followed. A file larger than the size
+// limit keeps its end, the most recent lines. When the total size limit is reached, the most recently
+// modified files are kept.
+//
+// Parameters:
+//   - ctx: Context bounding the collection
+//   - source: The game server log directory
+//   - destination: The run log directory
+//
+// Returns:
+//   - *Manifest: What was collected, nil when source does not exist
+//   - error: Any error collecting the logs, files that could not be collected do not stop the others
+func (collector *Collector) Collect(ctx context.Context, source string, destination string) (*Manifest, error) {
+	source = filepath.Clean(source)
+	info, err := os.Stat(source)
+	if os.IsNotExist(err) {
+		collector.logger.InfoContext(ctx, "game server log directory does not exist, skipping collection", "dir", source)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to access game server log directory '%s'", source)
+	}
+	if !info.IsDir() {
+		return nil, errors.Errorf("game server log directory '%s' is not a directory", source)
+	}
+
+	target := filepath.Join(destination, filepath.Base(source))
+	manifest := &Manifest{
+		Source:      source,
+		Destination: target,
+		CollectedAt: time.Now().UTC(),
+		Files:       make([]File, 0),
+	}
+
+	candidates, err := collector.walk(ctx, source, target, manifest)
+	if err != nil {
+		return manifest, err
+	}
+
+	// the most recent logs are the most useful when not everything fits
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].info.ModTime().After(candidates[j].info.ModTime())
+	})
+
+	failed := 0
+	for _, c := range candidates {
+		if err := ctx.Err(); err != nil {
+			return manifest, errors.Wrap(err, "game server log collection cancelled")
+		}
+
+		file := collector.collect(c, target, manifest.Collected)
+		manifest.Collected += file.Collected
+		manifest.Files = append(manifest.Files, file)
+		if len(file.Error) != 0 {
+			failed++
+			collector.logger.WarnContext(ctx, "failed to collect game server log file", "path", c.path, "err", file.Error)
+		}
+	}
+
+	if err := writeManifest(destination, manifest); err != nil {
+		return manifest, err
+	}
+
+	collector.logger.InfoContext(ctx, "collected game server logs", "source", source, "destination", target, "files", len(candidates)-failed, "bytes", manifest.Collected)
+	if failed != 0 {
+		return manifest, errors.Errorf("%d game server log files could not be collected", failed)
+	}
+	return manifest, nil
+}
+
+// walk lists the regular files to collect. Symlinks are recorded as skipped and the destination is never
+// walked, in case it is inside the source.
+func (collector *Collector) walk(ctx context.Context, source string, target string, manifest *Manifest) ([]candidate, error) {
+	candidates := make([]candidate, 0)
+	err := filepath.WalkDir(source, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == source {
+				return err
+			}
+			manifest.Files = append(manifest.Files, File{Path: relative(source, p), Error: err.Error()})
+			return nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if p == source {
+			return nil
+		}
+
+		rel := relative(source, p)
+		if d.IsDir() {
+			if filepath.Clean(p) == filepath.Clean(target) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		if !collector.selected(rel) {
+			return nil
+		}
+
+		switch {
+		case d.Type()&fs.ModeSymlink != 0:
+			manifest.Files = append(manifest.Files, File{Path: rel, Skipped: SkippedSymlink})
+		case !d.Type().IsRegular():
+			manifest.Files = append(manifest.Files, File{Path: rel, Skipped: SkippedNotRegular})
+		default:
+			info, err := d.Info()
+			if err != nil {
+				manifest.Files = append(manifest.Files, File{Path: rel, Error: err.Error()})
+				return nil
+			}
+			candidates = append(candidates, candidate{rel: rel, path: p, info: info})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list game server log directory '%s'", source)
+	}
+
+	return candidates, nil
+}
+
+func (collector *Collector) selected(rel string) bool {
+	if len(collector.cfg.Include) != 0 && !matchAny(collector.cfg.Include, rel) {
+		return false
+	}
+	return !matchAny(collector.cfg.Exclude, rel)
+}
+
+// collect copies a single file, within what is left of the total size limit.
+func (collector *Collector) collect(c candidate, target string, collected int64) File {
+	file := File{Path: c.rel, Size: c.info.Size(), Compressed: collector.cfg.Compress}
+
+	limit := collector.cfg.MaxFileSize
+	if collector.cfg.MaxTotalSize > 0 {
+		remaining := collector.cfg.MaxTotalSize - collected
+		if remaining <= 0 {
+			return File{Path: c.rel, Size: c.info.Size(), Skipped: SkippedTotalSize}
+		}
+		if limit <= 0 || remaining < limit {
+			limit = remaining
+		}
+	}
+
+	dst := filepath.Join(target, filepath.FromSlash(c.rel))
+	if collector.cfg.Compress {
+		dst += compressedExtension
+	}
+
+	size, copied, err := copyTail(c.path, c.info, dst, limit, collector.cfg.Compress)
+	file.Size = size
+	file.Collected = copied
+	file.Truncated = copied < size
+	if errors.Is(err, errChanged) {
+		file.Compressed = false
+		file.Skipped = SkippedChanged
+	} else if err != nil {
+		file.Compressed = false
+		file.Error = err.Error()
+	}
+	return file
+}
+
+var errChanged = errors.New("file changed")
+
+// copyTail copies at most limit bytes from the end of src to dst, gzipped if compress is set.
+// src must still be the file described by expected, so a file swapped for a symlink is not followed.
+func copyTail(src string, expected fs.FileInfo, dst string, limit int64, compress bool) (int64, int64, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return 0, 0, err
+	}
+	if !os.SameFile(expected, info) || !info.Mode().IsRegular() {
+		return 0, 0, errChanged
+	}
+
+	size := info.Size()
+	toCopy := size
+	if limit > 0 && size > limit {
+		toCopy = limit
+		if _, err := in.Seek(size-limit, io.SeekStart); err != nil {
+			return size, 0, err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return size, 0, err
+	}
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return size, 0, err
+	}
+
+	var w io.Writer = out
+	var gz *gzip.Writer
+	if compress {
+		gz = gzip.NewWriter(out)
+		w = gz
+	}
+
+	// the game can still be writing, only what was there when the file was opened is copied
+	copied, err := io.CopyN(w, in, toCopy)
+	if errors.Is(err, io.EOF) {
+		err = nil
+	}
+	if gz != nil {
+		if closeErr := gz.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+
+	return size, copied, err
+}
+
+func writeManifest(destination string, manifest *Manifest) error {
+	b, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to serialize game server log manifest")
+	}
+
+	if err := os.MkdirAll(destination, 0755); err != nil {
+		return errors.Wrapf(err, "failed to create directory '%s'", destination)
+	}
+
+	p := filepath.Join(destination, ManifestFileName)
+	if err := os.WriteFile(p, b, 0644); err != nil {
+		return errors.Wrapf(err, "failed to write game server log manifest '%s'", p)
+	}
+	return nil
+}
+
+func relative(base string, p string) string {
+	rel, err := filepath.Rel(base, p)
+	if err != nil {
+		rel = p
+	}
+	return filepath.ToSlash(rel)
+}
+
+// matchAny reports whether the relative path matches one of the globs. A glob without a slash matches the
+// file name, a glob with a slash the whole path, and a glob ending in /** everything under a directory.
+func matchAny(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		if match(pattern, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+func match(pattern string, rel string) bool {
+	if dir, ok := strings.CutSuffix(pattern, "/**"); ok {
+		segments := strings.Split(rel, "/")
+		depth := len(strings.Split(dir, "/"))
+		if depth >= len(segments) {
+			return false
+		}
+		matched, _ := path.Match(dir, strings.Join(segments[:depth], "/"))
+		return matched
+	}
+
+	if strings.Contains(pattern, "/") {
+		matched, _ := path.Match(pattern, rel)
+		return matched
+	}
+
+	matched, _ := path.Match(pattern, path.Base(rel))
+	return matched
+}
+
+// ValidatePatterns checks that the globs are well formed.
+//
+// Parameters:
+//   - patterns: The include or exclude globs
+//
+// Returns:
+//   - error: An error naming the first malformed glob
+func ValidatePatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if len(pattern) == 0 {
+			return errors.New("empty glob")
+		}
+		if _, err := path.Match(strings.TrimSuffix(pattern, "/**"), ""); err != nil {
+			return errors.Wrapf(err, "invalid glob '%s'", pattern)
+		}
+	}
+	return nil
+}
+
+// New creates a log collector.
+//
+// Parameters:
+//   - cfg: Which files are collected and how
+//   - logger: Logger for the collection
+//
+// Returns:
+//   - *Collector: The collector
+//   - error: An error if a glob is malformed or a size limit is negative
+func New(cfg config.LogCollection, logger *slog.Logger) (*Collector, error) {
+	if err := ValidatePatterns(cfg.Include); err != nil {
+		return nil, errors.Wrap(err, "invalid include")
+	}
+	if err := ValidatePatterns(cfg.Exclude); err != nil {
+		return nil, errors.Wrap(err, "invalid exclude")
+	}
+	if cfg.MaxFileSize < 0 || cfg.MaxTotalSize < 0 {
+		return nil, errors.New("size limits must not be negative")
+	}
+
+	return &Collector{
+		cfg:    cfg,
+		logger: logger,
+	}, nil
+}
@@ -0,0 +1,203 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package logcollector
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func createCollectorHelper(t *testing.T, cfg config.LogCollection) (*Collector, string, string) {
+	logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+	collector, err := New(cfg, logger)
+	assert.NoError(t, err)
+
+	source := filepath.Join(t.TempDir(), "logs")
+	assert.NoError(t, os.MkdirAll(source, 0755))
+	return collector, source, t.TempDir()
+}
+
+func writeLogFile(t *testing.T, dir string, name string, content string, modTime time.Time) {
+	p := filepath.Join(dir, filepath.FromSlash(name))
+	assert.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+	assert.NoError(t, os.WriteFile(p, []byte(content), 0644))
+	assert.NoError(t, os.Chtimes(p, modTime, modTime))
+}
+
+func readManifest(t *testing.T, destination string) Manifest {
+	b, err := os.ReadFile(filepath.Join(destination, ManifestFileName))
+	assert.NoError(t, err)
+
+	var manifest Manifest
+	assert.NoError(t, json.Unmarshal(b, &manifest))
+	return manifest
+}
+
+func TestCollect_CopiesTreeAndWritesManifest(t *testing.T) {
+	//arrange
+	collector, source, destination := createCollectorHelper(t, config.LogCollection{})
+	now := time.Now()
+	writeLogFile(t, source, "server.log", "hello", now)
+	writeLogFile(t, source, "crash/dump.txt", "boom", now)
+
+	//act
+	manifest, err := collector.Collect(context.Background(), source, destination)
+
+	//assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9), manifest.Collected)
+	b, err := os.ReadFile(filepath.Join(destination, "logs", "crash", "dump.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "boom", string(b))
+	assert.Len(t, readManifest(t, destination).Files, 2)
+}
+
+func TestCollect_IncludeExclude(t *testing.T) {
+	//arrange
+	collector, source, destination := createCollectorHelper(t, config.LogCollection{
+		Include: []string{"*.log", "crash/**"},
+		Exclude: []string{"debug-*.log"},
+	})
+	now := time.Now()
+	writeLogFile(t, source, "server.log", "a", now)
+	writeLogFile(t, source, "debug-1.log", "b", now)
+	writeLogFile(t, source, "data.bin", "c", now)
+	writeLogFile(t, source, "crash/core/dump", "d", now)
+
+	//act
+	manifest, err := collector.Collect(context.Background(), source, destination)
+
+	//assert
+	assert.NoError(t, err)
+	paths := []string{}
+	for _, file := range manifest.Files {
+		paths = append(paths, file.Path)
+	}
+	assert.ElementsMatch(t, []string{"server.log", "crash/core/dump"}, paths)
+}
+
+func TestCollect_MaxFileSizeKeepsTail(t *testing.T) {
+	//arrange
+	collector, source, destination := createCollectorHelper(t, config.LogCollection{MaxFileSize: 4})
+	writeLogFile(t, source, "server.log", "0123456789", time.Now())
+
+	//act
+	manifest, err := collector.Collect(context.Background(), source, destination)
+
+	//assert
+	assert.NoError(t, err)
+	b, _ := os.ReadFile(filepath.Join(destination, "logs", "server.log"))
+	assert.Equal(t, "6789", string(b))
+	assert.True(t, manifest.Files[0].Truncated)
+	assert.Equal(t, int64(10), manifest.Files[0].Size)
+	assert.Equal(t, int64(4), manifest.Files[0].Collected)
+}
+
+func TestCollect_MaxTotalSizePrefersRecentFiles(t *testing.T) {
+	//arrange
+	collector, source, destination := createCollectorHelper(t, config.LogCollection{MaxTotalSize: 8})
+	now := time.Now()
+	writeLogFile(t, source, "old.log", "oooooo", now.Add(-2*time.Hour))
+	writeLogFile(t, source, "older.log", "xxxxxx", now.Add(-3*time.Hour))
+	writeLogFile(t, source, "new.log", "nnnnnn", now)
+
+	//act
+	manifest, err := collector.Collect(context.Background(), source, destination)
+
+	//assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(8), manifest.Collected)
+	b, _ := os.ReadFile(filepath.Join(destination, "logs", "old.log"))
+	assert.Equal(t, "oo", string(b))
+	assert.Equal(t, "new.log", manifest.Files[0].Path)
+	assert.Equal(t, SkippedTotalSize, manifest.Files[2].Skipped)
+	assert.NoFileExists(t, filepath.Join(destination, "logs", "older.log"))
+}
+
+func TestCollect_Compress(t *testing.T) {
+	//arrange
+	collector, source, destination := createCollectorHelper(t, config.LogCollection{Compress: true})
+	writeLogFile(t, source, "server.log", "hello", time.Now())
+
+	//act
+	manifest, err := collector.Collect(context.Background(), source, destination)
+
+	//assert
+	assert.NoError(t, err)
+	assert.True(t, manifest.Files[0].Compressed)
+	f, err := os.Open(filepath.Join(destination, "logs", "server.log.gz"))
+	assert.NoError(t, err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	assert.NoError(t, err)
+	b, _ := io.ReadAll(gz)
+	assert.Equal(t, "hello", string(b))
+}
+
+func TestCollect_SkipsSymlinks(t *testing.T) {
+	//arrange
+	collector, source, destination := createCollectorHelper(t, config.LogCollection{})
+	secret := filepath.Join(t.TempDir(), "secret")
+	assert.NoError(t, os.WriteFile(secret, []byte("secret"), 0644))
+	if err := os.Symlink(secret, filepath.Join(source, "link.log")); err != nil {
+		t.Skip("symlinks not supported", err)
+	}
+
+	//act
+	manifest, err := collector.Collect(context.Background(), source, destination)
+
+	//assert
+	assert.NoError(t, err)
+	assert.Equal(t, SkippedSymlink, manifest.Files[0].Skipped)
+	assert.NoFileExists(t, filepath.Join(destination, "logs", "link.log"))
+}
+
+func TestCollect_DestinationInsideSource(t *testing.T) {
+	//arrange
+	collector, source, _ := createCollectorHelper(t, config.LogCollection{})
+	writeLogFile(t, source, "server.log", "hello", time.Now())
+	destination := source
+
+	//act
+	_, err := collector.Collect(context.Background(), source, destination)
+	_, err2 := collector.Collect(context.Background(), source, destination)
+
+	//assert
+	assert.NoError(t, err)
+	assert.NoError(t, err2)
+	assert.NoDirExists(t, filepath.Join(source, "logs", "logs"))
+}
+
+func TestCollect_MissingSource(t *testing.T) {
+	//arrange
+	collector, source, destination := createCollectorHelper(t, config.LogCollection{})
+
+	//act
+	manifest, err := collector.Collect(context.Background(), filepath.Join(source, "missing"), destination)
+
+	//assert
+	assert.NoError(t, err)
+	assert.Nil(t, manifest)
+}
+
+func TestNew_InvalidGlob(t *testing.T) {
+	//act
+	_, err := New(config.LogCollection{Exclude: []string{"[a-"}}, slog.Default())
+
+	//assert
+	assert.ErrorContains(t, err, "invalid exclude")
+}
@@ -6,7 +6,6 @@
 package logging
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"log/slog"
@@ -19,17 +18,17 @@ import (
 )
 
 type BufferedLogger struct {
-	buf     bytes.Buffer
-	scanner *bufio.Scanner
-	logger  Logger
-	file    *os.File
-	name    string
+	buf    bytes.Buffer
+	logger Logger
+	file   *os.File
+	name   string
 
 	level   slog.Level
 	mutex   sync.Mutex
 	closed  bool
 	ctx     context.Context
 	onClose func(context.Context) error
+	onLine  func(context.Context, string)
 }
 
 func NewBufferedLogger(ctx context.Context, logger Logger, name, logDirectory string) (*BufferedLogger, error) {
@@ -40,8 +39,6 @@ func NewBufferedLogger(ctx context.Context, logger Logger, name, logDirectory st
 		ctx:    context.WithValue(ctx, string(constants.ContextKeySource), name),
 	}
 
-	bufferedLogger.scanner = bufio.NewScanner(&bufferedLogger.buf)
-
 	if len(logDirectory) != 0 {
 		path := filepath.Join(logDirectory, name)
 
@@ -70,6 +67,13 @@ func (bufferedLogger *BufferedLogger) SetOnClosed(f func(ctx context.Context) er
 	bufferedLogger.onClose = f
 }
 
+// SetOnLine registers a function called with every complete line written, after it was logged.
+func (bufferedLogger *BufferedLogger) SetOnLine(f func(ctx context.Context, line string)) {
+	bufferedLogger.mutex.Lock()
+	defer bufferedLogger.mutex.Unlock()
+	bufferedLogger.onLine = f
+}
+
 func (bufferedLogger *BufferedLogger) File() *os.File {
 	return bufferedLogger.file
 }
@@ -82,25 +86,64 @@ func (bufferedLogger *BufferedLogger) Write(p []byte) (int, error) {
 		}
 	}
 
+	bufferedLogger.mutex.Lock()
 	n, e := bufferedLogger.buf.Write(p)
-	if bufferedLogger.scanner.Scan() {
-		line := bufferedLogger.scanner.Text()
-		bufferedLogger.logger.Log(bufferedLogger.ctx, bufferedLogger.level, line)
+	var lines []string
+	for {
+		i := bytes.IndexByte(bufferedLogger.buf.Bytes(), '\n')
+		if i < 0 {
+			break
+		}
+		line := bufferedLogger.buf.Next(i + 1)
+		lines = append(lines, bufferedLogger.emit(string(bytes.TrimRight(line, "\r\n"))))
 	}
+	onLine := bufferedLogger.onLine
+	bufferedLogger.mutex.Unlock()
+
+	// the line handler runs without the mutex, so it cannot hold up the other writers of the logger
+	bufferedLogger.handle(onLine, lines)
 
 	return n, e
 }
 
+// emit logs a line and returns it. The mutex must be held.
+func (bufferedLogger *BufferedLogger) emit(line string) string {
+	bufferedLogger.logger.Log(bufferedLogger.ctx, bufferedLogger.level, line)
+	return line
+}
+
+// handle passes the lines to the line handler, if there is one. The mutex must not be held.
+func (bufferedLogger *BufferedLogger) handle(onLine func(context.Context, string), lines []string) {
+	if onLine == nil {
+		return
+	}
+	for _, line := range lines {
+		onLine(bufferedLogger.ctx, line)
+	}
+}
+
 func (bufferedLogger *BufferedLogger) Close() error {
 	if bufferedLogger == nil {
 		return nil
 	}
 
 	bufferedLogger.mutex.Lock()
-	defer bufferedLogger.mutex.Unlock()
 	if bufferedLogger.closed {
+		bufferedLogger.mutex.Unlock()
 		return nil
 	}
+	bufferedLogger.closed = true
+
+	// a final line without a line ending is still output
+	var lines []string
+	if bufferedLogger.buf.Len() != 0 {
+		lines = append(lines, bufferedLogger.emit(string(bytes.TrimRight(bufferedLogger.buf.Bytes(), "\r"))))
+		bufferedLogger.buf.Reset()
+	}
+	onLine := bufferedLogger.onLine
+	bufferedLogger.mutex.Unlock()
+
+	bufferedLogger.handle(onLine, lines)
 
 	if bufferedLogger.onClose != nil {
 		bufferedLogger.onClose(bufferedLogger.ctx)
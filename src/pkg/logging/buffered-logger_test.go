@@ -224,3 +224,27 @@ func Test_BufferedLogger_WritesAsExpected(t *testing.T) {
 		})
 	}
 }
+
+func Test_BufferedLogger_SplitsLines(t *testing.T) {
+	//arrange
+	logger := newMockLogger()
+	bl, err := NewBufferedLogger(context.Background(), logger, "test", "")
+	assert.NoError(t, err)
+
+	var lines []string
+	bl.SetOnLine(func(ctx context.Context, line string) {
+		lines = append(lines, line)
+	})
+
+	//act
+	_, err = bl.Write([]byte("one\ntwo\r\nthr"))
+	assert.NoError(t, err)
+	_, err = bl.Write([]byte("ee\nfour"))
+	assert.NoError(t, err)
+	assert.NoError(t, bl.Close())
+
+	//assert
+	assert.Equal(t, []string{"one", "two", "three", "four"}, lines)
+	assert.Len(t, logger.infoContext, 4)
+	assert.Equal(t, "three", logger.infoContext[2].msg)
+}
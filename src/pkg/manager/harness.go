@@ -174,7 +174,12 @@ func (harness *harness) Run(ctx context.Context) error {
 
 		harness.logger.DebugContext(ctx, "Received hosting terminate event", "event", hostingTerminateEvent)
 
-		hostingTerminateErrorChannel <- harness.game.Stop(ctx)
+		trigger := game.TriggerForTerminateReason(hostingTerminateEvent.Reason)
+		stopCtx := game.WithTerminationTrigger(ctx, trigger)
+		if !hostingTerminateEvent.TerminationTime.IsZero() {
+			stopCtx = game.WithTerminationTime(stopCtx, hostingTerminateEvent.TerminationTime)
+		}
+		hostingTerminateErrorChannel <- harness.game.Stop(stopCtx)
 	}()
 
 	select {
@@ -261,6 +261,30 @@ func Test_Harness_Run_HappyPath_Terminate_Received(t *testing.T) {
 	assert.True(t, harnessTestHelper.GameService.StopCalled)
 }
 
+func Test_Harness_Run_Terminate_Received_TerminationTime(t *testing.T) {
+	//arrange
+	harnessTestHelper := CreateHarnessTestHelper(time.Second * 5)
+	terminationTime := time.Now().Add(time.Minute).Truncate(time.Second)
+
+	//act
+	go func() {
+		_ = harnessTestHelper.Harness.Run(harnessTestHelper.Ctx)
+	}()
+
+	harnessTestHelper.Harness.hostingTerminate <- &events.HostingTerminate{
+		Reason:          events.HostingTerminateReasonHostingShutdown,
+		TerminationTime: terminationTime,
+	}
+
+	time.Sleep(time.Second * 1)
+
+	//assert
+	assert.True(t, harnessTestHelper.GameService.StopCalled)
+	stopTime, ok := game.TerminationTimeFromContext(harnessTestHelper.GameService.StopCtx)
+	assert.True(t, ok)
+	assert.Equal(t, terminationTime, stopTime)
+}
+
 func Test_Harness_Close_HappyPath(t *testing.T) {
 	//arrange
 	harnessTestHelper := CreateHarnessTestHelper(time.Second * 5)
@@ -28,6 +28,7 @@ type GameServiceMock struct {
 	StopError  error
 	StopCalled bool
 	StopCount  int
+	StopCtx    context.Context
 
 	InitArgs   *game.InitArgs
 	StartArgs  *game.StartArgs
@@ -61,6 +62,7 @@ func (gameServiceMock *GameServiceMock) HealthCheck(ctx context.Context) events.
 func (gameServiceMock *GameServiceMock) Stop(ctx context.Context) error {
 	gameServiceMock.StopCalled = true
 	gameServiceMock.StopCount++
+	gameServiceMock.StopCtx = ctx
 	return gameServiceMock.StopError
 }
 
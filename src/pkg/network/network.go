@@ -0,0 +1,172 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package network builds the HTTP transport shared by the Amazon GameLift API client and the telemetry
+// exporters, from the proxy, certificate authority and timeout settings of the wrapper.
+package network
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/pkg/errors"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// ValidateLoopbackAddress ensures an address only exposes a server on the loopback interface. The host must
+// be localhost or a loopback ip address.
+func ValidateLoopbackAddress(address string) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	if host == "localhost" {
+		return nil
+	}
+
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return errors.New("host must be a loopback address")
+	}
+
+	return nil
+}
+
+// IsDefault reports whether cfg leaves the transport as the Go defaults, with the proxies of the environment.
+func IsDefault(cfg config.Network) bool {
+	return cfg.Proxy == (config.NetworkProxy{}) && len(cfg.CaBundle) == 0 && cfg.ConnectTimeout == 0 && cfg.RequestTimeout == 0
+}
+
+// Settings are the proxies, certificate authorities and request timeout of a network configuration, resolved
+// once so several HTTP clients can share them without reading the CA bundle again.
+type Settings struct {
+	Proxy     func(*http.Request) (*url.URL, error)
+	TLSConfig *tls.Config   // nil for the system certificate authorities
+	Timeout   time.Duration // zero for the default timeout of the client
+}
+
+// NewSettings resolves the settings of cfg, nil when cfg leaves the clients with their defaults. The connect
+// timeout is left out, the OTLP exporters the settings are for cannot be given a dialer, so it only applies
+// to the Amazon GameLift API client through TransportOptions.
+func NewSettings(cfg config.Network) (*Settings, error) {
+	if IsDefault(cfg) {
+		return nil, nil
+	}
+
+	tlsConfig, err := TLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Settings{
+		Proxy:     Proxy(cfg),
+		TLSConfig: tlsConfig,
+		Timeout:   cfg.RequestTimeout,
+	}, nil
+}
+
+// TransportOptions returns a function setting the proxies, certificate authorities and connect timeout of cfg
+// on a transport. The CA bundle is read once, when the function is created.
+func TransportOptions(cfg config.Network) (func(*http.Transport), error) {
+	tlsConfig, err := TLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	proxy := Proxy(cfg)
+	return func(transport *http.Transport) {
+		transport.Proxy = proxy
+		if tlsConfig != nil {
+			transport.TLSClientConfig = tlsConfig.Clone()
+		}
+
+		if cfg.ConnectTimeout > 0 {
+			dialer := &net.Dialer{
+				Timeout:   cfg.ConnectTimeout,
+				KeepAlive: 30 * time.Second,
+			}
+			transport.DialContext = dialer.DialContext
+			transport.TLSHandshakeTimeout = cfg.ConnectTimeout
+		}
+	}, nil
+}
+
+// Proxy returns the function choosing the proxy of a request. The proxies of the environment are used
+// when cfg sets none.
+func Proxy(cfg config.Network) func(*http.Request) (*url.URL, error) {
+	if cfg.Proxy == (config.NetworkProxy{}) {
+		return http.ProxyFromEnvironment
+	}
+
+	proxyFunc := (&httpproxy.Config{
+		HTTPProxy:  cfg.Proxy.Http,
+		HTTPSProxy: cfg.Proxy.Https,
+		NoProxy:    cfg.Proxy.NoProxy,
+	}).ProxyFunc()
+
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}
+}
+
+// TLSConfig returns a TLS configuration trusting the certificate authorities of the CA bundle on top of the
+// system ones, nil when cfg has no CA bundle.
+func TLSConfig(cfg config.Network) (*tls.Config, error) {
+	if len(cfg.CaBundle) == 0 {
+		return nil, nil
+	}
+
+	pem, err := os.ReadFile(cfg.CaBundle)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read ca bundle")
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.Errorf("no certificates found in ca bundle '%s'", cfg.CaBundle)
+	}
+
+	return &tls.Config{
+		RootCAs:    pool,
+		MinVersion: tls.VersionTLS12,
+	}, nil
+}
+
+// LogValue returns the effective settings of cfg, with the credentials of the proxy URLs redacted.
+func LogValue(cfg config.Network) slog.Value {
+	proxy := slog.StringValue("environment")
+	if cfg.Proxy != (config.NetworkProxy{}) {
+		proxy = slog.GroupValue(
+			slog.String("http", redactURL(cfg.Proxy.Http)),
+			slog.String("https", redactURL(cfg.Proxy.Https)),
+			slog.String("noProxy", cfg.Proxy.NoProxy))
+	}
+
+	return slog.GroupValue(
+		slog.String("gameLiftEndpoint", redactURL(cfg.GameLiftEndpoint)),
+		slog.Any("proxy", proxy),
+		slog.String("caBundle", cfg.CaBundle),
+		slog.Duration("connectTimeout", cfg.ConnectTimeout),
+		slog.Duration("requestTimeout", cfg.RequestTimeout))
+}
+
+// redactURL replaces the password of a URL, the proxy URLs can carry credentials.
+func redactURL(value string) string {
+	u, err := url.Parse(value)
+	if err != nil || u.User == nil {
+		return value
+	}
+	return u.Redacted()
+}
@@ -5,17 +5,22 @@
 
 package observability
 
-import "time"
+import (
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/network"
+)
 
 // Config defines the main configuration for observability features.
 type Config struct {
-	Name           string        `mapstructure:"-"`
-	Endpoint       string        `mapstructure:"endpoint,omitempty"`
-	Enabled        bool          `mapstructure:"enabled,omitempty"`
-	Retry          *RetryConfig  `mapstructure:"retry,omitempty"`
-	Insecure       bool          `mapstructure:"insecure,omitempty"`
-	Auth           *AuthConfig   `mapstructure:"auth,omitempty"`
-	ExportInterval time.Duration `mapstructure:"exportInterval,omitempty"`
+	Name           string            `mapstructure:"-"`
+	Endpoint       string            `mapstructure:"endpoint,omitempty"`
+	Enabled        bool              `mapstructure:"enabled,omitempty"`
+	Retry          *RetryConfig      `mapstructure:"retry,omitempty"`
+	Insecure       bool              `mapstructure:"insecure,omitempty"`
+	Auth           *AuthConfig       `mapstructure:"auth,omitempty"`
+	ExportInterval time.Duration     `mapstructure:"exportInterval,omitempty"`
+	Network        *network.Settings `mapstructure:"-"` // proxy, CA bundle and timeout of the exporters, nil for the defaults
 }
 
 // AuthConfig defines authentication credentials for the observability configuration.
@@ -72,6 +72,16 @@ func NewLoggingProvider(ctx context.Context, resource *resource.Resource, cfg *C
 		if cfg.Insecure {
 			opt = append(opt, otlploghttp.WithInsecure())
 		}
+		if cfg.Network != nil {
+			opt = append(opt, otlploghttp.WithProxy(cfg.Network.Proxy))
+			if cfg.Network.TLSConfig != nil {
+				opt = append(opt, otlploghttp.WithTLSClientConfig(cfg.Network.TLSConfig))
+			}
+			if cfg.Network.Timeout > 0 {
+				opt = append(opt, otlploghttp.WithTimeout(cfg.Network.Timeout))
+			}
+		}
+
 		exporter, err := otlploghttp.New(timeoutContext, opt...)
 		if err != nil {
 			return nil, err
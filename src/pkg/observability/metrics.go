@@ -70,6 +70,16 @@ func NewMetricsProvider(ctx context.Context, resource *resource.Resource, cfg *C
 			opt = append(opt, otlpmetrichttp.WithInsecure())
 		}
 
+		if cfg.Network != nil {
+			opt = append(opt, otlpmetrichttp.WithProxy(cfg.Network.Proxy))
+			if cfg.Network.TLSConfig != nil {
+				opt = append(opt, otlpmetrichttp.WithTLSClientConfig(cfg.Network.TLSConfig))
+			}
+			if cfg.Network.Timeout > 0 {
+				opt = append(opt, otlpmetrichttp.WithTimeout(cfg.Network.Timeout))
+			}
+		}
+
 		exporter, err := otlpmetrichttp.New(cc, opt...)
 		if err != nil {
 			return nil, err
@@ -71,6 +71,16 @@ func NewTracingProvider(ctx context.Context, resource *resource.Resource, cfg *C
 			opt = append(opt, otlptracehttp.WithInsecure())
 		}
 
+		if cfg.Network != nil {
+			opt = append(opt, otlptracehttp.WithProxy(cfg.Network.Proxy))
+			if cfg.Network.TLSConfig != nil {
+				opt = append(opt, otlptracehttp.WithTLSClientConfig(cfg.Network.TLSConfig))
+			}
+			if cfg.Network.Timeout > 0 {
+				opt = append(opt, otlptracehttp.WithTimeout(cfg.Network.Timeout))
+			}
+		}
+
 		exporter, err := otlptracehttp.New(contextWithTimeout, opt...)
 		if err != nil {
 			return nil, err
@@ -0,0 +1,218 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package ports
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/pkg/errors"
+)
+
+// GamePortName is the name the game port is allocated under.
+const GamePortName = "game"
+
+// Allocator picks ports for the game server and holds them until it is launched, so that nothing else
+// takes them in between.
+type Allocator struct {
+	logger       *slog.Logger
+	mutex        sync.Mutex
+	reservations []*reservation
+}
+
+// reservation holds a port open for both TCP and UDP.
+type reservation struct {
+	tcp net.Listener
+	udp net.PacketConn
+}
+
+func (reservation *reservation) close() {
+	_ = reservation.tcp.Close()
+	_ = reservation.udp.Close()
+}
+
+// Allocate picks the game port and the named ports. A port is only picked from a range when it is free for
+// both TCP and UDP, and it stays reserved until Release is called. A static game port is used as it is.
+//
+// Parameters:
+//   - ctx: Context for the allocation
+//   - cfg: The ranges to pick the ports from
+//   - gamePort: The static game port, used when no game port range is configured
+//
+// Returns:
+//   - map[string]int: The allocated ports by name, the game port is named GamePortName
+//   - error: An error if a range has no free port, nothing stays reserved then
+func (allocator *Allocator) Allocate(ctx context.Context, cfg config.PortAllocation, gamePort int) (map[string]int, error) {
+	allocated := make(map[string]int, len(cfg.Named)+1)
+
+	if cfg.Game.IsZero() {
+		allocated[GamePortName] = gamePort
+	} else {
+		port, err := allocator.reserve(cfg.Game, allocated)
+		if err != nil {
+			allocator.Release()
+			return nil, errors.Wrap(err, "failed to allocate game port")
+		}
+		allocated[GamePortName] = port
+	}
+
+	names := make([]string, 0, len(cfg.Named))
+	for name := range cfg.Named {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		port, err := allocator.reserve(cfg.Named[name], allocated)
+		if err != nil {
+			allocator.Release()
+			return nil, errors.Wrapf(err, "failed to allocate %s port", name)
+		}
+		allocated[name] = port
+	}
+
+	allocator.logger.InfoContext(ctx, "allocated game server ports", "ports", allocated)
+	return allocated, nil
+}
+
+// reserve binds the first port of the range that is free and not already allocated.
+func (allocator *Allocator) reserve(portRange config.PortRange, allocated map[string]int) (int, error) {
+	taken := make([]int, 0, len(allocated))
+	for _, port := range allocated {
+		taken = append(taken, port)
+	}
+
+	var lastErr error
+	for port := portRange.From; port <= portRange.To; port++ {
+		if slices.Contains(taken, port) {
+			continue
+		}
+
+		r, err := bind(port)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		allocator.mutex.Lock()
+		allocator.reservations = append(allocator.reservations, r)
+		allocator.mutex.Unlock()
+		return port, nil
+	}
+
+	if lastErr != nil {
+		return 0, errors.Wrapf(lastErr, "no free port in %d-%d", portRange.From, portRange.To)
+	}
+	return 0, errors.Errorf("no free port in %d-%d", portRange.From, portRange.To)
+}
+
+// Release frees the reserved ports, it is called right before the game server is launched so it can bind them.
+// Calling it again has no effect.
+func (allocator *Allocator) Release() {
+	allocator.mutex.Lock()
+	defer allocator.mutex.Unlock()
+
+	for _, r := range allocator.reservations {
+		r.close()
+	}
+	allocator.reservations = nil
+}
+
+// bind opens the port for TCP and UDP on all interfaces, which fails if anything already uses it.
+func bind(port int) (*reservation, error) {
+	address := ":" + strconv.Itoa(port)
+
+	tcp, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	udp, err := net.ListenPacket("udp", address)
+	if err != nil {
+		_ = tcp.Close()
+		return nil, err
+	}
+
+	return &reservation{tcp: tcp, udp: udp}, nil
+}
+
+// New creates a port allocator.
+//
+// Parameters:
+//   - logger: Logger for the allocation
+//
+// Returns:
+//   - *Allocator: The allocator
+func New(logger *slog.Logger) *Allocator {
+	return &Allocator{
+		logger: logger,
+	}
+}
+
+// ForInstance narrows the port configuration to the share of one of several wrapper instances running on the
+// same host. Every range is split into equal parts, and a static game port is offset by the instance index,
+// so the instances never compete for a port.
+//
+// Parameters:
+//   - cfg: The port ranges of the host
+//   - gamePort: The static game port, used when no game port range is configured
+//   - index: The index of the instance, from 0
+//   - count: The number of instances
+//
+// Returns:
+//   - config.PortAllocation: The port ranges of the instance
+//   - int: The static game port of the instance
+//   - error: An error if a range has fewer ports than instances
+func ForInstance(cfg config.PortAllocation, gamePort int, index int, count int) (config.PortAllocation, int, error) {
+	if count <= 1 {
+		return cfg, gamePort, nil
+	}
+	if index < 0 || index >= count {
+		return config.PortAllocation{}, 0, errors.Errorf("instance index %d is not between 0 and %d", index, count-1)
+	}
+
+	instance := config.PortAllocation{}
+
+	if cfg.Game.IsZero() {
+		if gamePort != 0 {
+			gamePort += index
+		}
+	} else {
+		game, err := share(cfg.Game, index, count)
+		if err != nil {
+			return config.PortAllocation{}, 0, errors.Wrap(err, "game port range")
+		}
+		instance.Game = game
+	}
+
+	if cfg.Named != nil {
+		instance.Named = make(map[string]config.PortRange, len(cfg.Named))
+	}
+	for name, portRange := range cfg.Named {
+		named, err := share(portRange, index, count)
+		if err != nil {
+			return config.PortAllocation{}, 0, errors.Wrapf(err, "%s port range", name)
+		}
+		instance.Named[name] = named
+	}
+
+	return instance, gamePort, nil
+}
+
+func share(portRange config.PortRange, index int, count int) (config.PortRange, error) {
+	size := (portRange.To - portRange.From + 1) / count
+	if size == 0 {
+		return config.PortRange{}, errors.Errorf("%d-%d has fewer ports than the %d instances", portRange.From, portRange.To, count)
+	}
+
+	from := portRange.From + index*size
+	return config.PortRange{From: from, To: from + size - 1}, nil
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package ports
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net"
+	"testing"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func createAllocatorHelper() *Allocator {
+	return New(slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil)))
+}
+
+// usedPort returns a port bound for UDP by the test, and the listener holding it.
+func usedPort(t *testing.T) (int, net.PacketConn) {
+	conn, err := net.ListenPacket("udp", ":0")
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn.LocalAddr().(*net.UDPAddr).Port, conn
+}
+
+func TestAllocate_StaticGamePort(t *testing.T) {
+	//arrange
+	allocator := createAllocatorHelper()
+
+	//act
+	allocated, err := allocator.Allocate(context.Background(), config.PortAllocation{}, 37016)
+
+	//assert
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{GamePortName: 37016}, allocated)
+}
+
+func TestAllocate_SkipsPortUsedForUdp(t *testing.T) {
+	//arrange
+	allocator := createAllocatorHelper()
+	defer allocator.Release()
+	port, _ := usedPort(t)
+
+	//act
+	allocated, err := allocator.Allocate(context.Background(), config.PortAllocation{
+		Game: config.PortRange{From: port, To: port + 20},
+	}, 0)
+
+	//assert
+	assert.NoError(t, err)
+	assert.NotEqual(t, port, allocated[GamePortName])
+	assert.Greater(t, allocated[GamePortName], port)
+}
+
+func TestAllocate_NamedPortsDoNotOverlap(t *testing.T) {
+	//arrange
+	allocator := createAllocatorHelper()
+	defer allocator.Release()
+	port, conn := usedPort(t)
+	_ = conn.Close()
+	portRange := config.PortRange{From: port, To: port + 20}
+
+	//act
+	allocated, err := allocator.Allocate(context.Background(), config.PortAllocation{
+		Game:  portRange,
+		Named: map[string]config.PortRange{"query": portRange, "rcon": portRange},
+	}, 0)
+
+	//assert
+	assert.NoError(t, err)
+	assert.Len(t, allocated, 3)
+	assert.NotEqual(t, allocated[GamePortName], allocated["query"])
+	assert.NotEqual(t, allocated["query"], allocated["rcon"])
+	assert.NotEqual(t, allocated[GamePortName], allocated["rcon"])
+}
+
+func TestAllocate_ReservesUntilRelease(t *testing.T) {
+	//arrange
+	allocator := createAllocatorHelper()
+	port, conn := usedPort(t)
+	_ = conn.Close()
+
+	//act
+	allocated, err := allocator.Allocate(context.Background(), config.PortAllocation{
+		Game: config.PortRange{From: port, To: port},
+	}, 0)
+
+	//assert
+	assert.NoError(t, err)
+	_, err = bind(allocated[GamePortName])
+	assert.Error(t, err)
+
+	allocator.Release()
+	r, err := bind(allocated[GamePortName])
+	assert.NoError(t, err)
+	r.close()
+}
+
+func TestAllocate_NoFreePort(t *testing.T) {
+	//arrange
+	allocator := createAllocatorHelper()
+	port, _ := usedPort(t)
+
+	//act
+	_, err := allocator.Allocate(context.Background(), config.PortAllocation{
+		Named: map[string]config.PortRange{"query": {From: port, To: port}},
+	}, 37016)
+
+	//assert
+	assert.ErrorContains(t, err, "failed to allocate query port")
+}
+
+func TestForInstance_SplitsRanges(t *testing.T) {
+	//arrange
+	cfg := config.PortAllocation{
+		Game:  config.PortRange{From: 7000, To: 7009},
+		Named: map[string]config.PortRange{"query": {From: 27000, To: 27004}},
+	}
+
+	//act
+	first, _, firstErr := ForInstance(cfg, 0, 0, 2)
+	second, _, secondErr := ForInstance(cfg, 0, 1, 2)
+
+	//assert
+	assert.NoError(t, firstErr)
+	assert.NoError(t, secondErr)
+	assert.Equal(t, config.PortRange{From: 7000, To: 7004}, first.Game)
+	assert.Equal(t, config.PortRange{From: 7005, To: 7009}, second.Game)
+	assert.Equal(t, config.PortRange{From: 27000, To: 27001}, first.Named["query"])
+	assert.Equal(t, config.PortRange{From: 27002, To: 27003}, second.Named["query"])
+}
+
+func TestForInstance_OffsetsStaticGamePort(t *testing.T) {
+	//act
+	_, gamePort, err := ForInstance(config.PortAllocation{}, 37016, 3, 4)
+
+	//assert
+	assert.NoError(t, err)
+	assert.Equal(t, 37019, gamePort)
+}
+
+func TestForInstance_RangeTooSmall(t *testing.T) {
+	//act
+	_, _, err := ForInstance(config.PortAllocation{
+		Named: map[string]config.PortRange{"rcon": {From: 27100, To: 27100}},
+	}, 37016, 0, 2)
+
+	//assert
+	assert.ErrorContains(t, err, "rcon port range")
+}
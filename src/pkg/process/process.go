@@ -14,10 +14,15 @@ import (
 	"os/exec"
 	"path/filepath"
 	"syscall"
+	"time"
 
 	"github.com/pkg/errors"
 )
 
+// waitDelay bounds how long Run waits for the output of a killed process to be drained, as
+// children of the game process can keep its stdout and stderr open after it has exited.
+const waitDelay = 5 * time.Second
+
 // Result represents the outcome of a process execution.
 type Result struct {
 	ReturnCode int
@@ -33,6 +38,7 @@ type State struct {
 // Args contains the arguments and I/O configuration for process execution.
 type Args struct {
 	CliArgs []string
+	Stdin   io.Reader // nil connects stdin to the null device
 	Stdout  io.Writer
 	Stderr  io.Writer
 }
@@ -42,6 +48,7 @@ type Process interface {
 	Init(ctx context.Context) error
 	Run(ctx context.Context, args *Args, pidChan chan<- int) (*Result, error)
 	State() *State
+	Signal(sig os.Signal) error
 }
 
 type process struct {
@@ -69,6 +76,15 @@ func (process *process) State() *State {
 	return state
 }
 
+// Signal sends a signal to the running process.
+func (process *process) Signal(sig os.Signal) error {
+	if process.cmd == nil || process.cmd.Process == nil {
+		return errors.New("process is not running")
+	}
+
+	return process.cmd.Process.Signal(sig)
+}
+
 func (process *process) Init(ctx context.Context) error {
 	if process.cfg == nil {
 		return errors.New("Process configuration is nil")
@@ -96,9 +112,11 @@ func (process *process) Run(ctx context.Context, args *Args, pidChan chan<- int)
 	process.logger.DebugContext(ctx, "Preparing command", "path", process.exePath, "workingDir", process.cfg.WorkingDirectory)
 
 	process.cmd = exec.CommandContext(ctx, process.exePath, args.CliArgs...)
+	process.cmd.Stdin = args.Stdin
 	process.cmd.Stderr = args.Stderr
 	process.cmd.Stdout = args.Stdout
 	process.cmd.Dir = process.cfg.WorkingDirectory
+	process.cmd.WaitDelay = waitDelay
 
 	if process.cfg.EnvVars != nil {
 		env := make([]string, 0)
@@ -126,7 +144,9 @@ func (process *process) Run(ctx context.Context, args *Args, pidChan chan<- int)
 	var ee *exec.ExitError
 	if errors.As(err, &ee) {
 		ws := ee.Sys().(syscall.WaitStatus)
-		res.Signal = ws.Signal()
+		if ws.Signaled() {
+			res.Signal = ws.Signal()
+		}
 		// it's been killed by either the context or by an external pid termination command
 		if ws.Signal() == syscall.SIGKILL {
 			process.logger.DebugContext(ctx, "Process terminated by signal",
@@ -19,6 +19,23 @@ import (
 	"golang.org/x/sys/unix"
 )
 
+// ParseSignal returns the signal with the given name, such as SIGTERM or USR1.
+//
+// Parameters:
+//   - name: The signal name, with or without the SIG prefix
+//
+// Returns:
+//   - os.Signal: The signal
+//   - error: An error if the signal is unknown
+func ParseSignal(name string) (os.Signal, error) {
+	sig := unix.SignalNum("SIG" + strings.TrimPrefix(strings.ToUpper(name), "SIG"))
+	if sig == 0 {
+		return nil, errors.Errorf("unknown signal '%s'", name)
+	}
+
+	return sig, nil
+}
+
 func ensureExecutable(fi os.FileInfo, path string) error {
 	m := fi.Mode()
 
@@ -9,8 +9,23 @@ package process
 
 import (
 	"os"
+
+	"github.com/pkg/errors"
 )
 
+// ParseSignal returns the signal with the given name. Windows processes can only be killed, which is not a
+// notification, so no signal is supported.
+//
+// Parameters:
+//   - name: The signal name
+//
+// Returns:
+//   - os.Signal: Always nil
+//   - error: An error as signals are not supported
+func ParseSignal(name string) (os.Signal, error) {
+	return nil, errors.Errorf("signal '%s' is not supported on windows", name)
+}
+
 func ensureExecutable(fi os.FileInfo, path string) error {
 	// check path is executable by running user in windows
 	isExecAny(fi.Mode())
@@ -0,0 +1,490 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package supervisor runs several wrapper instances on one host and restarts them when they exit.
+//
+// Each instance is a child wrapper process rather than a goroutine running its own service in this process.
+// The server SDK for Amazon GameLift Servers keeps its connection, process state and callbacks in package
+// variables of its server package, so a process can hold a single SDK connection. Running the instances
+// in one process would have them share, and tear down, each other's connection.
+package supervisor
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/constants"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/drain"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/process"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	DefaultStartInterval   = 2 * time.Second
+	DefaultStopTimeout     = time.Minute
+	DefaultRestartDelay    = time.Second
+	DefaultMaxRestartDelay = time.Minute
+	DefaultStatusInterval  = time.Minute
+
+	// an instance running for this long before exiting restarts without backoff
+	stableUptime = time.Minute
+)
+
+// State is the state of a wrapper instance.
+type State string
+
+const (
+	StateRunning    State = "running"
+	StateRestarting State = "restarting" // exited, waiting for its restart delay
+	StateStopped    State = "stopped"
+	StateDrained    State = "drained" // exited after being drained, it is not restarted
+)
+
+// Command is the wrapper command run for every instance.
+type Command struct {
+	Path string
+	Args []string
+	Env  []string // the instance index and count are added to it
+}
+
+// InstanceStatus describes a wrapper instance.
+type InstanceStatus struct {
+	Index        int       `json:"index"`
+	Pid          int       `json:"pid,omitempty"`
+	State        State     `json:"state"`
+	Restarts     int       `json:"restarts"`
+	LastExitCode int       `json:"lastExitCode"`
+	StartedAt    time.Time `json:"startedAt,omitempty"`
+}
+
+// Supervisor runs several wrapper instances on the same host, each in its own process as the Amazon GameLift
+// server SDK supports a single connection per process. Instances that exit are restarted until the supervisor
+// is stopped, unless they were drained.
+type Supervisor struct {
+	cfg     config.Supervisor
+	command Command
+	logger  *slog.Logger
+
+	mutex     sync.Mutex
+	instances []*instance
+	draining  bool
+	drains    chan struct{}
+
+	restarts metric.Int64Counter
+}
+
+type instance struct {
+	status InstanceStatus
+	cmd    *exec.Cmd
+	delay  time.Duration // restart delay, doubled while the instance keeps failing
+	timer  *time.Timer   // pending start
+}
+
+type exit struct {
+	index int
+	err   error
+}
+
+// Run starts the instances, one every start interval, and supervises them until ctx is done or every instance
+// was drained. On ctx done, the instances are asked to stop, and killed when they have not stopped within the
+// stop timeout.
+//
+// Parameters:
+//   - ctx: Context whose cancellation stops the instances
+//
+// Returns:
+//   - error: An error if an instance could not be stopped
+func (supervisor *Supervisor) Run(ctx context.Context) error {
+	exits := make(chan exit, len(supervisor.instances))
+	starts := make(chan int, len(supervisor.instances))
+
+	for i := range supervisor.instances {
+		supervisor.schedule(i, time.Duration(i)*supervisor.cfg.StartInterval, starts)
+	}
+
+	supervisor.logger.InfoContext(ctx, "supervising wrapper instances", "instances", len(supervisor.instances))
+
+	ticker := time.NewTicker(supervisor.cfg.StatusInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return supervisor.shutdown(ctx, exits)
+		case index := <-starts:
+			if supervisor.state(index) == StateDrained {
+				// the start was pending when the instances were drained
+				continue
+			}
+			if err := supervisor.start(ctx, index, exits); err != nil {
+				supervisor.logger.ErrorContext(ctx, "failed to start wrapper instance", "instance", index, "err", err)
+				supervisor.restartLater(ctx, index, false, starts)
+			}
+			supervisor.logStatus(ctx)
+		case e := <-exits:
+			clean := supervisor.exited(ctx, e)
+			if supervisor.state(e.index) != StateDrained {
+				supervisor.restartLater(ctx, e.index, clean, starts)
+			}
+			supervisor.logStatus(ctx)
+			if supervisor.Drained() {
+				supervisor.logger.InfoContext(ctx, "all wrapper instances drained")
+				return nil
+			}
+		case <-supervisor.drains:
+			supervisor.drain(ctx)
+			supervisor.logStatus(ctx)
+			if supervisor.Drained() {
+				supervisor.logger.InfoContext(ctx, "all wrapper instances drained")
+				return nil
+			}
+		case <-ticker.C:
+			supervisor.logStatus(ctx)
+		}
+	}
+}
+
+// Status returns the status of every instance.
+//
+// Returns:
+//   - []InstanceStatus: The instances, by index
+func (supervisor *Supervisor) Status() []InstanceStatus {
+	supervisor.mutex.Lock()
+	defer supervisor.mutex.Unlock()
+
+	statuses := make([]InstanceStatus, 0, len(supervisor.instances))
+	for _, i := range supervisor.instances {
+		statuses = append(statuses, i.status)
+	}
+	return statuses
+}
+
+// Drain drains every instance: running instances are sent the drain signal, and instances waiting to start or
+// restart are not started anymore. The instances are not restarted once they exit.
+func (supervisor *Supervisor) Drain() {
+	select {
+	case supervisor.drains <- struct{}{}:
+	default:
+		// a drain is already pending
+	}
+}
+
+// Drained reports whether every instance was drained.
+//
+// Returns:
+//   - bool: True once no instance is left to run
+func (supervisor *Supervisor) Drained() bool {
+	for _, status := range supervisor.Status() {
+		if status.State != StateDrained {
+			return false
+		}
+	}
+	return true
+}
+
+func (supervisor *Supervisor) state(index int) State {
+	supervisor.mutex.Lock()
+	defer supervisor.mutex.Unlock()
+
+	return supervisor.instances[index].status.State
+}
+
+func (supervisor *Supervisor) drain(ctx context.Context) {
+	sig, err := process.ParseSignal(drain.Signal)
+	if err != nil {
+		supervisor.logger.WarnContext(ctx, "the instances cannot be drained by signal, create the drain file instead", "err", err)
+		return
+	}
+
+	supervisor.logger.InfoContext(ctx, "draining wrapper instances")
+
+	supervisor.mutex.Lock()
+	defer supervisor.mutex.Unlock()
+
+	supervisor.draining = true
+	for index, i := range supervisor.instances {
+		if i.timer != nil {
+			i.timer.Stop()
+			i.timer = nil
+		}
+		if i.cmd == nil {
+			i.status.State = StateDrained
+			continue
+		}
+		if err := i.cmd.Process.Signal(sig); err != nil {
+			supervisor.logger.WarnContext(ctx, "failed to drain wrapper instance", "instance", index, "err", err)
+		}
+	}
+}
+
+func (supervisor *Supervisor) start(ctx context.Context, index int, exits chan<- exit) error {
+	cmd := exec.Command(supervisor.command.Path, supervisor.command.Args...)
+	cmd.Env = append(append([]string{}, supervisor.command.Env...),
+		constants.EnvironmentKeyInstanceIndex+"="+strconv.Itoa(index),
+		constants.EnvironmentKeyInstanceCount+"="+strconv.Itoa(len(supervisor.instances)),
+	)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Start(); err != nil {
+		return errors.Wrapf(err, "failed to run '%s'", supervisor.command.Path)
+	}
+
+	supervisor.mutex.Lock()
+	i := supervisor.instances[index]
+	restarted := !i.status.StartedAt.IsZero()
+	i.cmd = cmd
+	i.timer = nil
+	i.status.Pid = cmd.Process.Pid
+	i.status.State = StateRunning
+	i.status.StartedAt = time.Now()
+	if restarted {
+		i.status.Restarts++
+	}
+	supervisor.mutex.Unlock()
+
+	if restarted {
+		supervisor.restarts.Add(ctx, 1, metric.WithAttributes(attribute.Int("instance", index)))
+	}
+	supervisor.logger.InfoContext(ctx, "started wrapper instance", "instance", index, "pid", cmd.Process.Pid)
+
+	go func() {
+		exits <- exit{index: index, err: cmd.Wait()}
+	}()
+	return nil
+}
+
+// exited records the exit of an instance and reports whether it exited cleanly.
+func (supervisor *Supervisor) exited(ctx context.Context, e exit) bool {
+	code := exitCode(e.err)
+
+	supervisor.mutex.Lock()
+	i := supervisor.instances[e.index]
+	uptime := time.Since(i.status.StartedAt)
+	i.cmd = nil
+	i.status.Pid = 0
+	i.status.State = StateStopped
+	i.status.LastExitCode = code
+	if uptime >= stableUptime {
+		i.delay = 0
+	}
+	drained := code == constants.ExitCodeDrained || supervisor.draining
+	if drained {
+		i.status.State = StateDrained
+	}
+	supervisor.mutex.Unlock()
+
+	if drained {
+		supervisor.logger.InfoContext(ctx, "wrapper instance drained", "instance", e.index, "uptime", uptime, "exitCode", code)
+	} else if code == 0 {
+		supervisor.logger.InfoContext(ctx, "wrapper instance exited", "instance", e.index, "uptime", uptime)
+	} else {
+		supervisor.logger.WarnContext(ctx, "wrapper instance failed", "instance", e.index, "uptime", uptime, "exitCode", code, "err", e.err)
+	}
+	return code == 0
+}
+
+// restartLater schedules the restart of an instance. Failing instances back off up to the maximum restart delay.
+func (supervisor *Supervisor) restartLater(ctx context.Context, index int, clean bool, starts chan<- int) {
+	supervisor.mutex.Lock()
+	i := supervisor.instances[index]
+	switch {
+	case clean || i.delay == 0:
+		i.delay = supervisor.cfg.RestartDelay
+	default:
+		i.delay = min(i.delay*2, supervisor.cfg.MaxRestartDelay)
+	}
+	delay := i.delay
+	i.status.State = StateRestarting
+	supervisor.mutex.Unlock()
+
+	supervisor.logger.InfoContext(ctx, "restarting wrapper instance", "instance", index, "in", delay)
+	supervisor.schedule(index, delay, starts)
+}
+
+func (supervisor *Supervisor) schedule(index int, delay time.Duration, starts chan<- int) {
+	supervisor.mutex.Lock()
+	defer supervisor.mutex.Unlock()
+
+	// starts holds one slot per instance, and an instance has at most one pending start
+	supervisor.instances[index].timer = time.AfterFunc(delay, func() {
+		starts <- index
+	})
+}
+
+// shutdown asks the running instances to stop, kills those still running after the stop timeout, and
+// waits for all of them to exit.
+func (supervisor *Supervisor) shutdown(ctx context.Context, exits <-chan exit) error {
+	ctx = context.WithoutCancel(ctx)
+
+	running := 0
+	supervisor.mutex.Lock()
+	for index, i := range supervisor.instances {
+		if i.timer != nil {
+			i.timer.Stop()
+			i.timer = nil
+		}
+		if i.cmd == nil {
+			i.status.State = StateStopped
+			continue
+		}
+		running++
+		if err := stop(i.cmd.Process); err != nil {
+			supervisor.logger.WarnContext(ctx, "failed to stop wrapper instance", "instance", index, "err", err)
+		}
+	}
+	supervisor.mutex.Unlock()
+
+	supervisor.logger.InfoContext(ctx, "stopping wrapper instances", "running", running, "timeout", supervisor.cfg.StopTimeout)
+
+	timeout := time.NewTimer(supervisor.cfg.StopTimeout)
+	defer timeout.Stop()
+
+	killed := 0
+	for running > 0 {
+		select {
+		case e := <-exits:
+			supervisor.exited(ctx, e)
+			running--
+		case <-timeout.C:
+			supervisor.mutex.Lock()
+			for index, i := range supervisor.instances {
+				if i.cmd == nil {
+					continue
+				}
+				killed++
+				supervisor.logger.ErrorContext(ctx, "wrapper instance did not stop in time, killing it", "instance", index, "pid", i.status.Pid)
+				_ = i.cmd.Process.Kill()
+			}
+			supervisor.mutex.Unlock()
+		}
+	}
+
+	supervisor.logStatus(ctx)
+	if killed != 0 {
+		return errors.Errorf("%d wrapper instances were killed after not stopping within %s", killed, supervisor.cfg.StopTimeout)
+	}
+	return nil
+}
+
+func (supervisor *Supervisor) logStatus(ctx context.Context) {
+	statuses := supervisor.Status()
+	counts := make(map[State]int)
+	for _, status := range statuses {
+		counts[status.State]++
+	}
+
+	supervisor.logger.InfoContext(ctx, "wrapper instances",
+		"running", counts[StateRunning],
+		"restarting", counts[StateRestarting],
+		"stopped", counts[StateStopped],
+		"drained", counts[StateDrained],
+		"instances", statuses)
+}
+
+// stop asks a process to stop. Windows has no SIGTERM, the process is killed there.
+func stop(process *os.Process) error {
+	if runtime.GOOS == "windows" {
+		return process.Kill()
+	}
+	return process.Signal(syscall.SIGTERM)
+}
+
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+// withDefaults fills the unset fields of the configuration with the defaults.
+func withDefaults(cfg config.Supervisor) config.Supervisor {
+	if cfg.StartInterval <= 0 {
+		cfg.StartInterval = DefaultStartInterval
+	}
+	if cfg.StopTimeout <= 0 {
+		cfg.StopTimeout = DefaultStopTimeout
+	}
+	if cfg.RestartDelay <= 0 {
+		cfg.RestartDelay = DefaultRestartDelay
+	}
+	if cfg.MaxRestartDelay < cfg.RestartDelay {
+		cfg.MaxRestartDelay = max(DefaultMaxRestartDelay, cfg.RestartDelay)
+	}
+	if cfg.StatusInterval <= 0 {
+		cfg.StatusInterval = DefaultStatusInterval
+	}
+	return cfg
+}
+
+// New creates a supervisor.
+//
+// Parameters:
+//   - cfg: The number of instances and how they are started, restarted and stopped
+//   - command: The wrapper command run for every instance
+//   - logger: Logger for the supervisor
+//   - meter: Meter for the instance metrics
+//
+// Returns:
+//   - *Supervisor: The supervisor
+//   - error: An error if there are no instances or the metrics cannot be created
+func New(cfg config.Supervisor, command Command, logger *slog.Logger, meter metric.Meter) (*Supervisor, error) {
+	if cfg.Instances < 1 {
+		return nil, errors.Errorf("the number of instances must be at least 1, got %d", cfg.Instances)
+	}
+	if len(command.Path) == 0 {
+		return nil, errors.New("no command to run")
+	}
+
+	supervisor := &Supervisor{
+		cfg:       withDefaults(cfg),
+		command:   command,
+		logger:    logger,
+		instances: make([]*instance, cfg.Instances),
+		drains:    make(chan struct{}, 1),
+	}
+	for i := range supervisor.instances {
+		supervisor.instances[i] = &instance{status: InstanceStatus{Index: i, State: StateStopped}}
+	}
+
+	restarts, err := meter.Int64Counter("supervisor.instance.restarts",
+		metric.WithDescription("Number of wrapper instance restarts"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create instance restart counter")
+	}
+	supervisor.restarts = restarts
+
+	_, err = meter.Int64ObservableGauge("supervisor.instances",
+		metric.WithDescription("Number of wrapper instances by state"),
+		metric.WithInt64Callback(func(ctx context.Context, observer metric.Int64Observer) error {
+			counts := map[State]int64{StateRunning: 0, StateRestarting: 0, StateStopped: 0, StateDrained: 0}
+			for _, status := range supervisor.Status() {
+				counts[status.State]++
+			}
+			for state, count := range counts {
+				observer.Observe(count, metric.WithAttributes(attribute.String("state", string(state))))
+			}
+			return nil
+		}))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create instance gauge")
+	}
+
+	return supervisor, nil
+}
@@ -0,0 +1,198 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package supervisor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/constants"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// createSupervisorHelper returns a supervisor running the shell script for every instance. The script gets
+// the directory of the test as its first argument.
+func createSupervisorHelper(t *testing.T, cfg config.Supervisor, script string) (*Supervisor, string) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the supervisor tests run shell scripts")
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "instance.sh")
+	assert.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755))
+
+	logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+	supervisor, err := New(cfg, Command{Path: path, Args: []string{dir}, Env: os.Environ()}, logger, noop.NewMeterProvider().Meter("test"))
+	assert.NoError(t, err)
+	return supervisor, dir
+}
+
+func runSupervisorHelper(supervisor *Supervisor) (context.CancelFunc, chan error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- supervisor.Run(ctx)
+	}()
+	return cancel, done
+}
+
+func TestRun_StartsInstancesWithTheirIndex(t *testing.T) {
+	//arrange
+	supervisor, dir := createSupervisorHelper(t, config.Supervisor{Instances: 3, StartInterval: time.Millisecond}, `
+touch "$1/instance-$GAMELIFT_WRAPPER_INSTANCE_INDEX-of-$GAMELIFT_WRAPPER_INSTANCE_COUNT"
+trap 'exit 0' TERM
+while true; do sleep 0.1; done
+`)
+
+	//act
+	cancel, done := runSupervisorHelper(supervisor)
+
+	//assert
+	for i := range 3 {
+		assert.Eventually(t, func() bool {
+			_, err := os.Stat(filepath.Join(dir, fmt.Sprintf("instance-%d-of-3", i)))
+			return err == nil
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+	assert.Eventually(t, func() bool {
+		for _, status := range supervisor.Status() {
+			if status.State != StateRunning || status.Pid == 0 {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	assert.NoError(t, <-done)
+	for _, status := range supervisor.Status() {
+		assert.Equal(t, StateStopped, status.State)
+		assert.Equal(t, 0, status.Restarts)
+	}
+}
+
+func TestRun_RestartsExitedInstance(t *testing.T) {
+	//arrange
+	supervisor, _ := createSupervisorHelper(t, config.Supervisor{
+		Instances:       1,
+		RestartDelay:    time.Millisecond,
+		MaxRestartDelay: 10 * time.Millisecond,
+	}, `exit 3`)
+
+	//act
+	cancel, done := runSupervisorHelper(supervisor)
+
+	//assert
+	assert.Eventually(t, func() bool {
+		return supervisor.Status()[0].Restarts >= 2
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	assert.NoError(t, <-done)
+	assert.Equal(t, 3, supervisor.Status()[0].LastExitCode)
+}
+
+func TestRun_KillsInstanceIgnoringStop(t *testing.T) {
+	//arrange
+	supervisor, dir := createSupervisorHelper(t, config.Supervisor{Instances: 1, StopTimeout: 100 * time.Millisecond}, `
+trap '' TERM
+touch "$1/started"
+while true; do sleep 0.1; done
+`)
+	cancel, done := runSupervisorHelper(supervisor)
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(filepath.Join(dir, "started"))
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	//act
+	cancel()
+
+	//assert
+	select {
+	case err := <-done:
+		assert.ErrorContains(t, err, "1 wrapper instances were killed")
+	case <-time.After(5 * time.Second):
+		t.Fatal("supervisor did not stop")
+	}
+	assert.Equal(t, StateStopped, supervisor.Status()[0].State)
+}
+
+func TestRun_DoesNotRestartDrainedInstance(t *testing.T) {
+	//arrange
+	supervisor, _ := createSupervisorHelper(t, config.Supervisor{Instances: 2, RestartDelay: time.Millisecond},
+		fmt.Sprintf("exit %d", constants.ExitCodeDrained))
+
+	//act
+	cancel, done := runSupervisorHelper(supervisor)
+	defer cancel()
+
+	//assert
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("supervisor did not stop")
+	}
+	for _, status := range supervisor.Status() {
+		assert.Equal(t, StateDrained, status.State)
+		assert.Equal(t, 0, status.Restarts)
+	}
+}
+
+func TestDrain_SignalsRunningInstances(t *testing.T) {
+	//arrange
+	supervisor, dir := createSupervisorHelper(t, config.Supervisor{Instances: 1, RestartDelay: time.Millisecond}, `
+trap 'exit 0' USR1
+touch "$1/started"
+while true; do sleep 0.1; done
+`)
+	cancel, done := runSupervisorHelper(supervisor)
+	defer cancel()
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(filepath.Join(dir, "started"))
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	//act
+	supervisor.Drain()
+
+	//assert
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("supervisor did not stop")
+	}
+	assert.True(t, supervisor.Drained())
+}
+
+func TestNew_NoInstances(t *testing.T) {
+	//act
+	_, err := New(config.Supervisor{}, Command{Path: "wrapper"}, slog.Default(), noop.NewMeterProvider().Meter("test"))
+
+	//assert
+	assert.ErrorContains(t, err, "at least 1")
+}
+
+func TestWithDefaults(t *testing.T) {
+	//act
+	cfg := withDefaults(config.Supervisor{Instances: 2, RestartDelay: 2 * time.Minute})
+
+	//assert
+	assert.Equal(t, DefaultStartInterval, cfg.StartInterval)
+	assert.Equal(t, DefaultStopTimeout, cfg.StopTimeout)
+	assert.Equal(t, 2*time.Minute, cfg.MaxRestartDelay)
+	assert.Equal(t, DefaultStatusInterval, cfg.StatusInterval)
+}
@@ -0,0 +1,357 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package triggers
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// DefaultMetric is the counter incremented by metric rules that do not name one.
+	DefaultMetric = "game.log.triggers"
+	// DroppedMetric is the counter of the actions dropped because the action queue was full.
+	DroppedMetric = "game.log.triggers.dropped"
+
+	actionQueueSize = 256
+)
+
+// Actions are the wrapper operations log trigger rules can drive.
+type Actions interface {
+	MarkReady(ctx context.Context) error
+	MarkUnhealthy(ctx context.Context, reason string) error
+	AcceptPlayerSession(ctx context.Context, playerSessionId string) error
+	RemovePlayerSession(ctx context.Context, playerSessionId string) error
+	TerminateSession(ctx context.Context, reason string) error
+}
+
+// Match is a rule that matched a line of game output.
+type Match struct {
+	Rule     *Rule
+	Captures map[string]string
+}
+
+// Engine applies the log trigger rules to the game output and runs the matching actions.
+// It is safe for concurrent use.
+type Engine struct {
+	cfg     config.LogTriggers
+	actions Actions
+	logger  *slog.Logger
+	meter   metric.Meter
+
+	rules atomic.Pointer[[]*Rule]
+
+	mutex    sync.Mutex
+	counters map[string]metric.Int64Counter
+
+	queue   chan queuedMatch
+	dropped metric.Int64Counter
+}
+
+// queuedMatch is a match waiting for the worker started by Start.
+type queuedMatch struct {
+	ctx   context.Context
+	match Match
+}
+
+// Rules returns the rules currently applied.
+func (engine *Engine) Rules() []*Rule {
+	return *engine.rules.Load()
+}
+
+// Match returns the rules matching a line of the given stream, in rule order.
+//
+// Parameters:
+//   - stream: The stream the line was written to
+//   - line: The line, without its line ending
+//
+// Returns:
+//   - []Match: The matching rules and their named captures
+func (engine *Engine) Match(stream config.TriggerStream, line string) []Match {
+	var matches []Match
+	for _, rule := range engine.Rules() {
+		if captures, ok := rule.matches(stream, line); ok {
+			matches = append(matches, Match{Rule: rule, Captures: captures})
+		}
+	}
+	return matches
+}
+
+// Process runs the actions of the rules matching a line of game output.
+// Failed actions are logged, they do not stop the remaining rules.
+//
+// Parameters:
+//   - ctx: Context of the game session, its span receives the span events
+//   - stream: The stream the line was written to
+//   - line: The line, without its line ending
+func (engine *Engine) Process(ctx context.Context, stream config.TriggerStream, line string) {
+	for _, match := range engine.Match(stream, line) {
+		engine.logger.DebugContext(ctx, "log trigger matched", "rule", match.Rule.Name, "action", match.Rule.Action, "captures", match.Captures)
+		engine.runLogged(ctx, match)
+	}
+}
+
+// Enqueue applies the rules to a line of game output like Process, but only runs the metric and span event
+// actions directly. The other actions call the wrapper and Amazon GameLift, they are queued for the worker
+// started by Start so a slow call does not hold up the game output. When the queue is full, the action is
+// dropped and counted.
+//
+// Parameters:
+//   - ctx: Context of the game session, its span receives the span events
+//   - stream: The stream the line was written to
+//   - line: The line, without its line ending
+func (engine *Engine) Enqueue(ctx context.Context, stream config.TriggerStream, line string) {
+	for _, match := range engine.Match(stream, line) {
+		engine.logger.DebugContext(ctx, "log trigger matched", "rule", match.Rule.Name, "action", match.Rule.Action, "captures", match.Captures)
+		switch match.Rule.Action {
+		case config.TriggerActionMetric, config.TriggerActionSpanEvent:
+			engine.runLogged(ctx, match)
+			continue
+		}
+
+		select {
+		case engine.queue <- queuedMatch{ctx: ctx, match: match}:
+		default:
+			engine.dropped.Add(ctx, 1, metric.WithAttributes(attribute.String("rule", match.Rule.Name), attribute.String("action", string(match.Rule.Action))))
+			engine.logger.WarnContext(ctx, "log trigger action queue is full, dropping the action", "rule", match.Rule.Name, "action", match.Rule.Action)
+		}
+	}
+}
+
+// Start runs the actions queued by Enqueue, in order, until the context is done.
+//
+// Parameters:
+//   - ctx: Context bounding the worker
+func (engine *Engine) Start(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case queued := <-engine.queue:
+				engine.runLogged(queued.ctx, queued.match)
+			}
+		}
+	}()
+}
+
+// runLogged runs the action of a match, logging its failure.
+func (engine *Engine) runLogged(ctx context.Context, match Match) {
+	if err := engine.run(ctx, match); err != nil {
+		engine.logger.WarnContext(ctx, "log trigger action failed", "rule", match.Rule.Name, "action", match.Rule.Action, "err", err)
+	}
+}
+
+func (engine *Engine) run(ctx context.Context, match Match) error {
+	rule := match.Rule
+	reason := "log trigger rule '" + rule.Name + "' matched"
+
+	switch rule.Action {
+	case config.TriggerActionMetric:
+		return engine.count(ctx, match)
+	case config.TriggerActionSpanEvent:
+		trace.SpanFromContext(ctx).AddEvent(rule.Name, trace.WithAttributes(captureAttributes(match.Captures, nil)...))
+		return nil
+	}
+
+	if engine.actions == nil {
+		return errors.Errorf("action '%s' is not available", rule.Action)
+	}
+
+	switch rule.Action {
+	case config.TriggerActionMarkReady:
+		return engine.actions.MarkReady(ctx)
+	case config.TriggerActionMarkUnhealthy:
+		return engine.actions.MarkUnhealthy(ctx, reason)
+	case config.TriggerActionAcceptPlayerSession:
+		return engine.actions.AcceptPlayerSession(ctx, match.Captures[PlayerSessionIdCapture])
+	case config.TriggerActionRemovePlayerSession:
+		return engine.actions.RemovePlayerSession(ctx, match.Captures[PlayerSessionIdCapture])
+	case config.TriggerActionTerminateSession:
+		return engine.actions.TerminateSession(ctx, reason)
+	default:
+		return errors.Errorf("unknown action '%s'", rule.Action)
+	}
+}
+
+func (engine *Engine) count(ctx context.Context, match Match) error {
+	name := match.Rule.Metric
+	if len(name) == 0 {
+		name = DefaultMetric
+	}
+
+	engine.mutex.Lock()
+	counter, ok := engine.counters[name]
+	if !ok {
+		var err error
+		counter, err = engine.meter.Int64Counter(name, metric.WithDescription("Number of game output lines matching log trigger rules"))
+		if err != nil {
+			engine.mutex.Unlock()
+			return errors.Wrapf(err, "failed to create counter '%s'", name)
+		}
+		engine.counters[name] = counter
+	}
+	engine.mutex.Unlock()
+
+	attributes := captureAttributes(match.Captures, match.Rule.Attributes)
+	attributes = append(attributes, attribute.String("rule", match.Rule.Name))
+	counter.Add(ctx, 1, metric.WithAttributes(attributes...))
+	return nil
+}
+
+// captureAttributes converts the named captures to attributes, restricted to names when it is not nil.
+func captureAttributes(captures map[string]string, names []string) []attribute.KeyValue {
+	if names == nil {
+		names = make([]string, 0, len(captures))
+		for name := range captures {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	attributes := make([]attribute.KeyValue, 0, len(names))
+	for _, name := range names {
+		attributes = append(attributes, attribute.String(name, captures[name]))
+	}
+	return attributes
+}
+
+// Reload reads the rules again. The current rules are kept if the new rules are invalid.
+//
+// Returns:
+//   - error: Any error loading or compiling the rules
+func (engine *Engine) Reload() error {
+	rules, err := Load(engine.cfg)
+	if err != nil {
+		return err
+	}
+
+	engine.rules.Store(&rules)
+	return nil
+}
+
+// Watch reloads the rules whenever the rules file changes, until the context is done.
+//
+// Parameters:
+//   - ctx: Context bounding the watch
+//
+// Returns:
+//   - error: Any error setting up the watch, nil when there is no rules file
+func (engine *Engine) Watch(ctx context.Context) error {
+	if len(engine.cfg.RulesFile) == 0 {
+		return nil
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return errors.Wrap(err, "failed to create log trigger rules file watcher")
+	}
+
+	// watch the directory, editors often replace the file rather than writing to it
+	path := filepath.Clean(engine.cfg.RulesFile)
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		_ = watcher.Close()
+		return errors.Wrapf(err, "failed to watch log trigger rules file '%s'", path)
+	}
+
+	go func() {
+		defer watcher.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != path || !(event.Has(fsnotify.Write) || event.Has(fsnotify.Create)) {
+					continue
+				}
+				if err := engine.Reload(); err != nil {
+					engine.logger.WarnContext(ctx, "failed to reload log trigger rules, keeping the previous rules", "path", path, "err", err)
+					continue
+				}
+				engine.logger.InfoContext(ctx, "reloaded log trigger rules", "path", path, "rules", len(engine.Rules()))
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				engine.logger.WarnContext(ctx, "log trigger rules file watcher error", "path", path, "err", err)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Load compiles the inline rules followed by the rules of the rules file.
+//
+// Parameters:
+//   - cfg: The log trigger configuration
+//
+// Returns:
+//   - []*Rule: The compiled rules
+//   - error: Any error loading or compiling the rules
+func Load(cfg config.LogTriggers) ([]*Rule, error) {
+	rules := append([]config.TriggerRule{}, cfg.Rules...)
+	if len(cfg.RulesFile) != 0 {
+		fileRules, err := LoadFile(cfg.RulesFile)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, fileRules...)
+	}
+
+	return Compile(rules)
+}
+
+// New creates a log trigger engine with the configured rules.
+//
+// Parameters:
+//   - cfg: The log trigger configuration
+//   - actions: The wrapper operations the rules drive, nil to only allow metrics and span events
+//   - logger: Logger for matches and failed actions
+//   - meter: Meter for the metric action
+//
+// Returns:
+//   - *Engine: The engine
+//   - error: Any error loading or compiling the rules
+func New(cfg config.LogTriggers, actions Actions, logger *slog.Logger, meter metric.Meter) (*Engine, error) {
+	rules, err := Load(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	dropped, err := meter.Int64Counter(DroppedMetric, metric.WithDescription("Number of log trigger actions dropped because the action queue was full"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create counter '%s'", DroppedMetric)
+	}
+
+	engine := &Engine{
+		cfg:      cfg,
+		actions:  actions,
+		logger:   logger,
+		meter:    meter,
+		counters: make(map[string]metric.Int64Counter),
+		queue:    make(chan queuedMatch, actionQueueSize),
+		dropped:  dropped,
+	}
+	engine.rules.Store(&rules)
+
+	return engine, nil
+}
@@ -0,0 +1,207 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package triggers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+type actionsMock struct {
+	ready      int
+	unhealthy  []string
+	accepted   []string
+	removed    []string
+	terminated []string
+	err        error
+	block      chan struct{} // when set, MarkReady waits for it to be closed
+	readyCalls chan struct{} // when set, receives every MarkReady call
+}
+
+func (mock *actionsMock) MarkReady(ctx context.Context) error {
+	if mock.block != nil {
+		<-mock.block
+	}
+	mock.ready++
+	if mock.readyCalls != nil {
+		mock.readyCalls <- struct{}{}
+	}
+	return mock.err
+}
+
+func (mock *actionsMock) MarkUnhealthy(ctx context.Context, reason string) error {
+	mock.unhealthy = append(mock.unhealthy, reason)
+	return mock.err
+}
+
+func (mock *actionsMock) AcceptPlayerSession(ctx context.Context, playerSessionId string) error {
+	mock.accepted = append(mock.accepted, playerSessionId)
+	return mock.err
+}
+
+func (mock *actionsMock) RemovePlayerSession(ctx context.Context, playerSessionId string) error {
+	mock.removed = append(mock.removed, playerSessionId)
+	return mock.err
+}
+
+func (mock *actionsMock) TerminateSession(ctx context.Context, reason string) error {
+	mock.terminated = append(mock.terminated, reason)
+	return mock.err
+}
+
+type engineMockHelper struct {
+	engine    *Engine
+	actions   *actionsMock
+	logBuffer *bytes.Buffer
+}
+
+func createEngineMockHelper(t *testing.T, cfg config.LogTriggers) engineMockHelper {
+	logBuffer := bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(&logBuffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	actions := &actionsMock{}
+
+	engine, err := New(cfg, actions, logger, noop.NewMeterProvider().Meter("test"))
+	assert.NoError(t, err)
+
+	return engineMockHelper{engine: engine, actions: actions, logBuffer: &logBuffer}
+}
+
+func TestEngine_Process(t *testing.T) {
+	//arrange
+	helper := createEngineMockHelper(t, config.LogTriggers{Rules: []config.TriggerRule{
+		{Name: "ready", Pattern: "listening on port", Action: config.TriggerActionMarkReady},
+		{Name: "join", Pattern: "joined (?P<playerSessionId>\\S+)", Action: config.TriggerActionAcceptPlayerSession},
+		{Name: "leave", Pattern: "left (?P<playerSessionId>\\S+)", Action: config.TriggerActionRemovePlayerSession},
+		{Name: "crash", Stream: config.TriggerStreamStderr, Pattern: "FATAL", Action: config.TriggerActionMarkUnhealthy},
+		{Name: "over", Pattern: "match over", Action: config.TriggerActionTerminateSession},
+		{Name: "kills", Pattern: "kill weapon=(?P<weapon>\\w+)", Action: config.TriggerActionMetric, Metric: "game.kills", Attributes: []string{"weapon"}},
+		{Name: "map", Pattern: "loaded map (?P<map>\\w+)", Action: config.TriggerActionSpanEvent},
+	}})
+	ctx := context.Background()
+
+	//act
+	helper.engine.Process(ctx, config.TriggerStreamStdout, "listening on port 7777")
+	helper.engine.Process(ctx, config.TriggerStreamStdout, "joined psess-1")
+	helper.engine.Process(ctx, config.TriggerStreamStdout, "left psess-1")
+	helper.engine.Process(ctx, config.TriggerStreamStdout, "FATAL on stdout")
+	helper.engine.Process(ctx, config.TriggerStreamStderr, "FATAL out of memory")
+	helper.engine.Process(ctx, config.TriggerStreamStdout, "kill weapon=rocket")
+	helper.engine.Process(ctx, config.TriggerStreamStdout, "loaded map dust")
+	helper.engine.Process(ctx, config.TriggerStreamStdout, "match over")
+
+	//assert
+	assert.Equal(t, 1, helper.actions.ready)
+	assert.Equal(t, []string{"psess-1"}, helper.actions.accepted)
+	assert.Equal(t, []string{"psess-1"}, helper.actions.removed)
+	assert.Equal(t, []string{"log trigger rule 'crash' matched"}, helper.actions.unhealthy)
+	assert.Len(t, helper.actions.terminated, 1)
+	assert.NotContains(t, helper.logBuffer.String(), "log trigger action failed")
+	assert.Contains(t, helper.logBuffer.String(), "rule=kills")
+}
+
+func TestEngine_Process_ActionError(t *testing.T) {
+	//arrange
+	helper := createEngineMockHelper(t, config.LogTriggers{Rules: []config.TriggerRule{
+		{Name: "ready", Pattern: "ready", Action: config.TriggerActionMarkReady},
+		{Name: "ready-again", Pattern: "ready", Action: config.TriggerActionMarkReady},
+	}})
+	helper.actions.err = errors.New("Unit Test")
+
+	//act
+	helper.engine.Process(context.Background(), config.TriggerStreamStdout, "ready")
+
+	//assert
+	assert.Equal(t, 2, helper.actions.ready)
+	assert.Contains(t, helper.logBuffer.String(), "log trigger action failed")
+}
+
+func TestEngine_Enqueue(t *testing.T) {
+	//arrange
+	helper := createEngineMockHelper(t, config.LogTriggers{Rules: []config.TriggerRule{
+		{Name: "ready", Pattern: "ready", Action: config.TriggerActionMarkReady},
+	}})
+	helper.actions.block = make(chan struct{})
+	helper.actions.readyCalls = make(chan struct{}, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	helper.engine.Start(ctx)
+
+	//act
+	helper.engine.Enqueue(ctx, config.TriggerStreamStdout, "ready")
+	helper.engine.Enqueue(ctx, config.TriggerStreamStdout, "ready")
+	close(helper.actions.block)
+
+	//assert
+	for i := 0; i < 2; i++ {
+		select {
+		case <-helper.actions.readyCalls:
+		case <-time.After(5 * time.Second):
+			t.Fatal("queued action was not run")
+		}
+	}
+}
+
+func TestEngine_Enqueue_QueueFull(t *testing.T) {
+	//arrange
+	helper := createEngineMockHelper(t, config.LogTriggers{Rules: []config.TriggerRule{
+		{Name: "ready", Pattern: "ready", Action: config.TriggerActionMarkReady},
+	}})
+	helper.engine.queue = make(chan queuedMatch, 1)
+
+	//act
+	helper.engine.Enqueue(context.Background(), config.TriggerStreamStdout, "ready")
+	helper.engine.Enqueue(context.Background(), config.TriggerStreamStdout, "ready")
+
+	//assert
+	assert.Len(t, helper.engine.queue, 1)
+	assert.Equal(t, 0, helper.actions.ready)
+	assert.Equal(t, 1, bytes.Count(helper.logBuffer.Bytes(), []byte("log trigger action queue is full")))
+}
+
+func TestEngine_Reload(t *testing.T) {
+	//arrange
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte("rules:\n  - {name: a, pattern: first, action: mark-ready}\n"), 0644))
+	helper := createEngineMockHelper(t, config.LogTriggers{
+		RulesFile: path,
+		Rules:     []config.TriggerRule{{Name: "inline", Pattern: "inline", Action: config.TriggerActionMarkUnhealthy}},
+	})
+
+	//act
+	assert.NoError(t, os.WriteFile(path, []byte("rules:\n  - {name: b, pattern: second, action: mark-ready}\n"), 0644))
+	reloadErr := helper.engine.Reload()
+	matchesAfterReload := helper.engine.Match(config.TriggerStreamStdout, "second")
+	assert.NoError(t, os.WriteFile(path, []byte("rules:\n  - {name: c, pattern: '(', action: mark-ready}\n"), 0644))
+	invalidErr := helper.engine.Reload()
+
+	//assert
+	assert.NoError(t, reloadErr)
+	assert.Len(t, matchesAfterReload, 1)
+	assert.Equal(t, "b", matchesAfterReload[0].Rule.Name)
+	assert.ErrorContains(t, invalidErr, "invalid pattern")
+	assert.Len(t, helper.engine.Rules(), 2)
+	assert.Equal(t, "inline", helper.engine.Rules()[0].Name)
+	assert.Equal(t, "b", helper.engine.Rules()[1].Name)
+}
+
+func TestNew_InvalidRules(t *testing.T) {
+	//act
+	engine, err := New(config.LogTriggers{Rules: []config.TriggerRule{{Name: "a"}}}, nil, slog.Default(), noop.NewMeterProvider().Meter("test"))
+
+	//assert
+	assert.Nil(t, engine)
+	assert.Error(t, err)
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package triggers
+
+import (
+	"os"
+	"regexp"
+	"slices"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// PlayerSessionIdCapture is the named capture holding the player session id for the player session actions.
+const PlayerSessionIdCapture = "playerSessionId"
+
+// Rule is a compiled log trigger rule.
+type Rule struct {
+	config.TriggerRule
+	expression *regexp.Regexp
+}
+
+// RulesFile is the document read from a rules file.
+type RulesFile struct {
+	Rules []config.TriggerRule `yaml:"rules"`
+}
+
+// Compile validates the rules and compiles their patterns.
+//
+// Parameters:
+//   - rules: The configured rules
+//
+// Returns:
+//   - []*Rule: The compiled rules, in the configured order
+//   - error: An error describing the first invalid rule
+func Compile(rules []config.TriggerRule) ([]*Rule, error) {
+	compiled := make([]*Rule, 0, len(rules))
+	names := make(map[string]bool, len(rules))
+
+	for i, r := range rules {
+		if len(r.Name) == 0 {
+			return nil, errors.Errorf("log trigger rule %d has no name", i)
+		}
+		if names[r.Name] {
+			return nil, errors.Errorf("log trigger rule '%s' is defined more than once", r.Name)
+		}
+		names[r.Name] = true
+
+		switch r.Stream {
+		case "", config.TriggerStreamStdout, config.TriggerStreamStderr:
+		default:
+			return nil, errors.Errorf("log trigger rule '%s' has an unknown stream '%s'", r.Name, r.Stream)
+		}
+
+		if len(r.Pattern) == 0 {
+			return nil, errors.Errorf("log trigger rule '%s' has no pattern", r.Name)
+		}
+		expression, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "log trigger rule '%s' has an invalid pattern", r.Name)
+		}
+
+		captures := expression.SubexpNames()
+		switch r.Action {
+		case config.TriggerActionAcceptPlayerSession, config.TriggerActionRemovePlayerSession:
+			if !slices.Contains(captures, PlayerSessionIdCapture) {
+				return nil, errors.Errorf("log trigger rule '%s' needs a '%s' named capture for action '%s'", r.Name, PlayerSessionIdCapture, r.Action)
+			}
+		case config.TriggerActionMarkReady, config.TriggerActionMarkUnhealthy, config.TriggerActionMetric,
+			config.TriggerActionSpanEvent, config.TriggerActionTerminateSession:
+		default:
+			return nil, errors.Errorf("log trigger rule '%s' has an unknown action '%s'", r.Name, r.Action)
+		}
+
+		for _, attribute := range r.Attributes {
+			if !slices.Contains(captures, attribute) {
+				return nil, errors.Errorf("log trigger rule '%s' uses attribute '%s' which is not a named capture", r.Name, attribute)
+			}
+		}
+
+		compiled = append(compiled, &Rule{TriggerRule: r, expression: expression})
+	}
+
+	return compiled, nil
+}
+
+// LoadFile reads the rules from a YAML rules file.
+//
+// Parameters:
+//   - path: Path of the rules file
+//
+// Returns:
+//   - []config.TriggerRule: The rules in the file
+//   - error: Any error reading or parsing the file
+func LoadFile(path string) ([]config.TriggerRule, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read log trigger rules file '%s'", path)
+	}
+
+	rulesFile := RulesFile{}
+	if err := yaml.Unmarshal(b, &rulesFile); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse log trigger rules file '%s'", path)
+	}
+
+	return rulesFile.Rules, nil
+}
+
+// matches reports whether the rule applies to the line, returning its named captures.
+func (rule *Rule) matches(stream config.TriggerStream, line string) (map[string]string, bool) {
+	if len(rule.Stream) != 0 && rule.Stream != stream {
+		return nil, false
+	}
+
+	submatches := rule.expression.FindStringSubmatch(line)
+	if submatches == nil {
+		return nil, false
+	}
+
+	captures := make(map[string]string)
+	for i, name := range rule.expression.SubexpNames() {
+		if i != 0 && len(name) != 0 {
+			captures[name] = submatches[i]
+		}
+	}
+
+	return captures, true
+}
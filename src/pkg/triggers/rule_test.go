@@ -0,0 +1,99 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package triggers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompile_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		rules    []config.TriggerRule
+		expected string
+	}{
+		{"no name", []config.TriggerRule{{Pattern: "x", Action: config.TriggerActionMarkReady}}, "has no name"},
+		{"duplicate", []config.TriggerRule{
+			{Name: "a", Pattern: "x", Action: config.TriggerActionMarkReady},
+			{Name: "a", Pattern: "y", Action: config.TriggerActionMarkReady},
+		}, "defined more than once"},
+		{"stream", []config.TriggerRule{{Name: "a", Stream: "stdin", Pattern: "x", Action: config.TriggerActionMarkReady}}, "unknown stream"},
+		{"no pattern", []config.TriggerRule{{Name: "a", Action: config.TriggerActionMarkReady}}, "has no pattern"},
+		{"pattern", []config.TriggerRule{{Name: "a", Pattern: "(", Action: config.TriggerActionMarkReady}}, "invalid pattern"},
+		{"action", []config.TriggerRule{{Name: "a", Pattern: "x", Action: "explode"}}, "unknown action"},
+		{"player session capture", []config.TriggerRule{{Name: "a", Pattern: "joined (?P<id>\\S+)", Action: config.TriggerActionAcceptPlayerSession}}, "needs a 'playerSessionId' named capture"},
+		{"attribute", []config.TriggerRule{{Name: "a", Pattern: "map (?P<map>\\S+)", Action: config.TriggerActionMetric, Attributes: []string{"mode"}}}, "not a named capture"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			//act
+			rules, err := Compile(tt.rules)
+
+			//assert
+			assert.Nil(t, rules)
+			assert.ErrorContains(t, err, tt.expected)
+		})
+	}
+}
+
+func TestRule_Matches(t *testing.T) {
+	//arrange
+	rules, err := Compile([]config.TriggerRule{{
+		Name:    "join",
+		Stream:  config.TriggerStreamStdout,
+		Pattern: "player (?P<playerSessionId>psess-\\S+) joined",
+		Action:  config.TriggerActionAcceptPlayerSession,
+	}})
+	assert.NoError(t, err)
+
+	//act
+	captures, ok := rules[0].matches(config.TriggerStreamStdout, "12:00 player psess-123 joined")
+	_, okStderr := rules[0].matches(config.TriggerStreamStderr, "12:00 player psess-123 joined")
+	_, okOther := rules[0].matches(config.TriggerStreamStdout, "12:00 player left")
+
+	//assert
+	assert.True(t, ok)
+	assert.Equal(t, map[string]string{PlayerSessionIdCapture: "psess-123"}, captures)
+	assert.False(t, okStderr)
+	assert.False(t, okOther)
+}
+
+func TestLoadFile(t *testing.T) {
+	//arrange
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(`rules:
+  - name: ready
+    pattern: "Server listening"
+    action: mark-ready
+  - name: crash
+    stream: stderr
+    pattern: "FATAL"
+    action: mark-unhealthy
+`), 0644))
+
+	//act
+	rules, err := LoadFile(path)
+
+	//assert
+	assert.NoError(t, err)
+	assert.Len(t, rules, 2)
+	assert.Equal(t, config.TriggerActionMarkReady, rules[0].Action)
+	assert.Equal(t, config.TriggerStreamStderr, rules[1].Stream)
+}
+
+func TestLoadFile_Missing(t *testing.T) {
+	//act
+	_, err := LoadFile(filepath.Join(t.TempDir(), "rules.yaml"))
+
+	//assert
+	assert.ErrorContains(t, err, "failed to read log trigger rules file")
+}
@@ -6,14 +6,19 @@
 package events
 
 import (
+	"log/slog"
+
 	"github.com/amazon-gamelift/amazon-gamelift-servers-game-server-wrapper/pkg/config"
 )
 
 // HostingStart represents the initialization configuration for a game server instance.
 type HostingStart struct {
+	CertificatePath           string // path of the compute TLS certificate, empty when not available
 	CliArgs                   []config.CliArg
+	ComputeHostname           string // hostname the compute TLS certificate is issued for
 	ContainerPort             int
 	DNSName                   string
+	EnvVars                   map[string]string // set in the game process environment, overriding the wrapper's
 	FleetId                   string
 	GamePort                  int
 	GameProperties            string
@@ -26,3 +31,23 @@ type HostingStart struct {
 	MaximumPlayerSessionCount int
 	Provider                  config.Provider
 }
+
+// redactedHostingStart has the fields of HostingStart without its LogValue method.
+type redactedHostingStart HostingStart
+
+// LogValue keeps the values of the environment variables, which can be credentials, out of the logs.
+func (hostingStart *HostingStart) LogValue() slog.Value {
+	if hostingStart == nil {
+		return slog.AnyValue(nil)
+	}
+
+	redacted := redactedHostingStart(*hostingStart)
+	if len(redacted.EnvVars) != 0 {
+		redacted.EnvVars = make(map[string]string, len(hostingStart.EnvVars))
+		for key := range hostingStart.EnvVars {
+			redacted.EnvVars[key] = "<REDACTED>"
+		}
+	}
+
+	return slog.AnyValue(redacted)
+}
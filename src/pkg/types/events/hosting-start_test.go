@@ -0,0 +1,33 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package events
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHostingStart_LogValue_RedactsEnvVars(t *testing.T) {
+	//arrange
+	logBuffer := bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(&logBuffer, nil))
+	hostingStart := &HostingStart{
+		GameSessionId: "gsess-1",
+		EnvVars:       map[string]string{"AWS_CONTAINER_AUTHORIZATION_TOKEN": "secret"},
+	}
+
+	//act
+	logger.Info("hosting start", "event", hostingStart)
+
+	//assert
+	assert.Contains(t, logBuffer.String(), "gsess-1")
+	assert.Contains(t, logBuffer.String(), "AWS_CONTAINER_AUTHORIZATION_TOKEN")
+	assert.NotContains(t, logBuffer.String(), "secret")
+	assert.Equal(t, "secret", hostingStart.EnvVars["AWS_CONTAINER_AUTHORIZATION_TOKEN"])
+}
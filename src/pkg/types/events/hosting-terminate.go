@@ -5,17 +5,21 @@
 
 package events
 
+import "time"
+
 // HostingTerminateReason represents the reason for a game server termination event.
 type HostingTerminateReason string
 
 const (
-	HostingTerminateReasonUnspecified     HostingTerminateReason = "Unspecified"
-	HostingTerminateReasonContextExpiry   HostingTerminateReason = "ContextExpired"
-	HostingTerminateReasonHostingShutdown HostingTerminateReason = "HostingShutdown"
+	HostingTerminateReasonUnspecified       HostingTerminateReason = "Unspecified"
+	HostingTerminateReasonContextExpiry     HostingTerminateReason = "ContextExpired"
+	HostingTerminateReasonHostingShutdown   HostingTerminateReason = "HostingShutdown"
+	HostingTerminateReasonHealthCheckFailed HostingTerminateReason = "HealthCheckFailed"
 )
 
 // HostingTerminate represents a termination event for a game server instance.
-// It includes the reason for termination.
+// It includes the reason for termination and, when the hosting provides it, the time the process is shut down.
 type HostingTerminate struct {
-	Reason HostingTerminateReason
+	Reason          HostingTerminateReason
+	TerminationTime time.Time // zero when the termination time is not known
 }